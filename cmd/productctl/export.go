@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"product-microservice/internal/productio"
+	pb "product-microservice/proto/product"
+)
+
+// runExport streams the catalog from ExportProducts into a CSV, JSON Lines or
+// Parquet file, or to stdout
+func runExport(client pb.ProductServiceClient, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := flags.String("format", "", "file format: csv, jsonl or parquet (default: from the output extension, csv for stdout)")
+	output := flags.String("o", "", "output file (default: stdout)")
+	productType := flags.String("type", "", "only export products of this type: digital, physical or subscription")
+	showDeleted := flags.Bool("show-deleted", false, "include soft deleted products")
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		return errors.New("unexpected arguments")
+	}
+
+	format := productio.FormatCSV
+	var err error
+	switch {
+	case *formatName != "":
+		format, err = productio.ParseFormat(*formatName)
+	case *output != "":
+		format, err = productio.FormatFromPath(*output)
+	}
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	buffered := bufio.NewWriter(out)
+
+	count, err := export(client, buffered, format, &pb.ExportProductsRequest{
+		Type:        *productType,
+		ShowDeleted: *showDeleted,
+	})
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		if *output != "" {
+			// Do not leave a truncated snapshot behind
+			os.Remove(*output)
+		}
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d products\n", count)
+	return nil
+}
+
+// export writes every product received from the stream and returns how many
+// were written
+func export(client pb.ProductServiceClient, out io.Writer, format productio.Format, req *pb.ExportProductsRequest) (int, error) {
+	writer, err := productio.NewWriter(format, out)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.ExportProducts(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for {
+		exported, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, err
+		}
+		if err := writer.Write(exported); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, writer.Close()
+}
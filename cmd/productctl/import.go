@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"product-microservice/internal/productio"
+	pb "product-microservice/proto/product"
+)
+
+// importChunkSize is the amount of file data sent per stream message
+const importChunkSize = 64 * 1024
+
+var importFormats = map[productio.Format]pb.ImportFormat{
+	productio.FormatCSV:   pb.ImportFormat_IMPORT_FORMAT_CSV,
+	productio.FormatJSONL: pb.ImportFormat_IMPORT_FORMAT_JSONL,
+}
+
+// runImport streams a catalog file to ImportProducts and prints the summary
+func runImport(client pb.ProductServiceClient, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := flags.String("format", "", "file format: csv or jsonl (default: from the file extension)")
+	verbose := flags.Bool("v", false, "print the outcome of every row, not only failures")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one FILE argument")
+	}
+	path := flags.Arg(0)
+
+	format, err := productio.FormatFromPath(path)
+	if *formatName != "" {
+		format, err = productio.ParseFormat(*formatName)
+	}
+	if err != nil {
+		return err
+	}
+	wireFormat, ok := importFormats[format]
+	if !ok {
+		return fmt.Errorf("format %q cannot be imported", format)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	stream, err := client.ImportProducts(context.Background())
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.ImportProductsRequest{Payload: &pb.ImportProductsRequest_Format{Format: wireFormat}}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, importChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			chunk := &pb.ImportProductsRequest{Payload: &pb.ImportProductsRequest_Data{Data: buf[:n]}}
+			if err := stream.Send(chunk); err != nil {
+				// The server ended the stream; its error is reported by CloseAndRecv
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	for _, row := range summary.GetRows() {
+		switch {
+		case row.GetOutcome() == pb.ImportRowResult_FAILED:
+			fmt.Printf("line %d (%s): %s\n", row.GetLine(), row.GetExternalSku(), row.GetError())
+		case *verbose:
+			fmt.Printf("line %d (%s): %s %s\n", row.GetLine(), row.GetExternalSku(), row.GetOutcome(), row.GetProductId())
+		}
+	}
+	fmt.Printf("created: %d, updated: %d, failed: %d\n", summary.GetCreated(), summary.GetUpdated(), summary.GetFailed())
+	return nil
+}
@@ -0,0 +1,93 @@
+// Command productctl is a command line client for the product service.
+//
+// Usage:
+//
+//	productctl [-addr host:port] [-admin-token TOKEN] <command> [flags] [args]
+//
+// Commands:
+//
+//	import   Bulk import products from a CSV or JSON Lines file
+//	export   Export the catalog to a CSV, JSON Lines or Parquet file
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	pb "product-microservice/proto/product"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// adminToken sends the service's admin token as x-admin-token metadata on
+// every call, so exports include products that are not published
+type adminToken string
+
+func (t adminToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"x-admin-token": string(t)}, nil
+}
+
+func (t adminToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// command is a productctl subcommand
+type command struct {
+	usage string
+	run   func(client pb.ProductServiceClient, args []string) error
+}
+
+var commands = map[string]command{
+	"import": {usage: "import [-format csv|jsonl] FILE", run: runImport},
+	"export": {usage: "export [-format csv|jsonl|parquet] [-type TYPE] [-show-deleted] [-o FILE]", run: runExport},
+}
+
+func main() {
+	log.SetFlags(0)
+
+	defaultAddr := os.Getenv("PRODUCT_SERVICE_ADDR")
+	if defaultAddr == "" {
+		defaultAddr = "localhost:50051"
+	}
+	addr := flag.String("addr", defaultAddr, "address of the product service")
+	token := flag.String("admin-token", os.Getenv("PRODUCT_SERVICE_ADMIN_TOKEN"), "admin token of the product service")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		log.Printf("unknown command %q", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	options := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if *token != "" {
+		options = append(options, grpc.WithPerRPCCredentials(adminToken(*token)))
+	}
+	conn, err := grpc.NewClient(*addr, options...)
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", *addr, err)
+	}
+	defer conn.Close()
+
+	if err := cmd.run(pb.NewProductServiceClient(conn), flag.Args()[1:]); err != nil {
+		log.Fatalf("%s: %v", flag.Arg(0), err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: productctl [-addr host:port] [-admin-token TOKEN] <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", cmd.usage)
+	}
+}
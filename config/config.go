@@ -16,6 +16,17 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 	GRPCPort   string
+	// Product media are stored below MediaDir and served over HTTP on
+	// MediaHTTPPort at MediaBaseURL. Media uploads are disabled without MediaDir.
+	MediaDir      string
+	MediaBaseURL  string
+	MediaHTTPPort string
+	// AdminToken lets callers that send it as x-admin-token list products
+	// that are not published
+	AdminToken string
+	// ShippingRatesFile is the JSON file with the shipping zones and carrier
+	// rates. Shipping estimates are disabled without it.
+	ShippingRatesFile string
 }
 
 // LoadConfig loads environment variables from .env
@@ -24,7 +35,7 @@ func LoadConfig() *Config {
 	if err := godotenv.Load("./.env"); err != nil {
 		log.Println("No .env file found, relying on system environment variables")
 	}
- 
+
 	// Check for missing environment variables
 	dbHost := os.Getenv("DB_HOST")
 	if dbHost == "" {
@@ -61,14 +72,36 @@ func LoadConfig() *Config {
 		log.Fatal("GRPC_PORT is required but not set in the environment")
 	}
 
+	// Media storage is optional
+	mediaDir := os.Getenv("MEDIA_DIR")
+	mediaHTTPPort := os.Getenv("MEDIA_HTTP_PORT")
+	if mediaHTTPPort == "" {
+		mediaHTTPPort = "8080"
+	}
+	mediaBaseURL := os.Getenv("MEDIA_BASE_URL")
+	if mediaBaseURL == "" {
+		mediaBaseURL = "http://localhost:" + mediaHTTPPort + "/media/"
+	}
+
+	// Without an admin token every caller only sees published products
+	adminToken := os.Getenv("ADMIN_TOKEN")
+
+	// Shipping estimates are optional
+	shippingRatesFile := os.Getenv("SHIPPING_RATES_FILE")
+
 	// Return the config
 	return &Config{
-		DBHost:     dbHost,
-		DBPort:     dbPort,
-		DBUser:     dbUser,
-		DBPassword: dbPassword,
-		DBName:     dbName,
-		DBSSLMode:  dbsslMode,
-		GRPCPort:   grpcPort,
+		DBHost:            dbHost,
+		DBPort:            dbPort,
+		DBUser:            dbUser,
+		DBPassword:        dbPassword,
+		DBName:            dbName,
+		DBSSLMode:         dbsslMode,
+		GRPCPort:          grpcPort,
+		MediaDir:          mediaDir,
+		MediaBaseURL:      mediaBaseURL,
+		MediaHTTPPort:     mediaHTTPPort,
+		AdminToken:        adminToken,
+		ShippingRatesFile: shippingRatesFile,
 	}
 }
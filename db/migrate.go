@@ -0,0 +1,314 @@
+package db
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// migrations holds schema changes that AutoMigrate cannot express, such as
+// extensions, generated columns and specialised indexes. Every statement must
+// be idempotent because they run on every start-up, after AutoMigrate.
+var migrations = []string{
+	// Full-text and fuzzy product search
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(description, '')), 'B')
+		) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector)`,
+	`CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops)`,
+
+	// Prices used to be float columns. Move them into the NUMERIC amount
+	// columns of domain.Money without going through a float, keeping the
+	// currency default (USD) for existing rows.
+	moveLegacyPrice("products", "price", "price_amount"),
+	moveLegacyPrice("subscription_products", "renewal_price", "renewal_price_amount"),
+	moveLegacyPrice("subscription_plans", "price", "price_amount"),
+	`CREATE INDEX IF NOT EXISTS idx_products_price_id ON products (price_amount, id)`,
+
+	// Price list entries for the same product or plan, region and currency
+	// must not overlap in time, so at most one applies at any moment
+	`CREATE EXTENSION IF NOT EXISTS btree_gist`,
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'price_list_entries_no_overlap') THEN
+			ALTER TABLE price_list_entries ADD CONSTRAINT price_list_entries_no_overlap EXCLUDE USING gist (
+				product_id WITH =,
+				(coalesce(subscription_plan_id, '')) WITH =,
+				region WITH =,
+				price_currency WITH =,
+				tstzrange(effective_from, effective_to) WITH &&
+			);
+		END IF;
+	END
+	$$`,
+
+	// Price history. Every price written to a product or plan is appended,
+	// tagged with the scheduled change that wrote it if the price scheduler
+	// set app.scheduled_price_change_id. Targets that predate the history
+	// start with their price at the time the history was introduced.
+	`CREATE OR REPLACE FUNCTION record_price_change() RETURNS trigger AS $$
+	DECLARE
+		target_product_id text := NEW.id;
+		target_plan_id text;
+	BEGIN
+		IF TG_OP = 'UPDATE' AND (OLD.price_amount, OLD.price_currency) IS NOT DISTINCT FROM (NEW.price_amount, NEW.price_currency) THEN
+			RETURN NULL;
+		END IF;
+		IF TG_TABLE_NAME = 'subscription_plans' THEN
+			target_product_id := NEW.product_id;
+			target_plan_id := NEW.id;
+		END IF;
+		INSERT INTO price_history (product_id, subscription_plan_id, price_amount, price_currency, scheduled_price_change_id, changed_at)
+		VALUES (target_product_id, target_plan_id, NEW.price_amount, NEW.price_currency,
+			NULLIF(current_setting('app.scheduled_price_change_id', true), ''), now());
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE TRIGGER products_record_price_change AFTER INSERT OR UPDATE OF price_amount, price_currency ON products
+		FOR EACH ROW EXECUTE FUNCTION record_price_change()`,
+	`CREATE OR REPLACE TRIGGER subscription_plans_record_price_change AFTER INSERT OR UPDATE OF price_amount, price_currency ON subscription_plans
+		FOR EACH ROW EXECUTE FUNCTION record_price_change()`,
+	`CREATE OR REPLACE FUNCTION reject_price_history_change() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'price_history is append-only';
+	END
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE TRIGGER price_history_append_only BEFORE UPDATE OR DELETE ON price_history
+		FOR EACH ROW EXECUTE FUNCTION reject_price_history_change()`,
+	`INSERT INTO price_history (product_id, price_amount, price_currency, changed_at)
+		SELECT p.id, p.price_amount, p.price_currency, now() FROM products p
+		WHERE NOT EXISTS (SELECT 1 FROM price_history h WHERE h.product_id = p.id AND h.subscription_plan_id IS NULL)`,
+	`INSERT INTO price_history (product_id, subscription_plan_id, price_amount, price_currency, changed_at)
+		SELECT s.product_id, s.id, s.price_amount, s.price_currency, now() FROM subscription_plans s
+		WHERE NOT EXISTS (SELECT 1 FROM price_history h WHERE h.subscription_plan_id = s.id)`,
+
+	// Product change log for WatchProducts. A product exists while it is not
+	// soft deleted: becoming visible is recorded as created, disappearing as
+	// deleted, and changes to deleted products are not recorded at all.
+	// Needs PostgreSQL 14 or later.
+	`CREATE OR REPLACE FUNCTION record_product_event() RETURNS trigger AS $$
+	DECLARE
+		was_live boolean := false;
+		is_live boolean := false;
+		row_id text;
+	BEGIN
+		IF TG_OP IN ('UPDATE', 'DELETE') THEN
+			was_live := OLD.deleted_at IS NULL;
+			row_id := OLD.id;
+		END IF;
+		IF TG_OP IN ('INSERT', 'UPDATE') THEN
+			is_live := NEW.deleted_at IS NULL;
+			row_id := NEW.id;
+		END IF;
+		IF was_live OR is_live THEN
+			INSERT INTO product_events (transaction_id, resource, resource_id, product_id, type, created_at)
+			VALUES (pg_current_xact_id()::text::bigint, 'product', row_id, row_id,
+				CASE WHEN NOT was_live THEN 'created' WHEN NOT is_live THEN 'deleted' ELSE 'updated' END,
+				now());
+		END IF;
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE TRIGGER products_record_event AFTER INSERT OR UPDATE OR DELETE ON products
+		FOR EACH ROW EXECUTE FUNCTION record_product_event()`,
+	`CREATE OR REPLACE FUNCTION record_subscription_plan_event() RETURNS trigger AS $$
+	BEGIN
+		IF TG_OP = 'DELETE' THEN
+			INSERT INTO product_events (transaction_id, resource, resource_id, product_id, type, created_at)
+			VALUES (pg_current_xact_id()::text::bigint, 'subscription_plan', OLD.id, OLD.product_id, 'deleted', now());
+		ELSE
+			INSERT INTO product_events (transaction_id, resource, resource_id, product_id, type, created_at)
+			VALUES (pg_current_xact_id()::text::bigint, 'subscription_plan', NEW.id, NEW.product_id,
+				CASE TG_OP WHEN 'INSERT' THEN 'created' ELSE 'updated' END, now());
+		END IF;
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE TRIGGER subscription_plans_record_event AFTER INSERT OR UPDATE OR DELETE ON subscription_plans
+		FOR EACH ROW EXECUTE FUNCTION record_subscription_plan_event()`,
+
+	// Categories: subtree lookups are prefix matches on the materialized path,
+	// and siblings cannot share a name
+	`CREATE INDEX IF NOT EXISTS idx_categories_path_pattern ON categories (path text_pattern_ops)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_sibling_name ON categories (coalesce(parent_id, ''), lower(name))`,
+	`CREATE INDEX IF NOT EXISTS idx_products_tags ON products USING GIN (tags)`,
+
+	// A variant price override has both an amount and a currency, or neither
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'product_variants_price_override_complete') THEN
+			ALTER TABLE product_variants ADD CONSTRAINT product_variants_price_override_complete
+				CHECK ((price_override_amount IS NULL) = (price_override_currency IS NULL));
+		END IF;
+	END
+	$$`,
+
+	// An attribute definition applies to a product type or to a category,
+	// never both; attribute filters are containment queries
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'attribute_definitions_one_scope') THEN
+			ALTER TABLE attribute_definitions ADD CONSTRAINT attribute_definitions_one_scope
+				CHECK (num_nonnulls(product_type, category_id) = 1);
+		END IF;
+	END
+	$$`,
+	`CREATE INDEX IF NOT EXISTS idx_products_attributes ON products USING GIN (attributes jsonb_path_ops)`,
+
+	// Localized search. Translations can be in any language, so they are
+	// indexed with the 'simple' configuration, which does not stem.
+	`ALTER TABLE product_translations ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+		) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_product_translations_search_vector ON product_translations USING GIN (search_vector)`,
+	`CREATE INDEX IF NOT EXISTS idx_product_translations_name_trgm ON product_translations USING GIN (name gin_trgm_ops)`,
+
+	// Products are in one of the lifecycle states, and the publish scheduler
+	// looks up due publications by state
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'products_status_valid') THEN
+			ALTER TABLE products ADD CONSTRAINT products_status_valid
+				CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+		END IF;
+	END
+	$$`,
+	`CREATE INDEX IF NOT EXISTS idx_products_publish_due ON products (publish_at) WHERE status = 'in_review' AND publish_at IS NOT NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_products_unpublish_due ON products (unpublish_at) WHERE status = 'published' AND unpublish_at IS NOT NULL`,
+
+	// Bundles have a known pricing rule and contain at least one of each component
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'bundle_products_pricing_valid') THEN
+			ALTER TABLE bundle_products ADD CONSTRAINT bundle_products_pricing_valid
+				CHECK (pricing IN ('fixed', 'percent_off') AND discount_percent BETWEEN 0 AND 100);
+		END IF;
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'bundle_components_quantity_positive') THEN
+			ALTER TABLE bundle_components ADD CONSTRAINT bundle_components_quantity_positive CHECK (quantity > 0);
+		END IF;
+	END
+	$$`,
+
+	// Inventory. An item has one stock level per warehouse, which never goes
+	// negative nor reserves more than is on hand.
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_levels_item ON stock_levels (product_id, coalesce(variant_id, ''), warehouse_id)`,
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'stock_levels_counts_valid') THEN
+			ALTER TABLE stock_levels ADD CONSTRAINT stock_levels_counts_valid
+				CHECK (on_hand >= 0 AND reserved >= 0 AND reserved <= on_hand);
+		END IF;
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'stock_reservations_valid') THEN
+			ALTER TABLE stock_reservations ADD CONSTRAINT stock_reservations_valid
+				CHECK (quantity > 0 AND status IN ('pending', 'committed', 'released', 'expired'));
+		END IF;
+	END
+	$$`,
+	`CREATE INDEX IF NOT EXISTS idx_stock_movements_product ON stock_movements (product_id, id DESC)`,
+
+	// Warehouses. Stock recorded before warehouses existed gets an active
+	// placeholder warehouse for each warehouse ID it uses, to be named and
+	// given an address through UpdateWarehouse.
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouses_name ON warehouses (lower(name))`,
+	`INSERT INTO warehouses (id, name, time_zone, priority, active, version, created_at, updated_at)
+		SELECT warehouse_id, 'Warehouse ' || warehouse_id, 'UTC', 0, true, 1, now(), now()
+		FROM (SELECT warehouse_id FROM stock_levels UNION SELECT warehouse_id FROM stock_reservations) used
+		ON CONFLICT (id) DO NOTHING`,
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_stock_levels_warehouse') THEN
+			ALTER TABLE stock_levels ADD CONSTRAINT fk_stock_levels_warehouse
+				FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON DELETE CASCADE;
+		END IF;
+	END
+	$$`,
+
+	// Physical products used to have a unitless float weight, taken to be in
+	// kilograms, and free-text dimensions. Dimensions like "10x20x30" or
+	// "10 x 20 x 30 in" are parsed, in centimeters unless a unit says
+	// otherwise; anything else is kept in legacy_dimensions for review.
+	`DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'physical_products' AND column_name = 'weight') THEN
+			UPDATE physical_products SET weight_value = weight, weight_unit = 'kg' WHERE weight IS NOT NULL AND weight <> 0;
+			ALTER TABLE physical_products DROP COLUMN weight;
+		END IF;
+		IF EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'physical_products' AND column_name = 'dimensions') THEN
+			UPDATE physical_products p SET
+				dimensions_length = replace(m[1], ',', '.')::double precision / CASE m[4] WHEN 'mm' THEN 10 ELSE 1 END,
+				dimensions_width = replace(m[2], ',', '.')::double precision / CASE m[4] WHEN 'mm' THEN 10 ELSE 1 END,
+				dimensions_height = replace(m[3], ',', '.')::double precision / CASE m[4] WHEN 'mm' THEN 10 ELSE 1 END,
+				dimensions_unit = CASE WHEN m[4] IN ('in', 'inch', 'inches', '"') THEN 'in' ELSE 'cm' END,
+				dimensions = NULL
+			FROM (
+				SELECT id, regexp_match(lower(dimensions),
+					'^\s*(\d+(?:[.,]\d+)?)\s*[x×*]\s*(\d+(?:[.,]\d+)?)\s*[x×*]\s*(\d+(?:[.,]\d+)?)\s*(mm|cm|in|inch|inches|")?\s*$') AS m
+				FROM physical_products
+			) parsed
+			WHERE parsed.id = p.id AND parsed.m IS NOT NULL
+				AND replace(parsed.m[1], ',', '.')::double precision > 0
+				AND replace(parsed.m[2], ',', '.')::double precision > 0
+				AND replace(parsed.m[3], ',', '.')::double precision > 0;
+			UPDATE physical_products SET dimensions = NULL WHERE trim(dimensions) = '';
+			ALTER TABLE physical_products RENAME COLUMN dimensions TO legacy_dimensions;
+		END IF;
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'physical_products_units_valid') THEN
+			ALTER TABLE physical_products ADD CONSTRAINT physical_products_units_valid
+				CHECK (coalesce(weight_unit, '') IN ('', 'g', 'kg', 'lb', 'oz') AND coalesce(dimensions_unit, '') IN ('', 'cm', 'in'));
+		END IF;
+	END
+	$$`,
+
+	// Variant weight overrides used to be a unitless float in the unit of the
+	// product weight. They keep the unit the product has now, or kilograms
+	// like the legacy product weight if it has none.
+	`DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'product_variants' AND column_name = 'weight_override') THEN
+			UPDATE product_variants v SET
+				weight_override_value = v.weight_override::text::double precision,
+				weight_override_unit = coalesce(nullif(pp.weight_unit, ''), 'kg')
+			FROM products p LEFT JOIN physical_products pp ON pp.id = p.physical_product_id
+			WHERE p.id = v.product_id AND v.weight_override IS NOT NULL;
+			ALTER TABLE product_variants DROP COLUMN weight_override;
+		END IF;
+		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'product_variants_weight_override_valid') THEN
+			ALTER TABLE product_variants ADD CONSTRAINT product_variants_weight_override_valid
+				CHECK ((weight_override_value IS NULL) = (weight_override_unit IS NULL)
+					AND coalesce(weight_override_unit, 'kg') IN ('g', 'kg', 'lb', 'oz'));
+		END IF;
+	END
+	$$`,
+}
+
+// moveLegacyPrice copies a legacy float price column into its NUMERIC
+// replacement and drops it, if it still exists
+func moveLegacyPrice(table, legacyColumn, amountColumn string) string {
+	return fmt.Sprintf(`DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = '%[1]s' AND column_name = '%[2]s') THEN
+			UPDATE %[1]s SET %[3]s = %[2]s::text::numeric WHERE %[2]s IS NOT NULL;
+			ALTER TABLE %[1]s DROP COLUMN %[2]s;
+		END IF;
+	END
+	$$`, table, legacyColumn, amountColumn)
+}
+
+// Migrate applies the raw SQL migrations in order
+func Migrate(database *gorm.DB) error {
+	for _, statement := range migrations {
+		if err := database.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to run migration %q: %w", statement, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+// Package blobstore keeps the content of uploaded files, such as product
+// images, behind a small interface so the backing storage can be swapped.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrInvalidKey is returned for keys that are empty or escape the store
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store saves and removes blobs addressed by slash separated keys such as
+// "products/<id>/<media id>.png"
+type Store interface {
+	// Put stores everything read from r under key, replacing any blob with
+	// the same key, and returns the URL clients download it from. A failed
+	// Put leaves nothing behind.
+	Put(ctx context.Context, key string, r io.Reader) (string, error)
+	// Delete removes the blob under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
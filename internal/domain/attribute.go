@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttributeType is the kind of value a custom attribute holds
+type AttributeType string
+
+const (
+	AttributeTypeString AttributeType = "string"
+	AttributeTypeNumber AttributeType = "number"
+	AttributeTypeBool   AttributeType = "bool"
+	// AttributeTypeEnum attributes hold one of the definition's EnumValues
+	AttributeTypeEnum AttributeType = "enum"
+)
+
+// EnumValues lists the values an enum attribute allows, stored as a JSON array
+type EnumValues []string
+
+// Value implements driver.Valuer
+func (e EnumValues) Value() (driver.Value, error) {
+	return stringListValue(e)
+}
+
+// Scan implements sql.Scanner
+func (e *EnumValues) Scan(src interface{}) error {
+	return scanJSON(src, e)
+}
+
+// Contains reports whether value is one of the allowed values
+func (e EnumValues) Contains(value string) bool {
+	for _, allowed := range e {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AttributeDefinition declares a custom product attribute, such as
+// "screen_size" in inches. It applies either to every product of one type or
+// to the products in one category and its subcategories.
+type AttributeDefinition struct {
+	ID uuid.UUID `gorm:"primaryKey"`
+	// Name is the key of the attribute in Product.Attributes, unique across definitions
+	Name        string `gorm:"not null;uniqueIndex"`
+	Description string
+	Type        AttributeType `gorm:"type:text;not null"`
+	// Required attributes must be set on every product the definition applies to
+	Required bool `gorm:"not null;default:false"`
+	// Unit of a number attribute, such as "cm"; only informational
+	Unit       string
+	EnumValues EnumValues `gorm:"type:jsonb;not null;default:'[]'"`
+	// Exactly one of ProductType and CategoryID is set, which db.Migrate enforces
+	ProductType *string    `gorm:"index"`
+	CategoryID  *uuid.UUID `gorm:"index"`
+	// Category is only declared for the foreign key, which keeps categories
+	// with attribute definitions from being deleted
+	Category  *Category `gorm:"constraint:OnDelete:RESTRICT"`
+	Version   int64     `gorm:"not null;default:1"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BeforeCreate assigns the ID and initial version of a new definition
+func (d *AttributeDefinition) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	d.Version = 1
+	return nil
+}
+
+// CheckValue reports whether value is valid for the attribute. Numbers are
+// float64, as decoded from JSON.
+func (d *AttributeDefinition) CheckValue(value interface{}) error {
+	switch d.Type {
+	case AttributeTypeString:
+		if _, ok := value.(string); ok {
+			return nil
+		}
+	case AttributeTypeNumber:
+		if number, ok := value.(float64); ok {
+			if math.IsNaN(number) || math.IsInf(number, 0) {
+				return fmt.Errorf("%w: attribute %q must be a finite number", ErrInvalidArgument, d.Name)
+			}
+			return nil
+		}
+	case AttributeTypeBool:
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+	case AttributeTypeEnum:
+		if s, ok := value.(string); ok {
+			if !d.EnumValues.Contains(s) {
+				return fmt.Errorf("%w: attribute %q must be one of %v", ErrInvalidArgument, d.Name, []string(d.EnumValues))
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: attribute %q must be a %s value", ErrInvalidArgument, d.Name, d.Type)
+}
+
+// AttributeDefinitionListOptions selects the attribute definitions to list.
+// Empty options list every definition.
+type AttributeDefinitionListOptions struct {
+	ProductType string
+	CategoryID  *uuid.UUID
+	// Names only lists the definitions with these names
+	Names []string
+}
+
+// AttributeValues maps attribute names to the values a product has for
+// them: a string, a float64 or a bool. It is stored as a JSON object.
+type AttributeValues map[string]interface{}
+
+// Value implements driver.Valuer
+func (a AttributeValues) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	raw, err := json.Marshal(map[string]interface{}(a))
+	return string(raw), err
+}
+
+// Scan implements sql.Scanner
+func (a *AttributeValues) Scan(src interface{}) error {
+	return scanJSON(src, a)
+}
+
+// Type returns the API name of the product's type: "digital", "physical",
+// "subscription" or "bundle", or "" for a product without type-specific details
+func (p *Product) Type() string {
+	switch {
+	case p.DigitalProduct != nil:
+		return "digital"
+	case p.PhysicalProduct != nil:
+		return "physical"
+	case p.SubscriptionProduct != nil:
+		return "subscription"
+	case p.BundleProduct != nil:
+		return "bundle"
+	}
+	return ""
+}
+
+// ValidateProductType checks that name is one of the product type names
+// returned by Product.Type
+func ValidateProductType(name string) error {
+	switch name {
+	case "digital", "physical", "subscription", "bundle":
+		return nil
+	}
+	return fmt.Errorf("%w: unknown product type %q", ErrInvalidArgument, name)
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/currency"
+	"gorm.io/gorm"
+)
+
+// BundlePricing decides how the price of a bundle product is set
+type BundlePricing string
+
+const (
+	// BundlePricingFixed sells the bundle at its own product price
+	BundlePricingFixed BundlePricing = "fixed"
+	// BundlePricingPercentOff sells the bundle at the sum of its component
+	// prices less DiscountPercent
+	BundlePricingPercentOff BundlePricing = "percent_off"
+)
+
+// BundleProduct holds the details of a product that sells other products, or
+// variants of them, together at one price
+type BundleProduct struct {
+	ID      uuid.UUID     `gorm:"primaryKey"`
+	Pricing BundlePricing `gorm:"not null;default:'fixed'"`
+	// DiscountPercent is between 0 and 100 and only used by percent off pricing
+	DiscountPercent Amount `gorm:"type:numeric(28,9);not null;default:0"`
+	// Components are replaced as a whole on every update, in display order when loaded
+	Components []BundleComponent `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// BundleComponent is a quantity of one product, or of one of its variants,
+// included in a bundle
+type BundleComponent struct {
+	ID              uuid.UUID `gorm:"primaryKey"`
+	BundleProductID uuid.UUID `gorm:"not null;index"`
+	ProductID       uuid.UUID `gorm:"not null;index"`
+	// Product and Variant are only declared for the foreign keys, which keep
+	// products and variants from being removed while a bundle contains them
+	Product   *Product        `gorm:"constraint:OnDelete:RESTRICT"`
+	VariantID *uuid.UUID      `gorm:"index"`
+	Variant   *ProductVariant `gorm:"constraint:OnDelete:RESTRICT"`
+	Quantity  int32           `gorm:"not null"`
+	// Position orders the components of a bundle, starting at 0
+	Position int32 `gorm:"not null"`
+}
+
+func (bp *BundleProduct) BeforeCreate(tx *gorm.DB) (err error) {
+	if bp.ID == uuid.Nil {
+		bp.ID = uuid.New()
+	}
+	return nil
+}
+
+func (bc *BundleComponent) BeforeCreate(tx *gorm.DB) (err error) {
+	if bc.ID == uuid.Nil {
+		bc.ID = uuid.New()
+	}
+	return nil
+}
+
+// PercentOff returns price less percent per cent, rounded half away from zero
+// to the minor unit of its currency
+func PercentOff(price Money, percent Amount) (Money, error) {
+	hundred := big.NewInt(100 * nanosPerUnit)
+	remaining := new(big.Int).Sub(hundred, percent.bigNanos())
+	discounted := new(big.Rat).SetFrac(new(big.Int).Mul(price.Amount.bigNanos(), remaining), hundred)
+
+	// Round to a whole number of minor units, counted in nanos
+	step := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(9-minorUnitDigits(price.Currency))), nil)
+	steps := new(big.Rat).Quo(discounted, new(big.Rat).SetInt(step))
+	whole, rest := new(big.Int).QuoRem(steps.Num(), steps.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rest), big.NewInt(2)).Cmp(steps.Denom()) >= 0 {
+		whole.Add(whole, big.NewInt(int64(steps.Sign())))
+	}
+
+	amount, err := amountFromNanos(whole.Mul(whole, step))
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: price.Currency}, nil
+}
+
+// minorUnitDigits returns how many fractional digits amounts in a currency
+// are rounded to, two for currencies the locale data does not know
+func minorUnitDigits(code string) int {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return 2
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return scale
+}
+
+// ValidatePercent checks that an amount is a percentage between 0 and 100
+func ValidatePercent(percent Amount) error {
+	if percent.Sign() < 0 || percent.Cmp(Amount{Units: 100}) > 0 {
+		return fmt.Errorf("%w: percentage %s is not between 0 and 100", ErrInvalidArgument, percent)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category is a node of the category tree. Products can be in any number of
+// categories.
+type Category struct {
+	ID       uuid.UUID  `gorm:"primaryKey"`
+	ParentID *uuid.UUID `gorm:"index"`
+	// Parent is only declared for the foreign key, which keeps categories
+	// with children from being deleted
+	Parent      *Category `gorm:"constraint:OnDelete:RESTRICT"`
+	Name        string    `gorm:"not null"`
+	Description string
+	// Path is the materialized path of the category: the IDs from the root
+	// down to the category itself, each followed by a slash. The paths of all
+	// descendants start with it, so a subtree is a prefix match.
+	Path      string `gorm:"not null;uniqueIndex"`
+	Version   int64  `gorm:"not null;default:1"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BeforeCreate assigns the ID and initial version of a new category
+func (c *Category) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.Version = 1
+	return nil
+}
+
+// CategoryPath returns the materialized path of a category below the given
+// parent path, which is empty for top level categories
+func CategoryPath(parentPath string, id uuid.UUID) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + id.String() + "/"
+}
+
+// IsDescendantOf reports whether the category lies in the subtree rooted at
+// the category with the given path, including that category itself
+func (c *Category) IsDescendantOf(path string) bool {
+	return strings.HasPrefix(c.Path, path)
+}
+
+// CategoryListOptions selects the categories to list
+type CategoryListOptions struct {
+	// ParentID lists the children of a category, or top level categories if nil
+	ParentID *uuid.UUID
+	// IncludeDescendants lists the whole subtree instead of one level
+	IncludeDescendants bool
+}
+
+// Tags are free-form labels on a product, stored as a JSON array
+type Tags []string
+
+// Value implements driver.Valuer
+func (t Tags) Value() (driver.Value, error) {
+	return stringListValue(t)
+}
+
+// Scan implements sql.Scanner
+func (t *Tags) Scan(src interface{}) error {
+	return scanJSON(src, t)
+}
+
+// CategoryIDs returns the IDs of the categories a product is in
+func (p *Product) CategoryIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, len(p.Categories))
+	for i := range p.Categories {
+		ids[i] = p.Categories[i].ID
+	}
+	return ids
+}
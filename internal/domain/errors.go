@@ -0,0 +1,17 @@
+package domain
+
+import "errors"
+
+// Sentinel errors shared by the repository and service layers. Callers wrap
+// them with context using fmt.Errorf("%w: ...") and the transport layer maps
+// them onto gRPC status codes.
+var (
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrNotFound        = errors.New("not found")
+	// ErrAlreadyExists means the write clashes with a row that is already stored
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrVersionConflict means the row was modified since the caller read it
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrFailedPrecondition means the operation is not valid in the row's current state
+	ErrFailedPrecondition = errors.New("failed precondition")
+)
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatETag renders a row version as the opaque etag handed to clients
+func FormatETag(version int64) string {
+	return fmt.Sprintf(`W/"%d"`, version)
+}
+
+// ParseETag extracts the row version from an etag produced by FormatETag
+func ParseETag(etag string) (int64, error) {
+	if etag == "" {
+		return 0, fmt.Errorf("%w: etag is required", ErrInvalidArgument)
+	}
+
+	value := strings.TrimSuffix(strings.TrimPrefix(etag, `W/"`), `"`)
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("%w: malformed etag %q", ErrInvalidArgument, etag)
+	}
+	return version, nil
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StockItem identifies what stock is counted for: a physical product, or one
+// of its variants, in one warehouse
+type StockItem struct {
+	ProductID   uuid.UUID `gorm:"not null"`
+	VariantID   *uuid.UUID
+	WarehouseID uuid.UUID `gorm:"not null"`
+}
+
+// StockLevel is how much of an item a warehouse holds. Reserved units are
+// held for pending reservations and cannot be reserved again. The unique
+// index on the item and the foreign key to the warehouse, which removes the
+// level with its warehouse, are created by db.Migrate.
+type StockLevel struct {
+	ID uuid.UUID `gorm:"primaryKey"`
+	StockItem
+	// Product and Variant are only declared for the foreign keys, so purging a
+	// product or deleting a variant removes its stock levels
+	Product   *Product        `gorm:"constraint:OnDelete:CASCADE"`
+	Variant   *ProductVariant `gorm:"constraint:OnDelete:CASCADE"`
+	OnHand    int64           `gorm:"not null;default:0"`
+	Reserved  int64           `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
+
+// Available is the number of units that can still be reserved
+func (l *StockLevel) Available() int64 {
+	return l.OnHand - l.Reserved
+}
+
+// BeforeCreate assigns the ID of a new stock level
+func (l *StockLevel) BeforeCreate(tx *gorm.DB) (err error) {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// StockReservationStatus is the lifecycle state of a stock reservation
+type StockReservationStatus string
+
+const (
+	StockReservationPending   StockReservationStatus = "pending"
+	StockReservationCommitted StockReservationStatus = "committed"
+	StockReservationReleased  StockReservationStatus = "released"
+	StockReservationExpired   StockReservationStatus = "expired"
+)
+
+// StockReservation holds units of an item for a caller, such as a checkout,
+// until it is committed, released or expires
+type StockReservation struct {
+	ID uuid.UUID `gorm:"primaryKey"`
+	StockItem
+	Quantity int64                  `gorm:"not null"`
+	Status   StockReservationStatus `gorm:"not null;index:idx_stock_reservations_expiry,priority:1"`
+	// Reference is an optional caller-supplied identifier such as an order number
+	Reference string
+	ExpiresAt time.Time `gorm:"not null;index:idx_stock_reservations_expiry,priority:2"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BeforeCreate assigns the ID of a new reservation
+func (r *StockReservation) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// StockMovementKind says what caused a stock movement
+type StockMovementKind string
+
+const (
+	StockMovementAdjustment  StockMovementKind = "adjustment"
+	StockMovementReservation StockMovementKind = "reservation"
+	StockMovementRelease     StockMovementKind = "release"
+	StockMovementCommit      StockMovementKind = "commit"
+	StockMovementExpiry      StockMovementKind = "expiry"
+)
+
+// StockMovement is an entry in the append-only ledger of stock changes. Each
+// records the change to the on hand and reserved counts of an item and the
+// counts it left behind.
+type StockMovement struct {
+	ID int64 `gorm:"primaryKey;autoIncrement"`
+	StockItem
+	Kind          StockMovementKind `gorm:"not null"`
+	OnHandDelta   int64             `gorm:"not null"`
+	ReservedDelta int64             `gorm:"not null"`
+	OnHandAfter   int64             `gorm:"not null"`
+	ReservedAfter int64             `gorm:"not null"`
+	// ReservationID is set for movements caused by a reservation
+	ReservationID *uuid.UUID `gorm:"index"`
+	Reason        string
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+// StockMovementOptions describes a page of the stock ledger, newest first
+type StockMovementOptions struct {
+	ProductID   uuid.UUID
+	VariantID   *uuid.UUID
+	WarehouseID *uuid.UUID
+	Limit       int
+	// BeforeID continues after the last row of the previous page
+	BeforeID int64
+}
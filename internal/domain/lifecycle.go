@@ -0,0 +1,38 @@
+package domain
+
+// ProductStatus is the lifecycle state of a product. Only published products
+// are shown to shoppers.
+type ProductStatus string
+
+const (
+	ProductStatusDraft     ProductStatus = "draft"
+	ProductStatusInReview  ProductStatus = "in_review"
+	ProductStatusPublished ProductStatus = "published"
+	ProductStatusArchived  ProductStatus = "archived"
+)
+
+// productStatusTransitions lists the states each state can move to. A
+// product is published only after review, and archived products go back to
+// draft before they can be published again.
+var productStatusTransitions = map[ProductStatus][]ProductStatus{
+	ProductStatusDraft:     {ProductStatusInReview, ProductStatusArchived},
+	ProductStatusInReview:  {ProductStatusDraft, ProductStatusPublished, ProductStatusArchived},
+	ProductStatusPublished: {ProductStatusArchived},
+	ProductStatusArchived:  {ProductStatusDraft},
+}
+
+// Valid reports whether s is a known status
+func (s ProductStatus) Valid() bool {
+	_, ok := productStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a product in state s may move to target
+func (s ProductStatus) CanTransitionTo(target ProductStatus) bool {
+	for _, allowed := range productStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// LengthUnit is the unit the dimensions of a physical product are given in
+type LengthUnit string
+
+const (
+	LengthUnitCentimeter LengthUnit = "cm"
+	LengthUnitInch       LengthUnit = "in"
+)
+
+// lengthUnitCentimeters is the length of each unit in centimeters
+var lengthUnitCentimeters = map[LengthUnit]float64{
+	LengthUnitCentimeter: 1,
+	LengthUnitInch:       2.54,
+}
+
+// WeightUnit is the unit the weight of a physical product is given in
+type WeightUnit string
+
+const (
+	WeightUnitGram     WeightUnit = "g"
+	WeightUnitKilogram WeightUnit = "kg"
+	WeightUnitPound    WeightUnit = "lb"
+	WeightUnitOunce    WeightUnit = "oz"
+)
+
+// weightUnitGrams is the weight of each unit in grams
+var weightUnitGrams = map[WeightUnit]float64{
+	WeightUnitGram:     1,
+	WeightUnitKilogram: 1000,
+	WeightUnitPound:    453.59237,
+	WeightUnitOunce:    28.349523125,
+}
+
+// ConvertLength converts a length between units
+func ConvertLength(value float64, from, to LengthUnit) (float64, error) {
+	fromCentimeters, ok := lengthUnitCentimeters[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown length unit %q", ErrInvalidArgument, from)
+	}
+	toCentimeters, ok := lengthUnitCentimeters[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown length unit %q", ErrInvalidArgument, to)
+	}
+	if from == to {
+		return value, nil
+	}
+	return value * fromCentimeters / toCentimeters, nil
+}
+
+// ConvertWeight converts a weight between units
+func ConvertWeight(value float64, from, to WeightUnit) (float64, error) {
+	fromGrams, ok := weightUnitGrams[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown weight unit %q", ErrInvalidArgument, from)
+	}
+	toGrams, ok := weightUnitGrams[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown weight unit %q", ErrInvalidArgument, to)
+	}
+	if from == to {
+		return value, nil
+	}
+	return value * fromGrams / toGrams, nil
+}
+
+// Weight is the weight of a physical product. The zero Weight means the
+// weight is not known.
+type Weight struct {
+	Value float64
+	Unit  WeightUnit
+}
+
+// IsZero reports whether the weight is unset
+func (w Weight) IsZero() bool {
+	return w == Weight{}
+}
+
+// Validate checks that a set weight is not negative and has a known unit
+func (w Weight) Validate() error {
+	if w.IsZero() {
+		return nil
+	}
+	if _, ok := weightUnitGrams[w.Unit]; !ok {
+		return fmt.Errorf("%w: weight needs a unit of g, kg, lb or oz", ErrInvalidArgument)
+	}
+	if w.Value < 0 || math.IsNaN(w.Value) || math.IsInf(w.Value, 0) {
+		return fmt.Errorf("%w: weight must be a non-negative number", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// In returns the weight converted to unit
+func (w Weight) In(unit WeightUnit) (Weight, error) {
+	value, err := ConvertWeight(w.Value, w.Unit, unit)
+	if err != nil {
+		return Weight{}, err
+	}
+	return Weight{Value: value, Unit: unit}, nil
+}
+
+// Dimensions are the length, width and height of a physical product, or of
+// its package. The zero Dimensions mean they are not known.
+type Dimensions struct {
+	Length float64
+	Width  float64
+	Height float64
+	Unit   LengthUnit
+}
+
+// IsZero reports whether the dimensions are unset
+func (d Dimensions) IsZero() bool {
+	return d == Dimensions{}
+}
+
+// Validate checks that set dimensions are all positive and have a known unit
+func (d Dimensions) Validate() error {
+	if d.IsZero() {
+		return nil
+	}
+	if _, ok := lengthUnitCentimeters[d.Unit]; !ok {
+		return fmt.Errorf("%w: dimensions need a unit of cm or in", ErrInvalidArgument)
+	}
+	for _, side := range []float64{d.Length, d.Width, d.Height} {
+		if !(side > 0) || math.IsInf(side, 0) {
+			return fmt.Errorf("%w: length, width and height must all be positive", ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+// In returns the dimensions converted to unit
+func (d Dimensions) In(unit LengthUnit) (Dimensions, error) {
+	converted := Dimensions{Unit: unit}
+	for _, side := range []struct{ from, to *float64 }{
+		{&d.Length, &converted.Length},
+		{&d.Width, &converted.Width},
+		{&d.Height, &converted.Height},
+	} {
+		value, err := ConvertLength(*side.from, d.Unit, unit)
+		if err != nil {
+			return Dimensions{}, err
+		}
+		*side.to = value
+	}
+	return converted, nil
+}
+
+// Volume is the product of the three sides, in the cube of the unit
+func (d Dimensions) Volume() float64 {
+	return d.Length * d.Width * d.Height
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductMedia is an image or other file attached to a product. The content
+// lives in the blob store under StorageKey; the row only describes it.
+type ProductMedia struct {
+	ID        uuid.UUID `gorm:"primaryKey"`
+	ProductID uuid.UUID `gorm:"not null;index:idx_product_media_position,priority:1"`
+	// Product is only declared for the foreign key, so purging a product removes its media rows
+	Product    *Product `gorm:"constraint:OnDelete:CASCADE"`
+	StorageKey string   `gorm:"not null;uniqueIndex"`
+	// URL is where clients download the content, as returned by the blob store
+	URL         string `gorm:"not null"`
+	FileName    string
+	ContentType string `gorm:"not null"`
+	Size        int64  `gorm:"not null"`
+	// Width and Height are the pixel dimensions of images, zero for other files
+	Width  int32
+	Height int32
+	// Checksum is the hex encoded SHA-256 of the content
+	Checksum string `gorm:"not null"`
+	AltText  string
+	// Position orders the media of a product for display, starting at 0
+	Position  int32 `gorm:"not null;index:idx_product_media_position,priority:2"`
+	Version   int64 `gorm:"not null;default:1"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BeforeCreate assigns the ID and initial version of new media
+func (m *ProductMedia) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	m.Version = 1
+	return nil
+}
@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// nanosPerUnit is the number of nanos in one unit of an Amount
+const nanosPerUnit = 1_000_000_000
+
+// Amount is an exact decimal number with up to nine fractional digits. Like
+// google.type.Money it is kept as whole units plus nanos (10^-9 units), both
+// of the same sign. It is stored as NUMERIC and never passes through a float.
+type Amount struct {
+	Units int64
+	Nanos int32
+}
+
+// NewAmount builds an amount from units and nanos, rejecting values that
+// google.type.Money would reject
+func NewAmount(units int64, nanos int32) (Amount, error) {
+	if nanos <= -nanosPerUnit || nanos >= nanosPerUnit {
+		return Amount{}, fmt.Errorf("%w: nanos must be between -999999999 and 999999999", ErrInvalidArgument)
+	}
+	if (units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		return Amount{}, fmt.Errorf("%w: units and nanos must have the same sign", ErrInvalidArgument)
+	}
+	return Amount{Units: units, Nanos: nanos}, nil
+}
+
+// ParseAmount parses a decimal string such as "12.30" or "-0.5". More than
+// nine fractional digits are rejected rather than rounded.
+func ParseAmount(s string) (Amount, error) {
+	invalid := fmt.Errorf("%w: invalid amount %q", ErrInvalidArgument, s)
+
+	text := strings.TrimSpace(s)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "-"), "+")
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" && fraction == "" || len(fraction) > 9 {
+		return Amount{}, invalid
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	units, err := strconv.ParseUint(whole, 10, 63)
+	if err != nil {
+		return Amount{}, invalid
+	}
+	var nanos uint64
+	if fraction != "" {
+		if nanos, err = strconv.ParseUint(fraction, 10, 32); err != nil {
+			return Amount{}, invalid
+		}
+		for i := len(fraction); i < 9; i++ {
+			nanos *= 10
+		}
+	}
+
+	amount := Amount{Units: int64(units), Nanos: int32(nanos)}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+// MustParseAmount is like ParseAmount but panics on malformed input. It is
+// meant for constants.
+func MustParseAmount(s string) Amount {
+	amount, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// String formats the amount as a plain decimal without trailing zeros
+func (a Amount) String() string {
+	sign := ""
+	units, nanos := a.Units, int64(a.Nanos)
+	if units < 0 || nanos < 0 {
+		sign = "-"
+		units, nanos = -units, -nanos
+	}
+	if nanos == 0 {
+		return sign + strconv.FormatUint(uint64(units), 10)
+	}
+	fraction := strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")
+	return sign + strconv.FormatUint(uint64(units), 10) + "." + fraction
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the amount
+func (a Amount) Sign() int {
+	switch {
+	case a.Units > 0 || a.Nanos > 0:
+		return 1
+	case a.Units < 0 || a.Nanos < 0:
+		return -1
+	}
+	return 0
+}
+
+// IsZero reports whether the amount is zero
+func (a Amount) IsZero() bool {
+	return a.Units == 0 && a.Nanos == 0
+}
+
+// Neg returns -a
+func (a Amount) Neg() Amount {
+	return Amount{Units: -a.Units, Nanos: -a.Nanos}
+}
+
+// Cmp compares two amounts and returns -1, 0 or 1
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a.Units < b.Units:
+		return -1
+	case a.Units > b.Units:
+		return 1
+	case a.Nanos < b.Nanos:
+		return -1
+	case a.Nanos > b.Nanos:
+		return 1
+	}
+	return 0
+}
+
+// Add returns a+b, failing instead of overflowing
+func (a Amount) Add(b Amount) (Amount, error) {
+	return amountFromNanos(new(big.Int).Add(a.bigNanos(), b.bigNanos()))
+}
+
+// Sub returns a-b, failing instead of overflowing
+func (a Amount) Sub(b Amount) (Amount, error) {
+	return a.Add(b.Neg())
+}
+
+// MulInt returns a*n, failing instead of overflowing
+func (a Amount) MulInt(n int64) (Amount, error) {
+	return amountFromNanos(new(big.Int).Mul(a.bigNanos(), big.NewInt(n)))
+}
+
+// UnscaledNanos returns the amount as a whole number of nanos
+func (a Amount) UnscaledNanos() *big.Int {
+	return a.bigNanos()
+}
+
+func (a Amount) bigNanos() *big.Int {
+	n := new(big.Int).Mul(big.NewInt(a.Units), big.NewInt(nanosPerUnit))
+	return n.Add(n, big.NewInt(int64(a.Nanos)))
+}
+
+// amountFromNanos converts a whole number of nanos back into an amount
+func amountFromNanos(n *big.Int) (Amount, error) {
+	units, nanos := new(big.Int).QuoRem(n, big.NewInt(nanosPerUnit), new(big.Int))
+	if !units.IsInt64() || units.Int64() == math.MinInt64 {
+		return Amount{}, fmt.Errorf("%w: amount out of range", ErrInvalidArgument)
+	}
+	return Amount{Units: units.Int64(), Nanos: int32(nanos.Int64())}, nil
+}
+
+// Value stores the amount as a decimal string, which Postgres reads into
+// NUMERIC without loss
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan reads a NUMERIC column
+func (a *Amount) Scan(src interface{}) error {
+	var text string
+	switch v := src.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case int64:
+		*a = Amount{Units: v}
+		return nil
+	case nil:
+		*a = Amount{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into an amount", src)
+	}
+	amount, err := ParseAmount(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// MarshalText encodes the amount as a decimal string, so it survives JSON
+// without being turned into a float
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an amount written by MarshalText
+func (a *Amount) UnmarshalText(text []byte) error {
+	amount, err := ParseAmount(string(text))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Money is an amount in a currency identified by its ISO 4217 code. Rows
+// that predate currencies were migrated to USD, hence the column default.
+type Money struct {
+	Amount   Amount `gorm:"type:numeric(28,9);not null;default:0"`
+	Currency string `gorm:"type:char(3);not null;default:'USD'"`
+}
+
+// NewMoney returns an amount of money in the given currency
+func NewMoney(currency string, amount Amount) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Validate checks the currency code and the amount representation
+func (m Money) Validate() error {
+	if m.Currency == "" {
+		return fmt.Errorf("%w: currency code is required", ErrInvalidArgument)
+	}
+	if !IsCurrencyCode(m.Currency) {
+		return fmt.Errorf("%w: unknown ISO 4217 currency code %q", ErrInvalidArgument, m.Currency)
+	}
+	_, err := NewAmount(m.Amount.Units, m.Amount.Nanos)
+	return err
+}
+
+// String formats the money as "12.5 EUR"
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PriceChange is one row of the append-only price history: the price stored
+// on a product, or on one of its subscription plans, from ChangedAt until the
+// next row for the same target. Rows are written by database triggers
+// whenever a price is set, and the table rejects updates and deletes.
+type PriceChange struct {
+	ID                 int64      `gorm:"primaryKey;autoIncrement"`
+	ProductID          uuid.UUID  `gorm:"not null;index:idx_price_history_target,priority:1"`
+	SubscriptionPlanID *uuid.UUID `gorm:"index:idx_price_history_target,priority:2"`
+	Price              Money      `gorm:"embedded;embeddedPrefix:price_"`
+	// ScheduledPriceChangeID is set when the change was applied by the price scheduler
+	ScheduledPriceChangeID *uuid.UUID
+	ChangedAt              time.Time `gorm:"not null"`
+}
+
+// TableName keeps the table named after what it holds
+func (PriceChange) TableName() string {
+	return "price_history"
+}
+
+// PriceHistoryOptions describes a page of price history, newest first
+type PriceHistoryOptions struct {
+	ProductID          uuid.UUID
+	SubscriptionPlanID *uuid.UUID
+	Limit              int
+	// BeforeID continues after the last row of the previous page
+	BeforeID int64
+}
+
+// ScheduledPriceChangeStatus is the lifecycle state of a scheduled price change
+type ScheduledPriceChangeStatus string
+
+const (
+	ScheduledPriceChangePending   ScheduledPriceChangeStatus = "pending"
+	ScheduledPriceChangeApplied   ScheduledPriceChangeStatus = "applied"
+	ScheduledPriceChangeCancelled ScheduledPriceChangeStatus = "cancelled"
+	// ScheduledPriceChangeFailed means the change could not be applied, for
+	// example because the plan was deleted in the meantime
+	ScheduledPriceChangeFailed ScheduledPriceChangeStatus = "failed"
+)
+
+// ScheduledPriceChange sets the price of a product or subscription plan at a
+// future time. Pending changes are applied by the price scheduler running
+// inside the service.
+type ScheduledPriceChange struct {
+	ID                 uuid.UUID                  `gorm:"primaryKey"`
+	ProductID          uuid.UUID                  `gorm:"not null;index"`
+	SubscriptionPlanID *uuid.UUID                 `gorm:"index"`
+	Price              Money                      `gorm:"embedded;embeddedPrefix:price_"`
+	EffectiveAt        time.Time                  `gorm:"not null;index:idx_scheduled_price_changes_due,priority:2"`
+	Status             ScheduledPriceChangeStatus `gorm:"not null;default:'pending';index:idx_scheduled_price_changes_due,priority:1"`
+	// Error explains why a failed change was not applied
+	Error     string
+	AppliedAt *time.Time
+	Version   int64 `gorm:"not null;default:1"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BeforeCreate assigns the ID and initial state of a new scheduled change
+func (c *ScheduledPriceChange) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.Status = ScheduledPriceChangePending
+	c.Version = 1
+	return nil
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultRegion is the region of price list entries that apply wherever a
+// region has no price of its own
+const DefaultRegion = ""
+
+// PriceListEntry is the price of a product, or of one of its subscription
+// plans, in one region and currency over a period of time. Entries for the
+// same target, region and currency may not overlap in time; the database
+// enforces this with an exclusion constraint created by db.Migrate.
+type PriceListEntry struct {
+	ID                 uuid.UUID  `gorm:"primaryKey"`
+	ProductID          uuid.UUID  `gorm:"not null;index"`
+	SubscriptionPlanID *uuid.UUID `gorm:"index"`
+	// Region is an upper case storefront region code such as "EU" or "US",
+	// or DefaultRegion
+	Region string `gorm:"not null;default:''"`
+	Price  Money  `gorm:"embedded;embeddedPrefix:price_"`
+	// EffectiveFrom is inclusive and EffectiveTo exclusive; a nil bound is open
+	EffectiveFrom *time.Time
+	EffectiveTo   *time.Time
+	Version       int64 `gorm:"not null;default:1"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	// Only declared for the foreign keys: entries are removed together with
+	// the product or plan they price
+	Product          *Product          `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	SubscriptionPlan *SubscriptionPlan `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate assigns the ID and initial version of a new entry
+func (e *PriceListEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	e.Version = 1
+	return nil
+}
+
+// EffectiveAt reports whether the entry applies at the given time
+func (e *PriceListEntry) EffectiveAt(at time.Time) bool {
+	if e.EffectiveFrom != nil && at.Before(*e.EffectiveFrom) {
+		return false
+	}
+	return e.EffectiveTo == nil || at.Before(*e.EffectiveTo)
+}
+
+// PriceQuery asks for the price of a product, or of one of its subscription
+// plans, in a region and currency at a point in time
+type PriceQuery struct {
+	ProductID          uuid.UUID
+	SubscriptionPlanID *uuid.UUID
+	Region             string
+	Currency           string
+	At                 time.Time
+}
+
+// PriceSource says where a resolved price came from
+type PriceSource string
+
+const (
+	// PriceSourceRegion is an entry of the requested region
+	PriceSourceRegion PriceSource = "region"
+	// PriceSourceDefaultRegion is an entry of DefaultRegion
+	PriceSourceDefaultRegion PriceSource = "default_region"
+	// PriceSourceBasePrice is the price stored on the product or plan itself
+	PriceSourceBasePrice PriceSource = "base_price"
+)
+
+// ResolvedPrice is the answer to a PriceQuery. Entry is nil when the base
+// price was used.
+type ResolvedPrice struct {
+	Price  Money
+	Source PriceSource
+	Entry  *PriceListEntry
+}
@@ -8,21 +8,54 @@ import (
 )
 
 type Product struct {
-	ID                  uuid.UUID `gorm:"primaryKey"`
-	Name                string
+	ID                  uuid.UUID `gorm:"primaryKey;index:idx_products_name_id,priority:2;index:idx_products_created_at_id,priority:2"`
+	Name                string    `gorm:"index:idx_products_name_id,priority:1"`
 	Description         string
-	Price               float64
-	CreatedAt           time.Time
+	// Price is stored in price_amount and price_currency; the (price_amount, id)
+	// index is created by db.Migrate
+	Price               Money     `gorm:"embedded;embeddedPrefix:price_"`
+	CreatedAt           time.Time `gorm:"index:idx_products_created_at_id,priority:1"`
 	UpdatedAt           time.Time
+	// Version is bumped on every update and backs the etag used for optimistic locking
+	Version             int64     `gorm:"not null;default:1"`
+	// DeletedAt marks the product as soft deleted; GORM hides such rows from queries
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
+	// Status is the lifecycle state. Products that predate the lifecycle
+	// count as published; new products start as drafts.
+	Status              ProductStatus `gorm:"not null;default:'published';index"`
+	// PublishAt and UnpublishAt schedule the product to be published while
+	// in review, and archived while published, by the publish scheduler
+	PublishAt           *time.Time
+	UnpublishAt         *time.Time
+	// PublishedAt is when the product was last published
+	PublishedAt         *time.Time
+	// ExternalSKU identifies the product in external systems and keys imports
+	ExternalSKU         *string   `gorm:"uniqueIndex"`
+	// OptionNames are the axes, such as size and colour, its variants differ on
+	OptionNames         OptionNames `gorm:"type:jsonb;not null;default:'[]'"`
+	// Tags are lower-case labels; the GIN index on them is created by db.Migrate
+	Tags                Tags `gorm:"type:jsonb;not null;default:'[]'"`
+	// Attributes hold the values of custom attributes, keyed by the name of
+	// their AttributeDefinition; the GIN index on them is created by db.Migrate
+	Attributes          AttributeValues `gorm:"type:jsonb;not null;default:'{}'"`
+	// Categories are written by the repository itself, never by GORM's association saving
+	Categories          []Category `gorm:"many2many:product_categories;constraint:OnDelete:CASCADE"`
+	// Media are written through the media methods of the repository, in display order when loaded
+	Media               []ProductMedia
+	// Locale is the locale Name was translated into when the product was
+	// localized for a reader, empty for its own name and description
+	Locale              string `gorm:"-"`
 	// Associations with specific product types
 	DigitalProductID    *uuid.UUID `gorm:"index"`
 	PhysicalProductID   *uuid.UUID `gorm:"index"`
 	SubscriptionProductID *uuid.UUID `gorm:"index"`
+	BundleProductID     *uuid.UUID `gorm:"index"`
 
 	// Actual product associations (Pointers to structs)
 	DigitalProduct      *DigitalProduct
 	PhysicalProduct     *PhysicalProduct
 	SubscriptionProduct *SubscriptionProduct
+	BundleProduct       *BundleProduct
 }
 
 type DigitalProduct struct {
@@ -32,20 +65,24 @@ type DigitalProduct struct {
 }
 
 type PhysicalProduct struct {
-	ID         uuid.UUID `gorm:"primaryKey"`
-	Weight     float32
-	Dimensions string
+	ID         uuid.UUID  `gorm:"primaryKey"`
+	Weight     Weight     `gorm:"embedded;embeddedPrefix:weight_"`
+	Dimensions Dimensions `gorm:"embedded;embeddedPrefix:dimensions_"`
 }
 
 type SubscriptionProduct struct {
 	ID                uuid.UUID `gorm:"primaryKey"`
 	SubscriptionPeriod string
-	RenewalPrice      float32
+	RenewalPrice      Money `gorm:"embedded;embeddedPrefix:renewal_price_"`
 }
 
 // Hook to automatically set UUID before creating records
 func (p *Product) BeforeCreate(tx *gorm.DB) (err error) {
 	p.ID = uuid.New()
+	p.Version = 1
+	if p.Status == "" {
+		p.Status = ProductStatusDraft
+	}
 	if p.DigitalProduct != nil {
 		p.DigitalProduct.ID = uuid.New()
 	}
@@ -55,6 +92,9 @@ func (p *Product) BeforeCreate(tx *gorm.DB) (err error) {
 	if p.SubscriptionProduct != nil {
 		p.SubscriptionProduct.ID = uuid.New()
 	}
+	if p.BundleProduct != nil {
+		p.BundleProduct.ID = uuid.New()
+	}
 	return nil
 }
 
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductEventType says how a watched resource changed
+type ProductEventType string
+
+const (
+	ProductEventCreated ProductEventType = "created"
+	ProductEventUpdated ProductEventType = "updated"
+	ProductEventDeleted ProductEventType = "deleted"
+)
+
+// Resources recorded in the product change log
+const (
+	ProductEventResourceProduct          = "product"
+	ProductEventResourceSubscriptionPlan = "subscription_plan"
+)
+
+// ProductEvent is one entry of the product change log. Rows are written by
+// database triggers in the same transaction as the change they record.
+type ProductEvent struct {
+	Sequence int64 `gorm:"primaryKey;autoIncrement;index:idx_product_events_position,priority:2"`
+	// TransactionID is the id of the writing transaction. Events are read in
+	// (TransactionID, Sequence) order so a transaction that commits late
+	// cannot slip in behind a reader's position.
+	TransactionID int64            `gorm:"not null;index:idx_product_events_position,priority:1"`
+	Resource      string           `gorm:"not null"`
+	ResourceID    uuid.UUID        `gorm:"not null"`
+	ProductID     uuid.UUID        `gorm:"not null"`
+	Type          ProductEventType `gorm:"not null"`
+	CreatedAt     time.Time
+
+	// Current state of the resource, loaded when the event is read. Nil if
+	// the resource no longer exists.
+	Product          *Product          `gorm:"-"`
+	SubscriptionPlan *SubscriptionPlan `gorm:"-"`
+}
+
+// ProductEventPosition is a position in the change log, the event it
+// identifies excluded
+type ProductEventPosition struct {
+	TransactionID int64
+	Sequence      int64
+}
+
+// Position returns the log position right after the event
+func (e *ProductEvent) Position() ProductEventPosition {
+	return ProductEventPosition{TransactionID: e.TransactionID, Sequence: e.Sequence}
+}
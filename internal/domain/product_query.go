@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductSortField is a column products can be ordered by when listing
+type ProductSortField string
+
+const (
+	ProductSortByName      ProductSortField = "name"
+	ProductSortByPrice     ProductSortField = "price"
+	ProductSortByCreatedAt ProductSortField = "created_at"
+)
+
+// ProductFilter narrows down the set of products returned by a listing
+type ProductFilter struct {
+	Type         string
+	MinPrice     *Money
+	MaxPrice     *Money
+	CreatedAfter *time.Time
+	NamePrefix   string
+	ShowDeleted  bool
+	// CategoryID restricts the listing to products in the category, and in
+	// its descendants too if IncludeDescendants is set
+	CategoryID         *uuid.UUID
+	IncludeDescendants bool
+	Tag                string
+	Attributes         []AttributeFilter
+	// Statuses restricts the listing to products in these lifecycle states
+	Statuses []ProductStatus
+}
+
+// AttributeFilter matches products on the value of one custom attribute:
+// equal to Equals when it is set, and within Min and Max for number attributes
+type AttributeFilter struct {
+	Name   string
+	Equals interface{}
+	Min    *float64
+	Max    *float64
+}
+
+// ProductCursor identifies the last row of a page for keyset pagination.
+// Only the value matching the sort field is compared.
+type ProductCursor struct {
+	Name      string
+	Price     Amount
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// ProductListOptions describes a single page request against the products table
+type ProductListOptions struct {
+	Filter     ProductFilter
+	OrderBy    ProductSortField
+	Descending bool
+	Limit      int
+	After      *ProductCursor
+}
+
+// ProductSearchOptions describes a full-text search over the catalog
+type ProductSearchOptions struct {
+	Query string
+	Types []string
+	// Locales, most preferred first, whose translations are searched and
+	// highlighted in place of the products' own names and descriptions
+	Locales []string
+	// Statuses restricts the results to products in these lifecycle states
+	Statuses []ProductStatus
+	Limit    int
+	Offset   int
+}
+
+// ProductSearchHit is a product matched by a search along with its relevance
+// and the highlighted fragments that matched
+type ProductSearchHit struct {
+	Product            Product
+	Rank               float64
+	NameHighlight      string
+	DescriptionSnippet string
+}
+
+// ExportedProduct is a product together with its subscription plans as
+// produced by a catalog export
+type ExportedProduct struct {
+	Product           Product
+	SubscriptionPlans []SubscriptionPlan
+}
@@ -9,5 +9,6 @@ type SubscriptionPlan struct {
 	ProductID uuid.UUID `gorm:"product_id"`
 	PlanName  string    `json:"plan_name"`
 	Duration  int       `json:"duration"`
-	Price     float64   `json:"price"`
+	Price     Money     `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+	Version   int64     `gorm:"not null;default:1" json:"version"`
 }
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+)
+
+// ProductTranslation holds the name and description of a product in one
+// locale. The search_vector column and its indexes are created by db.Migrate.
+type ProductTranslation struct {
+	ProductID uuid.UUID `gorm:"primaryKey"`
+	// Product is only declared for the foreign key, so purging a product removes its translations
+	Product *Product `gorm:"constraint:OnDelete:CASCADE"`
+	// Locale is a canonical BCP 47 tag such as "de" or "pt-BR"
+	Locale      string `gorm:"primaryKey"`
+	Name        string `gorm:"not null"`
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CanonicalLocale parses a BCP 47 language tag and returns its language,
+// script and region in canonical form, so "pt_br" and "PT-BR" are both
+// stored as "pt-BR"
+func CanonicalLocale(locale string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(locale))
+	if err != nil || !specificLanguage(tag) {
+		return "", fmt.Errorf("%w: %q is not a valid locale", ErrInvalidArgument, locale)
+	}
+	return localeOf(tag), nil
+}
+
+// LocaleFallbacks turns a single language tag or an Accept-Language style
+// priority list such as "fr-CH, fr;q=0.9, en;q=0.8" into the locales to look
+// translations up in, most preferred first. Every tag is followed by its
+// less specific forms, so "de-CH" falls back to "de" before the next tag.
+// An empty list means the product's own name and description.
+func LocaleFallbacks(preferences string) ([]string, error) {
+	if strings.TrimSpace(preferences) == "" {
+		return nil, nil
+	}
+	tags, _, err := language.ParseAcceptLanguage(preferences)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid locale %q: %v", ErrInvalidArgument, preferences, err)
+	}
+
+	var locales []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if !specificLanguage(tag) {
+			continue
+		}
+		locale := localeOf(tag)
+		for {
+			if !seen[locale] {
+				seen[locale] = true
+				locales = append(locales, locale)
+			}
+			i := strings.LastIndexByte(locale, '-')
+			if i < 0 {
+				break
+			}
+			locale = locale[:i]
+		}
+	}
+	return locales, nil
+}
+
+// specificLanguage reports whether a tag names an actual language, as
+// opposed to "und", the "*" wildcard or a private use tag
+func specificLanguage(tag language.Tag) bool {
+	base, _, _ := tag.Raw()
+	switch base.String() {
+	case "und", "mul":
+		return false
+	}
+	return !strings.HasPrefix(tag.String(), "x-")
+}
+
+// localeOf drops the variants and extensions of a tag
+func localeOf(tag language.Tag) string {
+	base, script, region := tag.Raw()
+	composed, err := language.Compose(base, script, region)
+	if err != nil {
+		return base.String()
+	}
+	return composed.String()
+}
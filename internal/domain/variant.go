@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OptionNames lists the option axes of a product, such as "size" and
+// "colour", in display order. It is stored as a JSON array.
+type OptionNames []string
+
+// Value implements driver.Valuer
+func (n OptionNames) Value() (driver.Value, error) {
+	return stringListValue(n)
+}
+
+// Scan implements sql.Scanner
+func (n *OptionNames) Scan(src interface{}) error {
+	return scanJSON(src, n)
+}
+
+// Equal reports whether both lists hold the same axes in the same order
+func (n OptionNames) Equal(other OptionNames) bool {
+	if len(n) != len(other) {
+		return false
+	}
+	for i := range n {
+		if n[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// VariantOptions maps every option axis of a product to the value a variant
+// has on it, such as {"size": "M", "colour": "red"}. It is stored as a JSON
+// object.
+type VariantOptions map[string]string
+
+// Value implements driver.Valuer
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	raw, err := json.Marshal(map[string]string(o))
+	return string(raw), err
+}
+
+// Scan implements sql.Scanner
+func (o *VariantOptions) Scan(src interface{}) error {
+	return scanJSON(src, o)
+}
+
+// Matches reports whether the options set a value for exactly the given axes
+func (o VariantOptions) Matches(names OptionNames) bool {
+	if len(o) != len(names) {
+		return false
+	}
+	for _, name := range names {
+		if _, ok := o[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// stringListValue encodes a list of strings as a JSON array, never null
+func stringListValue(list []string) (driver.Value, error) {
+	if list == nil {
+		return "[]", nil
+	}
+	raw, err := json.Marshal(list)
+	return string(raw), err
+}
+
+// scanJSON decodes a JSON column into dst
+func scanJSON(src interface{}, dst interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case nil:
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into %T", src, dst)
+}
+
+// ProductVariant is one sellable version of a product, such as the medium
+// red shirt, with its own SKU. Each variant sets a value for every option
+// axis of its product and no two variants of a product share all values.
+type ProductVariant struct {
+	ID        uuid.UUID `gorm:"primaryKey"`
+	ProductID uuid.UUID `gorm:"not null;uniqueIndex:idx_product_variants_options,priority:1"`
+	// Product is only declared for the foreign key, so purging a product removes its variants
+	Product *Product `gorm:"constraint:OnDelete:CASCADE"`
+	// SKU is unique across all variants
+	SKU string `gorm:"not null;uniqueIndex"`
+	// Barcode is a GTIN-8, GTIN-12 (UPC-A), GTIN-13 (EAN) or GTIN-14, unique across all variants
+	Barcode *string        `gorm:"uniqueIndex"`
+	Options VariantOptions `gorm:"type:jsonb;not null;default:'{}';uniqueIndex:idx_product_variants_options,priority:2"`
+	// PriceOverrideAmount and PriceOverrideCurrency replace the product price
+	// for this variant when set. Both are set or neither, and the currency is
+	// always that of the product.
+	PriceOverrideAmount   *Amount `gorm:"type:numeric(28,9)"`
+	PriceOverrideCurrency *string `gorm:"type:char(3)"`
+	// WeightOverrideValue and WeightOverrideUnit replace the weight of a
+	// physical product for this variant when set. Both are set or neither.
+	WeightOverrideValue *float64
+	WeightOverrideUnit  *WeightUnit
+	Version             int64 `gorm:"not null;default:1"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// BeforeCreate assigns the ID and initial version of a new variant
+func (v *ProductVariant) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	v.Version = 1
+	return nil
+}
+
+// PriceOverride returns the price that replaces the product price, if any
+func (v *ProductVariant) PriceOverride() *Money {
+	if v.PriceOverrideAmount == nil || v.PriceOverrideCurrency == nil {
+		return nil
+	}
+	return &Money{Amount: *v.PriceOverrideAmount, Currency: *v.PriceOverrideCurrency}
+}
+
+// SetPriceOverride sets or, given nil, clears the price override
+func (v *ProductVariant) SetPriceOverride(price *Money) {
+	if price == nil {
+		v.PriceOverrideAmount, v.PriceOverrideCurrency = nil, nil
+		return
+	}
+	amount, currency := price.Amount, price.Currency
+	v.PriceOverrideAmount, v.PriceOverrideCurrency = &amount, &currency
+}
+
+// WeightOverride returns the weight that replaces the product weight, if any
+func (v *ProductVariant) WeightOverride() *Weight {
+	if v.WeightOverrideValue == nil || v.WeightOverrideUnit == nil {
+		return nil
+	}
+	return &Weight{Value: *v.WeightOverrideValue, Unit: *v.WeightOverrideUnit}
+}
+
+// SetWeightOverride sets or, given nil, clears the weight override
+func (v *ProductVariant) SetWeightOverride(weight *Weight) {
+	if weight == nil {
+		v.WeightOverrideValue, v.WeightOverrideUnit = nil, nil
+		return
+	}
+	value, unit := weight.Value, weight.Unit
+	v.WeightOverrideValue, v.WeightOverrideUnit = &value, &unit
+}
+
+// Price returns the price a variant sells for given the price of its product
+func (v *ProductVariant) Price(product Money) Money {
+	if override := v.PriceOverride(); override != nil {
+		return *override
+	}
+	return product
+}
+
+// ValidGTIN reports whether code is a GTIN-8, -12, -13 or -14 with a correct check digit
+func ValidGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		// Weights alternate 3, 1, 3, ... from the digit left of the check digit
+		if (len(code)-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Address is a postal address
+type Address struct {
+	Line1      string
+	Line2      string
+	City       string
+	Region     string
+	PostalCode string
+	// CountryCode is an ISO 3166-1 alpha-2 code such as "DE"
+	CountryCode string
+}
+
+// Warehouse is a location stock is kept in and shipped from. Stock is
+// allocated to active warehouses in the order of their priority, lower
+// numbers first. Names are unique ignoring case, by an index db.Migrate
+// creates.
+type Warehouse struct {
+	ID      uuid.UUID `gorm:"primaryKey"`
+	Name    string    `gorm:"not null"`
+	Address Address   `gorm:"embedded;embeddedPrefix:address_"`
+	// TimeZone is the IANA name of the time zone the warehouse works in
+	TimeZone string `gorm:"not null"`
+	Priority int32  `gorm:"not null"`
+	// Active warehouses take new reservations; inactive ones only keep the
+	// stock they hold
+	Active    bool  `gorm:"not null"`
+	Version   int64 `gorm:"not null;default:1"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BeforeCreate assigns the ID and initial version of a new warehouse
+func (w *Warehouse) BeforeCreate(tx *gorm.DB) (err error) {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	w.Version = 1
+	return nil
+}
+
+// WarehouseListOptions selects the warehouses to list
+type WarehouseListOptions struct {
+	ActiveOnly bool
+}
@@ -0,0 +1,105 @@
+package mapper
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// attributeTypes maps the domain attribute types to protobuf
+var attributeTypes = map[domain.AttributeType]pb.AttributeDefinition_Type{
+	domain.AttributeTypeString: pb.AttributeDefinition_STRING,
+	domain.AttributeTypeNumber: pb.AttributeDefinition_NUMBER,
+	domain.AttributeTypeBool:   pb.AttributeDefinition_BOOL,
+	domain.AttributeTypeEnum:   pb.AttributeDefinition_ENUM,
+}
+
+// AttributeValueToProto converts an attribute value, a string, float64 or bool, to protobuf
+func AttributeValueToProto(value interface{}) *pb.AttributeValue {
+	switch v := value.(type) {
+	case string:
+		return &pb.AttributeValue{Value: &pb.AttributeValue_StringValue{StringValue: v}}
+	case float64:
+		return &pb.AttributeValue{Value: &pb.AttributeValue_NumberValue{NumberValue: v}}
+	case bool:
+		return &pb.AttributeValue{Value: &pb.AttributeValue_BoolValue{BoolValue: v}}
+	}
+	return &pb.AttributeValue{}
+}
+
+// AttributeValueFromProto converts a protobuf attribute value to a string,
+// float64 or bool, or nil when no value is set
+func AttributeValueFromProto(pbValue *pb.AttributeValue) interface{} {
+	switch v := pbValue.GetValue().(type) {
+	case *pb.AttributeValue_StringValue:
+		return v.StringValue
+	case *pb.AttributeValue_NumberValue:
+		return v.NumberValue
+	case *pb.AttributeValue_BoolValue:
+		return v.BoolValue
+	}
+	return nil
+}
+
+// AttributeDefinitionToProto converts an attribute definition to its protobuf representation
+func AttributeDefinitionToProto(definition *domain.AttributeDefinition) *pb.AttributeDefinition {
+	pbDefinition := &pb.AttributeDefinition{
+		Id:          definition.ID.String(),
+		Name:        definition.Name,
+		Description: definition.Description,
+		Type:        attributeTypes[definition.Type],
+		Required:    definition.Required,
+		Unit:        definition.Unit,
+		EnumValues:  []string(definition.EnumValues),
+		CreatedAt:   timestamppb.New(definition.CreatedAt),
+		UpdatedAt:   timestamppb.New(definition.UpdatedAt),
+		Etag:        domain.FormatETag(definition.Version),
+	}
+	switch {
+	case definition.ProductType != nil:
+		pbDefinition.Scope = &pb.AttributeDefinition_ProductType{ProductType: *definition.ProductType}
+	case definition.CategoryID != nil:
+		pbDefinition.Scope = &pb.AttributeDefinition_CategoryId{CategoryId: definition.CategoryID.String()}
+	}
+	return pbDefinition
+}
+
+// AttributeDefinitionFromProto converts a protobuf attribute definition to
+// the domain model. The version and timestamps are left for the repository;
+// the ID is only read when set.
+func AttributeDefinitionFromProto(pbDefinition *pb.AttributeDefinition) (*domain.AttributeDefinition, error) {
+	definition := &domain.AttributeDefinition{
+		Name:        pbDefinition.GetName(),
+		Description: pbDefinition.GetDescription(),
+		Required:    pbDefinition.GetRequired(),
+		Unit:        pbDefinition.GetUnit(),
+		EnumValues:  pbDefinition.GetEnumValues(),
+	}
+	for domainType, pbType := range attributeTypes {
+		if pbType == pbDefinition.GetType() {
+			definition.Type = domainType
+		}
+	}
+
+	var err error
+	switch scope := pbDefinition.GetScope().(type) {
+	case *pb.AttributeDefinition_ProductType:
+		productType := scope.ProductType
+		definition.ProductType = &productType
+	case *pb.AttributeDefinition_CategoryId:
+		categoryID, err := uuid.Parse(scope.CategoryId)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid category ID format: %v", domain.ErrInvalidArgument, err)
+		}
+		definition.CategoryID = &categoryID
+	}
+	if pbDefinition.GetId() != "" {
+		if definition.ID, err = uuid.Parse(pbDefinition.GetId()); err != nil {
+			return nil, fmt.Errorf("%w: invalid attribute definition ID format: %v", domain.ErrInvalidArgument, err)
+		}
+	}
+	return definition, nil
+}
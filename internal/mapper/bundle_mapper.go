@@ -0,0 +1,75 @@
+package mapper
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+)
+
+var bundlePricings = map[domain.BundlePricing]pb.BundleProduct_Pricing{
+	domain.BundlePricingFixed:      pb.BundleProduct_FIXED,
+	domain.BundlePricingPercentOff: pb.BundleProduct_PERCENT_OFF,
+}
+
+// BundleProductToProto converts the details of a bundle product
+func BundleProductToProto(bundle *domain.BundleProduct) *pb.BundleProduct {
+	pbBundle := &pb.BundleProduct{
+		Pricing:    bundlePricings[bundle.Pricing],
+		Components: make([]*pb.BundleComponent, len(bundle.Components)),
+	}
+	if bundle.Pricing == domain.BundlePricingPercentOff {
+		pbBundle.DiscountPercent = bundle.DiscountPercent.String()
+	}
+	for i, component := range bundle.Components {
+		pbBundle.Components[i] = &pb.BundleComponent{
+			ProductId: component.ProductID.String(),
+			Quantity:  component.Quantity,
+		}
+		if component.VariantID != nil {
+			pbBundle.Components[i].VariantId = component.VariantID.String()
+		}
+	}
+	return pbBundle
+}
+
+// BundleProductFromProto converts the details of a bundle product. Unspecified
+// pricing is left empty for the service to default.
+func BundleProductFromProto(pbBundle *pb.BundleProduct) (*domain.BundleProduct, error) {
+	bundle := &domain.BundleProduct{
+		Components: make([]domain.BundleComponent, len(pbBundle.GetComponents())),
+	}
+	for pricing, pbPricing := range bundlePricings {
+		if pbPricing == pbBundle.GetPricing() {
+			bundle.Pricing = pricing
+		}
+	}
+	if pbBundle.GetPricing() != pb.BundleProduct_PRICING_UNSPECIFIED && bundle.Pricing == "" {
+		return nil, fmt.Errorf("%w: unknown bundle pricing %v", domain.ErrInvalidArgument, pbBundle.GetPricing())
+	}
+	if pbBundle.GetDiscountPercent() != "" {
+		percent, err := domain.ParseAmount(pbBundle.GetDiscountPercent())
+		if err != nil {
+			return nil, fmt.Errorf("discount_percent: %w", err)
+		}
+		bundle.DiscountPercent = percent
+	}
+
+	for i, pbComponent := range pbBundle.GetComponents() {
+		productID, err := uuid.Parse(pbComponent.GetProductId())
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid component product ID format: %v", domain.ErrInvalidArgument, err)
+		}
+		component := domain.BundleComponent{ProductID: productID, Quantity: pbComponent.GetQuantity()}
+		if pbComponent.GetVariantId() != "" {
+			variantID, err := uuid.Parse(pbComponent.GetVariantId())
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid component variant ID format: %v", domain.ErrInvalidArgument, err)
+			}
+			component.VariantID = &variantID
+		}
+		bundle.Components[i] = component
+	}
+	return bundle, nil
+}
@@ -0,0 +1,48 @@
+package mapper
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CategoryToProto converts a domain category to its protobuf representation
+func CategoryToProto(category *domain.Category) *pb.Category {
+	pbCategory := &pb.Category{
+		Id:          category.ID.String(),
+		Name:        category.Name,
+		Description: category.Description,
+		Path:        category.Path,
+		CreatedAt:   timestamppb.New(category.CreatedAt),
+		UpdatedAt:   timestamppb.New(category.UpdatedAt),
+		Etag:        domain.FormatETag(category.Version),
+	}
+	if category.ParentID != nil {
+		pbCategory.ParentId = category.ParentID.String()
+	}
+	return pbCategory
+}
+
+// CategoryFromProto converts a protobuf category to the domain model. The
+// path, version and timestamps are left for the repository; the ID is only
+// read when set.
+func CategoryFromProto(pbCategory *pb.Category) (*domain.Category, error) {
+	parentID, err := OptionalUUID(pbCategory.GetParentId())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid parent category ID format: %v", domain.ErrInvalidArgument, err)
+	}
+	category := &domain.Category{
+		ParentID:    parentID,
+		Name:        pbCategory.GetName(),
+		Description: pbCategory.GetDescription(),
+	}
+	if pbCategory.GetId() != "" {
+		if category.ID, err = uuid.Parse(pbCategory.GetId()); err != nil {
+			return nil, fmt.Errorf("%w: invalid category ID format: %v", domain.ErrInvalidArgument, err)
+		}
+	}
+	return category, nil
+}
@@ -0,0 +1,79 @@
+package mapper
+
+import (
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var stockReservationStatuses = map[domain.StockReservationStatus]pb.StockReservation_Status{
+	domain.StockReservationPending:   pb.StockReservation_PENDING,
+	domain.StockReservationCommitted: pb.StockReservation_COMMITTED,
+	domain.StockReservationReleased:  pb.StockReservation_RELEASED,
+	domain.StockReservationExpired:   pb.StockReservation_EXPIRED,
+}
+
+var stockMovementKinds = map[domain.StockMovementKind]pb.StockMovement_Kind{
+	domain.StockMovementAdjustment:  pb.StockMovement_ADJUSTMENT,
+	domain.StockMovementReservation: pb.StockMovement_RESERVATION,
+	domain.StockMovementRelease:     pb.StockMovement_RELEASE,
+	domain.StockMovementCommit:      pb.StockMovement_COMMIT,
+	domain.StockMovementExpiry:      pb.StockMovement_EXPIRY,
+}
+
+// optionalID formats an ID that may be unset, as the empty string if it is
+func optionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// StockLevelToProto converts a stock level to its protobuf representation
+func StockLevelToProto(level *domain.StockLevel) *pb.StockLevel {
+	return &pb.StockLevel{
+		ProductId:   level.ProductID.String(),
+		VariantId:   optionalID(level.VariantID),
+		WarehouseId: level.WarehouseID.String(),
+		OnHand:      level.OnHand,
+		Reserved:    level.Reserved,
+		Available:   level.Available(),
+		UpdatedAt:   timestamppb.New(level.UpdatedAt),
+	}
+}
+
+// StockReservationToProto converts a stock reservation to its protobuf representation
+func StockReservationToProto(reservation *domain.StockReservation) *pb.StockReservation {
+	return &pb.StockReservation{
+		Id:          reservation.ID.String(),
+		ProductId:   reservation.ProductID.String(),
+		VariantId:   optionalID(reservation.VariantID),
+		WarehouseId: reservation.WarehouseID.String(),
+		Quantity:    reservation.Quantity,
+		Status:      stockReservationStatuses[reservation.Status],
+		Reference:   reservation.Reference,
+		ExpiresAt:   timestamppb.New(reservation.ExpiresAt),
+		CreatedAt:   timestamppb.New(reservation.CreatedAt),
+		UpdatedAt:   timestamppb.New(reservation.UpdatedAt),
+	}
+}
+
+// StockMovementToProto converts a stock ledger entry to its protobuf representation
+func StockMovementToProto(movement *domain.StockMovement) *pb.StockMovement {
+	return &pb.StockMovement{
+		Id:            movement.ID,
+		ProductId:     movement.ProductID.String(),
+		VariantId:     optionalID(movement.VariantID),
+		WarehouseId:   movement.WarehouseID.String(),
+		Kind:          stockMovementKinds[movement.Kind],
+		OnHandDelta:   movement.OnHandDelta,
+		ReservedDelta: movement.ReservedDelta,
+		OnHandAfter:   movement.OnHandAfter,
+		ReservedAfter: movement.ReservedAfter,
+		ReservationId: optionalID(movement.ReservationID),
+		Reason:        movement.Reason,
+		CreatedAt:     timestamppb.New(movement.CreatedAt),
+	}
+}
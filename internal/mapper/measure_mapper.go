@@ -0,0 +1,67 @@
+package mapper
+
+import (
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+)
+
+var weightUnits = map[domain.WeightUnit]pb.Weight_Unit{
+	domain.WeightUnitGram:     pb.Weight_G,
+	domain.WeightUnitKilogram: pb.Weight_KG,
+	domain.WeightUnitPound:    pb.Weight_LB,
+	domain.WeightUnitOunce:    pb.Weight_OZ,
+}
+
+var lengthUnits = map[domain.LengthUnit]pb.Dimensions_Unit{
+	domain.LengthUnitCentimeter: pb.Dimensions_CM,
+	domain.LengthUnitInch:       pb.Dimensions_IN,
+}
+
+// WeightToProto converts a weight, returning nil if it is unset
+func WeightToProto(weight domain.Weight) *pb.Weight {
+	if weight.IsZero() {
+		return nil
+	}
+	return &pb.Weight{Value: weight.Value, Unit: weightUnits[weight.Unit]}
+}
+
+// WeightFromProto converts a weight. An unknown unit is left empty, which
+// fails validation unless the weight is unset.
+func WeightFromProto(pbWeight *pb.Weight) domain.Weight {
+	weight := domain.Weight{Value: pbWeight.GetValue()}
+	for unit, pbUnit := range weightUnits {
+		if pbUnit == pbWeight.GetUnit() {
+			weight.Unit = unit
+		}
+	}
+	return weight
+}
+
+// DimensionsToProto converts dimensions, returning nil if they are unset
+func DimensionsToProto(dimensions domain.Dimensions) *pb.Dimensions {
+	if dimensions.IsZero() {
+		return nil
+	}
+	return &pb.Dimensions{
+		Length: dimensions.Length,
+		Width:  dimensions.Width,
+		Height: dimensions.Height,
+		Unit:   lengthUnits[dimensions.Unit],
+	}
+}
+
+// DimensionsFromProto converts dimensions. An unknown unit is left empty,
+// which fails validation unless the dimensions are unset.
+func DimensionsFromProto(pbDimensions *pb.Dimensions) domain.Dimensions {
+	dimensions := domain.Dimensions{
+		Length: pbDimensions.GetLength(),
+		Width:  pbDimensions.GetWidth(),
+		Height: pbDimensions.GetHeight(),
+	}
+	for unit, pbUnit := range lengthUnits {
+		if pbUnit == pbDimensions.GetUnit() {
+			dimensions.Unit = unit
+		}
+	}
+	return dimensions
+}
@@ -0,0 +1,36 @@
+package mapper
+
+import (
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProductMediaToProto converts product media to its protobuf representation
+func ProductMediaToProto(media *domain.ProductMedia) *pb.ProductMedia {
+	return &pb.ProductMedia{
+		Id:             media.ID.String(),
+		ProductId:      media.ProductID.String(),
+		Url:            media.URL,
+		FileName:       media.FileName,
+		ContentType:    media.ContentType,
+		SizeBytes:      media.Size,
+		Width:          media.Width,
+		Height:         media.Height,
+		ChecksumSha256: media.Checksum,
+		AltText:        media.AltText,
+		Position:       media.Position,
+		CreatedAt:      timestamppb.New(media.CreatedAt),
+		Etag:           domain.FormatETag(media.Version),
+	}
+}
+
+// ProductMediaListToProto converts the media of a product in order
+func ProductMediaListToProto(media []domain.ProductMedia) []*pb.ProductMedia {
+	pbMedia := make([]*pb.ProductMedia, len(media))
+	for i := range media {
+		pbMedia[i] = ProductMediaToProto(&media[i])
+	}
+	return pbMedia
+}
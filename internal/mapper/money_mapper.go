@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"product-microservice/internal/domain"
+	moneypb "product-microservice/proto/money"
+)
+
+// MoneyToProto converts a domain amount of money to its protobuf representation
+func MoneyToProto(money domain.Money) *moneypb.Money {
+	return &moneypb.Money{
+		CurrencyCode: money.Currency,
+		Units:        money.Amount.Units,
+		Nanos:        money.Amount.Nanos,
+	}
+}
+
+// MoneyFromProto converts a protobuf amount of money. A missing message maps
+// to the zero value, which fails validation for lack of a currency.
+func MoneyFromProto(money *moneypb.Money) domain.Money {
+	return domain.Money{
+		Amount:   domain.Amount{Units: money.GetUnits(), Nanos: money.GetNanos()},
+		Currency: money.GetCurrencyCode(),
+	}
+}
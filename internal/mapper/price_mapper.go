@@ -0,0 +1,159 @@
+package mapper
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PriceListEntryToProto converts a domain price list entry to its protobuf representation
+func PriceListEntryToProto(entry *domain.PriceListEntry) *pb.PriceListEntry {
+	if entry == nil {
+		return nil
+	}
+	pbEntry := &pb.PriceListEntry{
+		Id:        entry.ID.String(),
+		ProductId: entry.ProductID.String(),
+		Region:    entry.Region,
+		Price:     MoneyToProto(entry.Price),
+		Etag:      domain.FormatETag(entry.Version),
+	}
+	if entry.SubscriptionPlanID != nil {
+		pbEntry.SubscriptionPlanId = entry.SubscriptionPlanID.String()
+	}
+	if entry.EffectiveFrom != nil {
+		pbEntry.EffectiveFrom = timestamppb.New(*entry.EffectiveFrom)
+	}
+	if entry.EffectiveTo != nil {
+		pbEntry.EffectiveTo = timestamppb.New(*entry.EffectiveTo)
+	}
+	return pbEntry
+}
+
+// PriceListEntryFromProto converts a protobuf price list entry to the domain
+// model. The ID and etag are ignored since entries are only ever created from it.
+func PriceListEntryFromProto(pbEntry *pb.PriceListEntry) (*domain.PriceListEntry, error) {
+	productID, err := uuid.Parse(pbEntry.GetProductId())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid product ID format: %v", domain.ErrInvalidArgument, err)
+	}
+	planID, err := OptionalUUID(pbEntry.GetSubscriptionPlanId())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid subscription plan ID format: %v", domain.ErrInvalidArgument, err)
+	}
+
+	entry := &domain.PriceListEntry{
+		ProductID:          productID,
+		SubscriptionPlanID: planID,
+		Region:             pbEntry.GetRegion(),
+		Price:              MoneyFromProto(pbEntry.GetPrice()),
+	}
+	if pbEntry.GetEffectiveFrom() != nil {
+		from := pbEntry.GetEffectiveFrom().AsTime()
+		entry.EffectiveFrom = &from
+	}
+	if pbEntry.GetEffectiveTo() != nil {
+		to := pbEntry.GetEffectiveTo().AsTime()
+		entry.EffectiveTo = &to
+	}
+	return entry, nil
+}
+
+// ResolvedPriceToProto converts the answer to a price query
+func ResolvedPriceToProto(resolved *domain.ResolvedPrice) *pb.ResolvedPrice {
+	pbResolved := &pb.ResolvedPrice{
+		Price: MoneyToProto(resolved.Price),
+		Entry: PriceListEntryToProto(resolved.Entry),
+	}
+	switch resolved.Source {
+	case domain.PriceSourceRegion:
+		pbResolved.Source = pb.ResolvedPrice_REGION
+	case domain.PriceSourceDefaultRegion:
+		pbResolved.Source = pb.ResolvedPrice_DEFAULT_REGION
+	case domain.PriceSourceBasePrice:
+		pbResolved.Source = pb.ResolvedPrice_BASE_PRICE
+	}
+	return pbResolved
+}
+
+// OptionalUUID parses an ID that may be left empty
+func OptionalUUID(s string) (*uuid.UUID, error) {
+	if s == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// PriceChangeToProto converts a price history row to its protobuf representation
+func PriceChangeToProto(change *domain.PriceChange) *pb.PriceHistoryEntry {
+	pbEntry := &pb.PriceHistoryEntry{
+		ProductId: change.ProductID.String(),
+		Price:     MoneyToProto(change.Price),
+		ChangedAt: timestamppb.New(change.ChangedAt),
+	}
+	if change.SubscriptionPlanID != nil {
+		pbEntry.SubscriptionPlanId = change.SubscriptionPlanID.String()
+	}
+	if change.ScheduledPriceChangeID != nil {
+		pbEntry.ScheduledPriceChangeId = change.ScheduledPriceChangeID.String()
+	}
+	return pbEntry
+}
+
+// scheduledPriceChangeStatuses maps the domain statuses of scheduled price changes to protobuf
+var scheduledPriceChangeStatuses = map[domain.ScheduledPriceChangeStatus]pb.ScheduledPriceChange_Status{
+	domain.ScheduledPriceChangePending:   pb.ScheduledPriceChange_PENDING,
+	domain.ScheduledPriceChangeApplied:   pb.ScheduledPriceChange_APPLIED,
+	domain.ScheduledPriceChangeCancelled: pb.ScheduledPriceChange_CANCELLED,
+	domain.ScheduledPriceChangeFailed:    pb.ScheduledPriceChange_FAILED,
+}
+
+// ScheduledPriceChangeToProto converts a scheduled price change to its protobuf representation
+func ScheduledPriceChangeToProto(change *domain.ScheduledPriceChange) *pb.ScheduledPriceChange {
+	pbChange := &pb.ScheduledPriceChange{
+		Id:          change.ID.String(),
+		ProductId:   change.ProductID.String(),
+		Price:       MoneyToProto(change.Price),
+		EffectiveAt: timestamppb.New(change.EffectiveAt),
+		Status:      scheduledPriceChangeStatuses[change.Status],
+		Error:       change.Error,
+		Etag:        domain.FormatETag(change.Version),
+	}
+	if change.SubscriptionPlanID != nil {
+		pbChange.SubscriptionPlanId = change.SubscriptionPlanID.String()
+	}
+	if change.AppliedAt != nil {
+		pbChange.AppliedAt = timestamppb.New(*change.AppliedAt)
+	}
+	return pbChange
+}
+
+// ScheduledPriceChangeFromProto converts a protobuf scheduled price change to
+// the domain model. Only the fields a client may set are read.
+func ScheduledPriceChangeFromProto(pbChange *pb.ScheduledPriceChange) (*domain.ScheduledPriceChange, error) {
+	productID, err := uuid.Parse(pbChange.GetProductId())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid product ID format: %v", domain.ErrInvalidArgument, err)
+	}
+	planID, err := OptionalUUID(pbChange.GetSubscriptionPlanId())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid subscription plan ID format: %v", domain.ErrInvalidArgument, err)
+	}
+
+	change := &domain.ScheduledPriceChange{
+		ProductID:          productID,
+		SubscriptionPlanID: planID,
+		Price:              MoneyFromProto(pbChange.GetPrice()),
+	}
+	if pbChange.GetEffectiveAt() != nil {
+		change.EffectiveAt = pbChange.GetEffectiveAt().AsTime()
+	}
+	return change, nil
+}
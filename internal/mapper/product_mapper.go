@@ -0,0 +1,236 @@
+package mapper
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// productStatuses maps the domain lifecycle states of products to protobuf
+var productStatuses = map[domain.ProductStatus]pb.ProductStatus{
+	domain.ProductStatusDraft:     pb.ProductStatus_PRODUCT_STATUS_DRAFT,
+	domain.ProductStatusInReview:  pb.ProductStatus_PRODUCT_STATUS_IN_REVIEW,
+	domain.ProductStatusPublished: pb.ProductStatus_PRODUCT_STATUS_PUBLISHED,
+	domain.ProductStatusArchived:  pb.ProductStatus_PRODUCT_STATUS_ARCHIVED,
+}
+
+// ProductStatusFromProto converts a protobuf lifecycle state, returning the
+// empty status for PRODUCT_STATUS_UNSPECIFIED and unknown values
+func ProductStatusFromProto(status pb.ProductStatus) domain.ProductStatus {
+	for domainStatus, pbStatus := range productStatuses {
+		if pbStatus == status {
+			return domainStatus
+		}
+	}
+	return ""
+}
+
+// ProductToProto converts a domain product, including whichever type-specific
+// details are loaded, to its protobuf representation
+func ProductToProto(product *domain.Product) *pb.Product {
+	if product == nil {
+		return nil
+	}
+
+	pbProduct := &pb.Product{
+		Id:          product.ID.String(),
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       MoneyToProto(product.Price),
+		CreatedAt:   timestamppb.New(product.CreatedAt),
+		UpdatedAt:   timestamppb.New(product.UpdatedAt),
+		Etag:        domain.FormatETag(product.Version),
+		Locale:      product.Locale,
+		Status:      productStatuses[product.Status],
+	}
+	if product.PublishAt != nil {
+		pbProduct.PublishAt = timestamppb.New(*product.PublishAt)
+	}
+	if product.UnpublishAt != nil {
+		pbProduct.UnpublishAt = timestamppb.New(*product.UnpublishAt)
+	}
+	if product.PublishedAt != nil {
+		pbProduct.PublishedAt = timestamppb.New(*product.PublishedAt)
+	}
+	if product.DeletedAt.Valid {
+		pbProduct.DeletedAt = timestamppb.New(product.DeletedAt.Time)
+	}
+	if product.ExternalSKU != nil {
+		pbProduct.ExternalSku = *product.ExternalSKU
+	}
+	if len(product.OptionNames) > 0 {
+		pbProduct.OptionNames = []string(product.OptionNames)
+	}
+	if len(product.Tags) > 0 {
+		pbProduct.Tags = []string(product.Tags)
+	}
+	for _, id := range product.CategoryIDs() {
+		pbProduct.CategoryIds = append(pbProduct.CategoryIds, id.String())
+	}
+	if len(product.Media) > 0 {
+		pbProduct.Media = ProductMediaListToProto(product.Media)
+	}
+	if len(product.Attributes) > 0 {
+		pbProduct.Attributes = make(map[string]*pb.AttributeValue, len(product.Attributes))
+		for name, value := range product.Attributes {
+			pbProduct.Attributes[name] = AttributeValueToProto(value)
+		}
+	}
+
+	switch {
+	case product.DigitalProduct != nil:
+		pbProduct.ProductType = &pb.Product_DigitalProduct{
+			DigitalProduct: &pb.DigitalProduct{
+				FileSize:     product.DigitalProduct.FileSize,
+				DownloadLink: product.DigitalProduct.DownloadLink,
+			},
+		}
+	case product.PhysicalProduct != nil:
+		pbProduct.ProductType = &pb.Product_PhysicalProduct{
+			PhysicalProduct: &pb.PhysicalProduct{
+				Weight:     WeightToProto(product.PhysicalProduct.Weight),
+				Dimensions: DimensionsToProto(product.PhysicalProduct.Dimensions),
+			},
+		}
+	case product.SubscriptionProduct != nil:
+		pbProduct.ProductType = &pb.Product_SubscriptionProduct{
+			SubscriptionProduct: &pb.SubscriptionProduct{
+				SubscriptionPeriod: product.SubscriptionProduct.SubscriptionPeriod,
+				RenewalPrice:       MoneyToProto(product.SubscriptionProduct.RenewalPrice),
+			},
+		}
+	case product.BundleProduct != nil:
+		pbProduct.ProductType = &pb.Product_BundleProduct{
+			BundleProduct: BundleProductToProto(product.BundleProduct),
+		}
+	}
+	return pbProduct
+}
+
+// ProductsToProto converts a slice of domain products
+func ProductsToProto(products []domain.Product) []*pb.Product {
+	pbProducts := make([]*pb.Product, 0, len(products))
+	for i := range products {
+		pbProducts = append(pbProducts, ProductToProto(&products[i]))
+	}
+	return pbProducts
+}
+
+// ProductFromProto converts a protobuf product to the domain model. The ID,
+// version and timestamps are left for the caller or the database to fill in.
+func ProductFromProto(pbProduct *pb.Product) (*domain.Product, error) {
+	product := &domain.Product{
+		Name:        pbProduct.GetName(),
+		Description: pbProduct.GetDescription(),
+		Price:       MoneyFromProto(pbProduct.GetPrice()),
+		OptionNames: pbProduct.GetOptionNames(),
+		Tags:        pbProduct.GetTags(),
+		Status:      ProductStatusFromProto(pbProduct.GetStatus()),
+	}
+	for _, categoryID := range pbProduct.GetCategoryIds() {
+		id, err := uuid.Parse(categoryID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid category ID format: %v", domain.ErrInvalidArgument, err)
+		}
+		product.Categories = append(product.Categories, domain.Category{ID: id})
+	}
+	if len(pbProduct.GetAttributes()) > 0 {
+		product.Attributes = make(domain.AttributeValues, len(pbProduct.GetAttributes()))
+		for name, value := range pbProduct.GetAttributes() {
+			product.Attributes[name] = AttributeValueFromProto(value)
+		}
+	}
+	if sku := pbProduct.GetExternalSku(); sku != "" {
+		product.ExternalSKU = &sku
+	}
+
+	switch pt := pbProduct.GetProductType().(type) {
+	case nil:
+	case *pb.Product_DigitalProduct:
+		product.DigitalProduct = &domain.DigitalProduct{
+			FileSize:     pt.DigitalProduct.GetFileSize(),
+			DownloadLink: pt.DigitalProduct.GetDownloadLink(),
+		}
+	case *pb.Product_PhysicalProduct:
+		product.PhysicalProduct = &domain.PhysicalProduct{
+			Weight:     WeightFromProto(pt.PhysicalProduct.GetWeight()),
+			Dimensions: DimensionsFromProto(pt.PhysicalProduct.GetDimensions()),
+		}
+	case *pb.Product_SubscriptionProduct:
+		product.SubscriptionProduct = &domain.SubscriptionProduct{
+			SubscriptionPeriod: pt.SubscriptionProduct.GetSubscriptionPeriod(),
+			RenewalPrice:       MoneyFromProto(pt.SubscriptionProduct.GetRenewalPrice()),
+		}
+	case *pb.Product_BundleProduct:
+		bundle, err := BundleProductFromProto(pt.BundleProduct)
+		if err != nil {
+			return nil, err
+		}
+		product.BundleProduct = bundle
+	default:
+		return nil, fmt.Errorf("%w: unsupported product type", domain.ErrInvalidArgument)
+	}
+	return product, nil
+}
+
+// ExportedProductToProto converts an exported product and its subscription
+// plans to the message streamed by ExportProducts
+func ExportedProductToProto(exported *domain.ExportedProduct) *pb.ExportedProduct {
+	plans := make([]*pb.SubscriptionPlan, len(exported.SubscriptionPlans))
+	for i := range exported.SubscriptionPlans {
+		plans[i] = SubscriptionPlanToProto(&exported.SubscriptionPlans[i])
+	}
+	return &pb.ExportedProduct{
+		Product:           ProductToProto(&exported.Product),
+		SubscriptionPlans: plans,
+	}
+}
+
+// SubscriptionPlanToProto converts a domain subscription plan to the plan
+// message of the product API
+func SubscriptionPlanToProto(plan *domain.SubscriptionPlan) *pb.SubscriptionPlan {
+	return &pb.SubscriptionPlan{
+		Id:        plan.ID.String(),
+		ProductId: plan.ProductID.String(),
+		PlanName:  plan.PlanName,
+		Duration:  int32(plan.Duration),
+		Price:     MoneyToProto(plan.Price),
+		Etag:      domain.FormatETag(plan.Version),
+	}
+}
+
+var productEventTypes = map[domain.ProductEventType]pb.ProductEvent_Type{
+	domain.ProductEventCreated: pb.ProductEvent_CREATED,
+	domain.ProductEventUpdated: pb.ProductEvent_UPDATED,
+	domain.ProductEventDeleted: pb.ProductEvent_DELETED,
+}
+
+// ProductEventToProto converts a change log entry to the message streamed by
+// WatchProducts. A resource that no longer exists is sent with just its ids.
+func ProductEventToProto(event *domain.ProductEvent, resumeToken string) *pb.ProductEvent {
+	pbEvent := &pb.ProductEvent{
+		Type:        productEventTypes[event.Type],
+		ResumeToken: resumeToken,
+		EventTime:   timestamppb.New(event.CreatedAt),
+		ProductId:   event.ProductID.String(),
+	}
+
+	switch event.Resource {
+	case domain.ProductEventResourceProduct:
+		product := ProductToProto(event.Product)
+		if product == nil {
+			product = &pb.Product{Id: event.ResourceID.String()}
+		}
+		pbEvent.Resource = &pb.ProductEvent_Product{Product: product}
+	case domain.ProductEventResourceSubscriptionPlan:
+		plan := &pb.SubscriptionPlan{Id: event.ResourceID.String(), ProductId: event.ProductID.String()}
+		if event.SubscriptionPlan != nil {
+			plan = SubscriptionPlanToProto(event.SubscriptionPlan)
+		}
+		pbEvent.Resource = &pb.ProductEvent_SubscriptionPlan{SubscriptionPlan: plan}
+	}
+	return pbEvent
+}
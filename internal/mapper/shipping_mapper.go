@@ -0,0 +1,31 @@
+package mapper
+
+import (
+	"product-microservice/internal/domain"
+	"product-microservice/internal/shipping"
+	pb "product-microservice/proto/product"
+)
+
+// kilograms returns a weight in kilograms
+func kilograms(value float64) *pb.Weight {
+	return &pb.Weight{Value: value, Unit: weightUnits[domain.WeightUnitKilogram]}
+}
+
+// ShippingEstimateToProto converts a shipping estimate to its protobuf representation
+func ShippingEstimateToProto(estimate *shipping.Estimate) *pb.EstimateShippingResponse {
+	response := &pb.EstimateShippingResponse{
+		Zone:         estimate.Zone,
+		ActualWeight: kilograms(estimate.ActualWeightKg),
+		Quotes:       make([]*pb.ShippingQuote, len(estimate.Quotes)),
+	}
+	for i, quote := range estimate.Quotes {
+		response.Quotes[i] = &pb.ShippingQuote{
+			CarrierCode:       quote.CarrierCode,
+			CarrierName:       quote.CarrierName,
+			DimensionalWeight: kilograms(quote.DimensionalWeightKg),
+			BillableWeight:    kilograms(quote.BillableWeightKg),
+			Price:             MoneyToProto(quote.Price),
+		}
+	}
+	return response
+}
@@ -0,0 +1,32 @@
+package mapper
+
+import (
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProductTranslationsToProto converts the translations of a product in order
+func ProductTranslationsToProto(translations []domain.ProductTranslation) []*pb.ProductTranslation {
+	pbTranslations := make([]*pb.ProductTranslation, len(translations))
+	for i, translation := range translations {
+		pbTranslations[i] = &pb.ProductTranslation{
+			Locale:      translation.Locale,
+			Name:        translation.Name,
+			Description: translation.Description,
+			UpdatedAt:   timestamppb.New(translation.UpdatedAt),
+		}
+	}
+	return pbTranslations
+}
+
+// ProductTranslationFromProto converts a protobuf translation to the domain
+// model; the locale is normalised by the service
+func ProductTranslationFromProto(pbTranslation *pb.ProductTranslation) domain.ProductTranslation {
+	return domain.ProductTranslation{
+		Locale:      pbTranslation.GetLocale(),
+		Name:        pbTranslation.GetName(),
+		Description: pbTranslation.GetDescription(),
+	}
+}
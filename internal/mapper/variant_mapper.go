@@ -0,0 +1,79 @@
+package mapper
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProductVariantToProto converts a domain variant to its protobuf representation
+func ProductVariantToProto(variant *domain.ProductVariant) *pb.ProductVariant {
+	pbVariant := &pb.ProductVariant{
+		Id:        variant.ID.String(),
+		ProductId: variant.ProductID.String(),
+		Sku:       variant.SKU,
+		Options:   variant.Options,
+		CreatedAt: timestamppb.New(variant.CreatedAt),
+		UpdatedAt: timestamppb.New(variant.UpdatedAt),
+		Etag:      domain.FormatETag(variant.Version),
+	}
+	if variant.Barcode != nil {
+		pbVariant.Barcode = *variant.Barcode
+	}
+	if price := variant.PriceOverride(); price != nil {
+		pbVariant.PriceOverride = MoneyToProto(*price)
+	}
+	if weight := variant.WeightOverride(); weight != nil {
+		pbVariant.WeightOverride = WeightToProto(*weight)
+	}
+	return pbVariant
+}
+
+// ProductVariantFromProto converts a protobuf variant to the domain model.
+// The ID, version and timestamps are left for the caller or the database.
+func ProductVariantFromProto(pbVariant *pb.ProductVariant) (*domain.ProductVariant, error) {
+	productID, err := OptionalUUID(pbVariant.GetProductId())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid product ID format: %v", domain.ErrInvalidArgument, err)
+	}
+
+	variant := &domain.ProductVariant{
+		SKU:     pbVariant.GetSku(),
+		Options: domain.VariantOptions(pbVariant.GetOptions()),
+	}
+	if productID != nil {
+		variant.ProductID = *productID
+	}
+	if barcode := pbVariant.GetBarcode(); barcode != "" {
+		variant.Barcode = &barcode
+	}
+	if pbVariant.GetPriceOverride() != nil {
+		price := MoneyFromProto(pbVariant.GetPriceOverride())
+		variant.SetPriceOverride(&price)
+	}
+	if pbVariant.GetWeightOverride() != nil {
+		weight := WeightFromProto(pbVariant.GetWeightOverride())
+		variant.SetWeightOverride(&weight)
+	}
+	return variant, nil
+}
+
+// ProductVariantUpdateFromProto converts a variant update to the domain
+// variant carrying the target ID and the version the client read
+func ProductVariantUpdateFromProto(pbVariant *pb.ProductVariant) (uuid.UUID, *domain.ProductVariant, error) {
+	id, err := uuid.Parse(pbVariant.GetId())
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("%w: invalid product variant ID format: %v", domain.ErrInvalidArgument, err)
+	}
+	variant, err := ProductVariantFromProto(pbVariant)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if variant.Version, err = domain.ParseETag(pbVariant.GetEtag()); err != nil {
+		return uuid.Nil, nil, err
+	}
+	return id, variant, nil
+}
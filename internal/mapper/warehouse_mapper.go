@@ -0,0 +1,69 @@
+package mapper
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AddressToProto converts a domain address to its protobuf representation
+func AddressToProto(address domain.Address) *pb.Address {
+	return &pb.Address{
+		Line1:       address.Line1,
+		Line2:       address.Line2,
+		City:        address.City,
+		Region:      address.Region,
+		PostalCode:  address.PostalCode,
+		CountryCode: address.CountryCode,
+	}
+}
+
+// AddressFromProto converts a protobuf address to the domain model
+func AddressFromProto(pbAddress *pb.Address) domain.Address {
+	return domain.Address{
+		Line1:       pbAddress.GetLine1(),
+		Line2:       pbAddress.GetLine2(),
+		City:        pbAddress.GetCity(),
+		Region:      pbAddress.GetRegion(),
+		PostalCode:  pbAddress.GetPostalCode(),
+		CountryCode: pbAddress.GetCountryCode(),
+	}
+}
+
+// WarehouseToProto converts a domain warehouse to its protobuf representation
+func WarehouseToProto(warehouse *domain.Warehouse) *pb.Warehouse {
+	return &pb.Warehouse{
+		Id:        warehouse.ID.String(),
+		Name:      warehouse.Name,
+		Address:   AddressToProto(warehouse.Address),
+		TimeZone:  warehouse.TimeZone,
+		Priority:  warehouse.Priority,
+		Active:    &warehouse.Active,
+		CreatedAt: timestamppb.New(warehouse.CreatedAt),
+		UpdatedAt: timestamppb.New(warehouse.UpdatedAt),
+		Etag:      domain.FormatETag(warehouse.Version),
+	}
+}
+
+// WarehouseFromProto converts a protobuf warehouse to the domain model. An
+// unset active flag means active. The version and timestamps are left for
+// the repository; the ID is only read when set.
+func WarehouseFromProto(pbWarehouse *pb.Warehouse) (*domain.Warehouse, error) {
+	warehouse := &domain.Warehouse{
+		Name:     pbWarehouse.GetName(),
+		Address:  AddressFromProto(pbWarehouse.GetAddress()),
+		TimeZone: pbWarehouse.GetTimeZone(),
+		Priority: pbWarehouse.GetPriority(),
+		Active:   pbWarehouse.Active == nil || pbWarehouse.GetActive(),
+	}
+	if pbWarehouse.GetId() != "" {
+		var err error
+		if warehouse.ID, err = uuid.Parse(pbWarehouse.GetId()); err != nil {
+			return nil, fmt.Errorf("%w: invalid warehouse ID format: %v", domain.ErrInvalidArgument, err)
+		}
+	}
+	return warehouse, nil
+}
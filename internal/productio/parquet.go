@@ -0,0 +1,421 @@
+package productio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+	"time"
+)
+
+// The Parquet writer produces the simplest file layout every reader
+// understands: flat OPTIONAL columns, PLAIN encoded values, RLE definition
+// levels, no compression and one data page per column chunk. Rows are
+// buffered and flushed as a row group every parquetRowGroupSize rows.
+
+// parquetRowGroupSize is the number of rows buffered per row group
+const parquetRowGroupSize = 10000
+
+var parquetMagic = []byte("PAR1")
+
+// Amounts are written as DECIMAL(28, 9): the unscaled number of nanos as a
+// 12 byte big-endian two's complement integer, which holds any domain.Amount
+const (
+	parquetDecimalPrecision = 28
+	parquetDecimalScale     = 9
+	parquetDecimalLength    = 12
+)
+
+// parquetDecimalModulus is 2^96, added to negative values to get their two's
+// complement
+var parquetDecimalModulus = new(big.Int).Lsh(big.NewInt(1), 8*parquetDecimalLength)
+
+// Parquet physical types, converted types and enums from parquet.thrift
+const (
+	parquetInt32             int32 = 1
+	parquetInt64             int32 = 2
+	parquetDouble            int32 = 5
+	parquetByteArray         int32 = 6
+	parquetFixedLenByteArray int32 = 7
+
+	parquetUTF8            int32 = 0
+	parquetDecimal         int32 = 5
+	parquetTimestampMicros int32 = 10
+
+	parquetOptional     int32 = 1
+	parquetPlain        int32 = 0
+	parquetRLE          int32 = 3
+	parquetUncompressed int32 = 0
+	parquetDataPage     int32 = 0
+)
+
+// parquetColumn buffers the values of one column for the current row group
+type parquetColumn struct {
+	field  exportField
+	values bytes.Buffer
+	// levels holds the definition level of every row: 1 if set, 0 if null
+	levels []bool
+}
+
+func (c *parquetColumn) physicalType() int32 {
+	switch c.field.kind {
+	case kindInt32:
+		return parquetInt32
+	case kindDouble:
+		return parquetDouble
+	case kindTimestamp:
+		return parquetInt64
+	case kindDecimal:
+		return parquetFixedLenByteArray
+	}
+	return parquetByteArray
+}
+
+func (c *parquetColumn) append(value interface{}) {
+	c.levels = append(c.levels, value != nil)
+	var scratch [8]byte
+	switch value := value.(type) {
+	case string:
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(len(value)))
+		c.values.Write(scratch[:4])
+		c.values.WriteString(value)
+	case int32:
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(value))
+		c.values.Write(scratch[:4])
+	case float64:
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(value))
+		c.values.Write(scratch[:])
+	case time.Time:
+		binary.LittleEndian.PutUint64(scratch[:], uint64(value.UnixMicro()))
+		c.values.Write(scratch[:])
+	case domain.Amount:
+		unscaled := value.UnscaledNanos()
+		if unscaled.Sign() < 0 {
+			unscaled.Add(unscaled, parquetDecimalModulus)
+		}
+		var decimal [parquetDecimalLength]byte
+		unscaled.FillBytes(decimal[:])
+		c.values.Write(decimal[:])
+	}
+}
+
+// page encodes the buffered column as a single data page body
+func (c *parquetColumn) page() []byte {
+	levels := encodeLevels(c.levels)
+	var body bytes.Buffer
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(levels)))
+	body.Write(size[:])
+	body.Write(levels)
+	body.Write(c.values.Bytes())
+	return body.Bytes()
+}
+
+func (c *parquetColumn) reset() {
+	c.values.Reset()
+	c.levels = c.levels[:0]
+}
+
+// encodeLevels encodes definition levels of bit width 1 with the RLE half of
+// the RLE/bit-packing hybrid encoding
+func encodeLevels(levels []bool) []byte {
+	var out []byte
+	for start := 0; start < len(levels); {
+		end := start
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		out = binary.AppendUvarint(out, uint64(end-start)<<1)
+		if levels[start] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		start = end
+	}
+	return out
+}
+
+type parquetWriter struct {
+	writer    *countingWriter
+	columns   []*parquetColumn
+	rows      int
+	totalRows int64
+	rowGroups [][]columnChunk
+	groupRows []int
+	err       error
+}
+
+// columnChunk records where a column chunk was written for the footer
+type columnChunk struct {
+	offset int64
+	size   int64
+	values int
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	columns := make([]*parquetColumn, len(exportFields))
+	for i, field := range exportFields {
+		columns[i] = &parquetColumn{field: field}
+	}
+	return &parquetWriter{writer: &countingWriter{writer: w}, columns: columns}
+}
+
+func (w *parquetWriter) Write(exported *pb.ExportedProduct) error {
+	if w.err != nil {
+		return w.err
+	}
+	values, err := flatten(exported)
+	if err != nil {
+		return err
+	}
+	for i, value := range values {
+		w.columns[i].append(value)
+	}
+	w.rows++
+	if w.rows >= parquetRowGroupSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows as a row group
+func (w *parquetWriter) flush() error {
+	if w.writer.count == 0 {
+		if _, err := w.writer.Write(parquetMagic); err != nil {
+			w.err = err
+			return err
+		}
+	}
+	if w.rows == 0 {
+		return nil
+	}
+
+	chunks := make([]columnChunk, len(w.columns))
+	for i, column := range w.columns {
+		page := column.page()
+		header := newThriftWriter()
+		header.structBegin()
+		header.i32Field(1, parquetDataPage)
+		header.i32Field(2, int32(len(page)))
+		header.i32Field(3, int32(len(page)))
+		header.fieldBegin(5, thriftStruct)
+		header.structBegin()
+		header.i32Field(1, int32(w.rows))
+		header.i32Field(2, parquetPlain)
+		header.i32Field(3, parquetRLE)
+		header.i32Field(4, parquetRLE)
+		header.structEnd()
+		header.structEnd()
+
+		offset := w.writer.count
+		if _, err := w.writer.Write(header.bytes()); err != nil {
+			w.err = err
+			return err
+		}
+		if _, err := w.writer.Write(page); err != nil {
+			w.err = err
+			return err
+		}
+		chunks[i] = columnChunk{offset: offset, size: w.writer.count - offset, values: w.rows}
+		column.reset()
+	}
+	w.rowGroups = append(w.rowGroups, chunks)
+	w.groupRows = append(w.groupRows, w.rows)
+	w.totalRows += int64(w.rows)
+	w.rows = 0
+	return nil
+}
+
+// Close flushes the last row group and writes the file footer
+func (w *parquetWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	footer := newThriftWriter()
+	footer.structBegin()
+	footer.i32Field(1, 1)
+
+	footer.fieldBegin(2, thriftList)
+	footer.listBegin(thriftStruct, len(w.columns)+1)
+	footer.structBegin()
+	footer.binaryField(4, "schema")
+	footer.i32Field(5, int32(len(w.columns)))
+	footer.structEnd()
+	for _, column := range w.columns {
+		footer.structBegin()
+		footer.i32Field(1, column.physicalType())
+		if column.field.kind == kindDecimal {
+			footer.i32Field(2, parquetDecimalLength)
+		}
+		footer.i32Field(3, parquetOptional)
+		footer.binaryField(4, column.field.name)
+		switch column.field.kind {
+		case kindString:
+			footer.i32Field(6, parquetUTF8)
+		case kindTimestamp:
+			footer.i32Field(6, parquetTimestampMicros)
+		case kindDecimal:
+			footer.i32Field(6, parquetDecimal)
+			footer.i32Field(7, parquetDecimalScale)
+			footer.i32Field(8, parquetDecimalPrecision)
+		}
+		footer.structEnd()
+	}
+
+	footer.i64Field(3, w.totalRows)
+
+	footer.fieldBegin(4, thriftList)
+	footer.listBegin(thriftStruct, len(w.rowGroups))
+	for g, chunks := range w.rowGroups {
+		var groupSize int64
+		for _, chunk := range chunks {
+			groupSize += chunk.size
+		}
+		footer.structBegin()
+		footer.fieldBegin(1, thriftList)
+		footer.listBegin(thriftStruct, len(chunks))
+		for i, chunk := range chunks {
+			column := w.columns[i]
+			footer.structBegin()
+			footer.i64Field(2, chunk.offset)
+			footer.fieldBegin(3, thriftStruct)
+			footer.structBegin()
+			footer.i32Field(1, column.physicalType())
+			footer.fieldBegin(2, thriftList)
+			footer.listBegin(thriftI32, 2)
+			footer.i32(parquetPlain)
+			footer.i32(parquetRLE)
+			footer.fieldBegin(3, thriftList)
+			footer.listBegin(thriftBinary, 1)
+			footer.binary(column.field.name)
+			footer.i32Field(4, parquetUncompressed)
+			footer.i64Field(5, int64(chunk.values))
+			footer.i64Field(6, chunk.size)
+			footer.i64Field(7, chunk.size)
+			footer.i64Field(9, chunk.offset)
+			footer.structEnd()
+			footer.structEnd()
+		}
+		footer.i64Field(2, groupSize)
+		footer.i64Field(3, int64(w.groupRows[g]))
+		footer.structEnd()
+	}
+
+	footer.binaryField(6, "product-microservice")
+	footer.structEnd()
+
+	metadata := footer.bytes()
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(metadata)))
+	for _, chunk := range [][]byte{metadata, size[:], parquetMagic} {
+		if _, err := w.writer.Write(chunk); err != nil {
+			w.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+// countingWriter tracks the file offset needed by the Parquet footer
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
+
+// Thrift compact protocol type ids
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+// thriftWriter encodes the Parquet metadata structs with the Thrift compact
+// protocol. Only the parts the writer needs are implemented.
+type thriftWriter struct {
+	buf []byte
+	// lastField holds the previous field id of every open struct, since
+	// field ids are delta encoded
+	lastField []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{}
+}
+
+func (t *thriftWriter) bytes() []byte {
+	return t.buf
+}
+
+func (t *thriftWriter) structBegin() {
+	t.lastField = append(t.lastField, 0)
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf = append(t.buf, 0)
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+func (t *thriftWriter) fieldBegin(id int16, fieldType byte) {
+	last := &t.lastField[len(t.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|fieldType)
+	} else {
+		t.buf = append(t.buf, fieldType)
+		t.buf = binary.AppendVarint(t.buf, int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) listBegin(elementType byte, size int) {
+	if size < 15 {
+		t.buf = append(t.buf, byte(size)<<4|elementType)
+		return
+	}
+	t.buf = append(t.buf, 0xf0|elementType)
+	t.buf = binary.AppendUvarint(t.buf, uint64(size))
+}
+
+// i32 and i64 are zigzag varints, which is what binary.AppendVarint writes
+func (t *thriftWriter) i32(v int32) {
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+func (t *thriftWriter) i64(v int64) {
+	t.buf = binary.AppendVarint(t.buf, v)
+}
+
+func (t *thriftWriter) binary(s string) {
+	t.buf = binary.AppendUvarint(t.buf, uint64(len(s)))
+	t.buf = append(t.buf, s...)
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldBegin(id, thriftI32)
+	t.i32(v)
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldBegin(id, thriftI64)
+	t.i64(v)
+}
+
+func (t *thriftWriter) binaryField(id int16, s string) {
+	t.fieldBegin(id, thriftBinary)
+	t.binary(s)
+}
@@ -0,0 +1,60 @@
+// Package productio reads and writes product catalog files. Rows are
+// represented as protobuf Product messages so the same validation and
+// mapping apply as for products received over gRPC.
+package productio
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format identifies a catalog file format
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	// FormatParquet is only supported for writing
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat parses a format name, accepting file extensions as aliases
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	case "parquet":
+		return FormatParquet, nil
+	}
+	return "", fmt.Errorf("unsupported format %q", name)
+}
+
+// FormatFromPath infers the format of a file from its extension
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(filepath.Ext(path))
+}
+
+// RowError reports a single malformed row. Readers return it for rows that
+// can be skipped; any other error means the file cannot be read further.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// IsRowError reports whether err only affects a single row
+func IsRowError(err error) bool {
+	var rowErr *RowError
+	return errors.As(err, &rowErr)
+}
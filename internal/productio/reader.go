@@ -0,0 +1,272 @@
+package productio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"product-microservice/internal/domain"
+	moneypb "product-microservice/proto/money"
+	pb "product-microservice/proto/product"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Columns are the CSV header names understood by the reader and written by
+// the writer. The type column selects which of the detail columns apply.
+// Prices are plain decimals in the currency given by the currency column.
+// Weights and dimensions are decimals in the lower-case unit of their unit
+// column, such as kg or cm.
+var Columns = []string{
+	"external_sku",
+	"type",
+	"name",
+	"description",
+	"price",
+	"currency",
+	"file_size",
+	"download_link",
+	"weight",
+	"weight_unit",
+	"length",
+	"width",
+	"height",
+	"dimensions_unit",
+	"subscription_period",
+	"renewal_price",
+}
+
+// maxLineSize bounds a single JSON Lines row
+const maxLineSize = 1 << 20
+
+// Reader reads products from a catalog file one row at a time
+type Reader interface {
+	// Read returns the next product and the 1-based line it starts on. It
+	// returns io.EOF after the last row and a *RowError for a malformed row
+	// that can be skipped.
+	Read() (*pb.Product, int, error)
+}
+
+// NewReader returns a reader for the given format
+func NewReader(format Format, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &jsonlReader{scanner: scanner}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("csv file is empty")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	known := make(map[string]bool, len(Columns))
+	for _, column := range Columns {
+		known[column] = true
+	}
+	// Exports carry extra read-only columns; they are accepted and ignored
+	for _, field := range exportFields {
+		known[field.name] = true
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		columns[name] = i
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Read() (*pb.Product, int, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return nil, 0, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		// The reader resynchronises on the next line
+		return nil, parseErr.StartLine, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	product, err := r.product(record)
+	if err != nil {
+		return nil, line, &RowError{Line: line, Err: err}
+	}
+	return product, line, nil
+}
+
+// product builds a product from a CSV record
+func (r *csvReader) product(record []string) (*pb.Product, error) {
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	money := func(name string) (*moneypb.Money, error) {
+		amount := domain.Amount{}
+		if value := field(name); value != "" {
+			var err error
+			if amount, err = domain.ParseAmount(value); err != nil {
+				return nil, fmt.Errorf("invalid %s %q", name, value)
+			}
+		}
+		return &moneypb.Money{CurrencyCode: strings.ToUpper(field("currency")), Units: amount.Units, Nanos: amount.Nanos}, nil
+	}
+	float := func(name string) (float64, error) {
+		value := field(name)
+		if value == "" {
+			return 0, nil
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", name, value)
+		}
+		return parsed, nil
+	}
+
+	price, err := money("price")
+	if err != nil {
+		return nil, err
+	}
+	product := &pb.Product{
+		ExternalSku: field("external_sku"),
+		Name:        field("name"),
+		Description: field("description"),
+		Price:       price,
+	}
+
+	switch productType := strings.ToLower(field("type")); productType {
+	case "":
+	case "digital":
+		fileSize, err := strconv.ParseInt(orZero(field("file_size")), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file_size %q", field("file_size"))
+		}
+		product.ProductType = &pb.Product_DigitalProduct{DigitalProduct: &pb.DigitalProduct{
+			FileSize:     int32(fileSize),
+			DownloadLink: field("download_link"),
+		}}
+	case "physical":
+		physical, err := physicalDetails(field, float)
+		if err != nil {
+			return nil, err
+		}
+		product.ProductType = &pb.Product_PhysicalProduct{PhysicalProduct: physical}
+	case "subscription":
+		renewalPrice, err := money("renewal_price")
+		if err != nil {
+			return nil, err
+		}
+		product.ProductType = &pb.Product_SubscriptionProduct{SubscriptionProduct: &pb.SubscriptionProduct{
+			SubscriptionPeriod: field("subscription_period"),
+			RenewalPrice:       renewalPrice,
+		}}
+	case "bundle":
+		return nil, fmt.Errorf("bundle products can only be imported from JSONL")
+	default:
+		return nil, fmt.Errorf("unknown product type %q", productType)
+	}
+	return product, nil
+}
+
+// physicalDetails reads the weight and dimensions columns, each of which is
+// only set when a value or its unit is given
+func physicalDetails(field func(string) string, float func(string) (float64, error)) (*pb.PhysicalProduct, error) {
+	physical := &pb.PhysicalProduct{}
+	if field("weight") != "" || field("weight_unit") != "" {
+		value, err := float("weight")
+		if err != nil {
+			return nil, err
+		}
+		unit, ok := pb.Weight_Unit_value[strings.ToUpper(field("weight_unit"))]
+		if !ok || unit == 0 {
+			return nil, fmt.Errorf("invalid weight_unit %q", field("weight_unit"))
+		}
+		physical.Weight = &pb.Weight{Value: value, Unit: pb.Weight_Unit(unit)}
+	}
+	if field("length") != "" || field("width") != "" || field("height") != "" || field("dimensions_unit") != "" {
+		dimensions := &pb.Dimensions{}
+		for _, side := range []struct {
+			name  string
+			value *float64
+		}{{"length", &dimensions.Length}, {"width", &dimensions.Width}, {"height", &dimensions.Height}} {
+			value, err := float(side.name)
+			if err != nil {
+				return nil, err
+			}
+			*side.value = value
+		}
+		unit, ok := pb.Dimensions_Unit_value[strings.ToUpper(field("dimensions_unit"))]
+		if !ok || unit == 0 {
+			return nil, fmt.Errorf("invalid dimensions_unit %q", field("dimensions_unit"))
+		}
+		dimensions.Unit = pb.Dimensions_Unit(unit)
+		physical.Dimensions = dimensions
+	}
+	return physical, nil
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *jsonlReader) Read() (*pb.Product, int, error) {
+	for r.scanner.Scan() {
+		r.line++
+		raw := r.scanner.Bytes()
+		if len(strings.TrimSpace(string(raw))) == 0 {
+			continue
+		}
+
+		product := &pb.Product{}
+		if err := protojson.Unmarshal(raw, product); err != nil {
+			// Exported files wrap every product in an ExportedProduct
+			exported := &pb.ExportedProduct{}
+			if protojson.Unmarshal(raw, exported) != nil || exported.GetProduct() == nil {
+				return nil, r.line, &RowError{Line: r.line, Err: err}
+			}
+			product = exported.GetProduct()
+		}
+		return product, r.line, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	return nil, 0, io.EOF
+}
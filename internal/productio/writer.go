@@ -0,0 +1,291 @@
+package productio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"product-microservice/internal/domain"
+	moneypb "product-microservice/proto/money"
+	pb "product-microservice/proto/product"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Writer writes exported products to a catalog file
+type Writer interface {
+	Write(product *pb.ExportedProduct) error
+	// Close flushes anything still buffered. It does not close the
+	// underlying io.Writer.
+	Close() error
+}
+
+// NewWriter returns a writer for the given format
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSONL:
+		return &jsonlWriter{writer: bufio.NewWriter(w)}, nil
+	case FormatParquet:
+		return newParquetWriter(w), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// fieldKind is the type of a flattened export column
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindInt32
+	kindDouble
+	kindTimestamp
+	// kindDecimal values are domain.Amount
+	kindDecimal
+)
+
+// exportField is one column of the flat layout shared by the CSV and Parquet
+// writers. get returns nil when the product has no value for the column.
+type exportField struct {
+	name string
+	kind fieldKind
+	get  func(exported *pb.ExportedProduct) (interface{}, error)
+}
+
+// exportFields lists the exported columns: the importable Columns surrounded
+// by read-only metadata, which the CSV reader accepts and ignores so an
+// export can be imported again
+var exportFields = []exportField{
+	{"id", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		return e.GetProduct().GetId(), nil
+	}},
+	{"external_sku", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		return nonEmpty(e.GetProduct().GetExternalSku()), nil
+	}},
+	{"type", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		return nonEmpty(productType(e.GetProduct())), nil
+	}},
+	{"name", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		return e.GetProduct().GetName(), nil
+	}},
+	{"description", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		return e.GetProduct().GetDescription(), nil
+	}},
+	{"price", kindDecimal, func(e *pb.ExportedProduct) (interface{}, error) {
+		return amount(e.GetProduct().GetPrice()), nil
+	}},
+	{"currency", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		return nonEmpty(e.GetProduct().GetPrice().GetCurrencyCode()), nil
+	}},
+	{"file_size", kindInt32, func(e *pb.ExportedProduct) (interface{}, error) {
+		if digital := e.GetProduct().GetDigitalProduct(); digital != nil {
+			return digital.GetFileSize(), nil
+		}
+		return nil, nil
+	}},
+	{"download_link", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		if digital := e.GetProduct().GetDigitalProduct(); digital != nil {
+			return digital.GetDownloadLink(), nil
+		}
+		return nil, nil
+	}},
+	{"weight", kindDouble, func(e *pb.ExportedProduct) (interface{}, error) {
+		if weight := e.GetProduct().GetPhysicalProduct().GetWeight(); weight != nil {
+			return weight.GetValue(), nil
+		}
+		return nil, nil
+	}},
+	{"weight_unit", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		if weight := e.GetProduct().GetPhysicalProduct().GetWeight(); weight != nil {
+			return strings.ToLower(weight.GetUnit().String()), nil
+		}
+		return nil, nil
+	}},
+	{"length", kindDouble, func(e *pb.ExportedProduct) (interface{}, error) {
+		if dimensions := e.GetProduct().GetPhysicalProduct().GetDimensions(); dimensions != nil {
+			return dimensions.GetLength(), nil
+		}
+		return nil, nil
+	}},
+	{"width", kindDouble, func(e *pb.ExportedProduct) (interface{}, error) {
+		if dimensions := e.GetProduct().GetPhysicalProduct().GetDimensions(); dimensions != nil {
+			return dimensions.GetWidth(), nil
+		}
+		return nil, nil
+	}},
+	{"height", kindDouble, func(e *pb.ExportedProduct) (interface{}, error) {
+		if dimensions := e.GetProduct().GetPhysicalProduct().GetDimensions(); dimensions != nil {
+			return dimensions.GetHeight(), nil
+		}
+		return nil, nil
+	}},
+	{"dimensions_unit", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		if dimensions := e.GetProduct().GetPhysicalProduct().GetDimensions(); dimensions != nil {
+			return strings.ToLower(dimensions.GetUnit().String()), nil
+		}
+		return nil, nil
+	}},
+	{"subscription_period", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		if subscription := e.GetProduct().GetSubscriptionProduct(); subscription != nil {
+			return subscription.GetSubscriptionPeriod(), nil
+		}
+		return nil, nil
+	}},
+	{"renewal_price", kindDecimal, func(e *pb.ExportedProduct) (interface{}, error) {
+		if subscription := e.GetProduct().GetSubscriptionProduct(); subscription != nil {
+			return amount(subscription.GetRenewalPrice()), nil
+		}
+		return nil, nil
+	}},
+	{"etag", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		return e.GetProduct().GetEtag(), nil
+	}},
+	{"created_at", kindTimestamp, func(e *pb.ExportedProduct) (interface{}, error) {
+		return timestamp(e.GetProduct().GetCreatedAt().AsTime(), e.GetProduct().GetCreatedAt() != nil), nil
+	}},
+	{"updated_at", kindTimestamp, func(e *pb.ExportedProduct) (interface{}, error) {
+		return timestamp(e.GetProduct().GetUpdatedAt().AsTime(), e.GetProduct().GetUpdatedAt() != nil), nil
+	}},
+	{"deleted_at", kindTimestamp, func(e *pb.ExportedProduct) (interface{}, error) {
+		return timestamp(e.GetProduct().GetDeletedAt().AsTime(), e.GetProduct().GetDeletedAt() != nil), nil
+	}},
+	{"subscription_plans", kindString, func(e *pb.ExportedProduct) (interface{}, error) {
+		return plansJSON(e.GetSubscriptionPlans())
+	}},
+}
+
+// flatten returns the values of every export field for one product
+func flatten(exported *pb.ExportedProduct) ([]interface{}, error) {
+	values := make([]interface{}, len(exportFields))
+	for i, field := range exportFields {
+		value, err := field.get(exported)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.name, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// productType returns the type name of a product as used by the type column
+func productType(product *pb.Product) string {
+	switch product.GetProductType().(type) {
+	case *pb.Product_DigitalProduct:
+		return "digital"
+	case *pb.Product_PhysicalProduct:
+		return "physical"
+	case *pb.Product_SubscriptionProduct:
+		return "subscription"
+	case *pb.Product_BundleProduct:
+		return "bundle"
+	}
+	return ""
+}
+
+// amount returns the amount of a money message, or nil if it is not set
+func amount(money *moneypb.Money) interface{} {
+	if money == nil {
+		return nil
+	}
+	return domain.Amount{Units: money.GetUnits(), Nanos: money.GetNanos()}
+}
+
+func nonEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func timestamp(t time.Time, ok bool) interface{} {
+	if !ok {
+		return nil
+	}
+	return t
+}
+
+// plansJSON encodes subscription plans as a JSON array for the flat formats
+func plansJSON(plans []*pb.SubscriptionPlan) (string, error) {
+	encoded := make([]json.RawMessage, len(plans))
+	for i, plan := range plans {
+		raw, err := protojson.Marshal(plan)
+		if err != nil {
+			return "", err
+		}
+		encoded[i] = raw
+	}
+	// Re-encoding also strips the unstable whitespace protojson emits
+	raw, err := json.Marshal(encoded)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	header := make([]string, len(exportFields))
+	for i, field := range exportFields {
+		header[i] = field.name
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer}, nil
+}
+
+func (w *csvWriter) Write(exported *pb.ExportedProduct) error {
+	values, err := flatten(exported)
+	if err != nil {
+		return err
+	}
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch value := value.(type) {
+		case string:
+			record[i] = value
+		case int32:
+			record[i] = strconv.FormatInt(int64(value), 10)
+		case float64:
+			record[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		case time.Time:
+			record[i] = value.UTC().Format(time.RFC3339Nano)
+		case domain.Amount:
+			record[i] = value.String()
+		}
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonlWriter struct {
+	writer *bufio.Writer
+}
+
+func (w *jsonlWriter) Write(exported *pb.ExportedProduct) error {
+	raw, err := protojson.Marshal(exported)
+	if err != nil {
+		return err
+	}
+	if _, err := w.writer.Write(raw); err != nil {
+		return err
+	}
+	return w.writer.WriteByte('\n')
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.writer.Flush()
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// attributeWriteError turns the unique name violation into ErrAlreadyExists
+func attributeWriteError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: an attribute named %q is already defined", domain.ErrAlreadyExists, name)
+	}
+	return err
+}
+
+// findAttributeDefinition loads a definition, reporting a missing one as ErrNotFound
+func findAttributeDefinition(db *gorm.DB, id uuid.UUID) (*domain.AttributeDefinition, error) {
+	var definition domain.AttributeDefinition
+	if err := db.First(&definition, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("attribute definition with ID %s %w", id, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// CreateAttributeDefinition stores a new attribute definition
+func (r *ProductRepositoryImpl) CreateAttributeDefinition(ctx context.Context, definition *domain.AttributeDefinition) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if definition.CategoryID != nil {
+			if _, err := findCategory(tx, *definition.CategoryID); err != nil {
+				return err
+			}
+		}
+		return attributeWriteError(tx.Omit(clause.Associations).Create(definition).Error, definition.Name)
+	})
+}
+
+// GetAttributeDefinition retrieves an attribute definition by its ID
+func (r *ProductRepositoryImpl) GetAttributeDefinition(ctx context.Context, id uuid.UUID) (*domain.AttributeDefinition, error) {
+	return findAttributeDefinition(r.DB.WithContext(ctx), id)
+}
+
+// ListAttributeDefinitions returns the attribute definitions matching opts ordered by name
+func (r *ProductRepositoryImpl) ListAttributeDefinitions(ctx context.Context, opts domain.AttributeDefinitionListOptions) ([]domain.AttributeDefinition, error) {
+	query := r.DB.WithContext(ctx).Model(&domain.AttributeDefinition{})
+	if opts.ProductType != "" {
+		query = query.Where("product_type = ?", opts.ProductType)
+	}
+	if opts.CategoryID != nil {
+		query = query.Where("category_id = ?", *opts.CategoryID)
+	}
+	if len(opts.Names) > 0 {
+		query = query.Where("name IN ?", opts.Names)
+	}
+
+	var definitions []domain.AttributeDefinition
+	if err := query.Order("name").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// ApplicableAttributeDefinitions returns the definitions that apply to a
+// product of the given type in the given categories: those of the type, and
+// those of the categories or any of their ancestors
+func (r *ProductRepositoryImpl) ApplicableAttributeDefinitions(ctx context.Context, productType string, categoryIDs []uuid.UUID) ([]domain.AttributeDefinition, error) {
+	query := r.DB.WithContext(ctx).Model(&domain.AttributeDefinition{}).Where("product_type = ?", productType)
+	if len(categoryIDs) > 0 {
+		// An ancestor's path is a prefix of the paths of the product's categories
+		query = query.Or(`category_id IN (SELECT a.id FROM categories a JOIN categories c ON c.path LIKE a.path || '%'
+			WHERE c.id IN ?)`, categoryIDs)
+	}
+
+	var definitions []domain.AttributeDefinition
+	if err := query.Order("name").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// UpdateAttributeDefinition writes the mutable fields of a definition if the
+// stored version still matches definition.Version, which is then
+// incremented. Enum values still used by a product cannot be removed.
+func (r *ProductRepositoryImpl) UpdateAttributeDefinition(ctx context.Context, definition *domain.AttributeDefinition) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stored, err := findAttributeDefinition(tx.Clauses(clause.Locking{Strength: "UPDATE"}), definition.ID)
+		if err != nil {
+			return err
+		}
+		if stored.Version != definition.Version {
+			return fmt.Errorf("attribute definition with ID %s was modified concurrently: %w", definition.ID, domain.ErrVersionConflict)
+		}
+
+		var removed []string
+		for _, value := range stored.EnumValues {
+			if !definition.EnumValues.Contains(value) {
+				removed = append(removed, value)
+			}
+		}
+		if len(removed) > 0 {
+			var used int64
+			err := tx.Unscoped().Model(&domain.Product{}).
+				Where("attributes ->> ? IN ?", stored.Name, removed).
+				Count(&used).Error
+			if err != nil {
+				return err
+			}
+			if used > 0 {
+				return fmt.Errorf("%d products still use a removed value of attribute %q: %w", used, stored.Name, domain.ErrFailedPrecondition)
+			}
+		}
+
+		definition.Version = stored.Version + 1
+		err = tx.Model(definition).
+			Select("description", "required", "unit", "enum_values", "version", "updated_at").
+			Updates(definition).Error
+		if err != nil {
+			definition.Version = stored.Version
+		}
+		return err
+	})
+}
+
+// DeleteAttributeDefinition removes a definition no product has a value for
+// if it is still at the given version. Soft deleted products count, since
+// they can be restored.
+func (r *ProductRepositoryImpl) DeleteAttributeDefinition(ctx context.Context, id uuid.UUID, version int64) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stored, err := findAttributeDefinition(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id)
+		if err != nil {
+			return err
+		}
+		if stored.Version != version {
+			return fmt.Errorf("attribute definition with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+		}
+		var used int64
+		if err := tx.Unscoped().Model(&domain.Product{}).Where("attributes -> ? IS NOT NULL", stored.Name).Count(&used).Error; err != nil {
+			return err
+		}
+		if used > 0 {
+			return fmt.Errorf("%d products still have a value for attribute %q: %w", used, stored.Name, domain.ErrFailedPrecondition)
+		}
+		return tx.Delete(&domain.AttributeDefinition{}, "id = ?", id).Error
+	})
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// foreignKeyViolation is the SQLSTATE raised when a write breaks a foreign key
+const foreignKeyViolation = "23503"
+
+// componentsInOrder orders preloaded bundle components by position
+func componentsInOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// saveBundle writes the details of a bundle product and replaces its components
+func saveBundle(tx *gorm.DB, bundle *domain.BundleProduct) error {
+	if err := tx.Omit(clause.Associations).Save(bundle).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("bundle_product_id = ?", bundle.ID).Delete(&domain.BundleComponent{}).Error; err != nil {
+		return err
+	}
+	if len(bundle.Components) == 0 {
+		return nil
+	}
+	for i := range bundle.Components {
+		bundle.Components[i].ID = uuid.Nil
+		bundle.Components[i].BundleProductID = bundle.ID
+	}
+	return tx.Omit(clause.Associations).Create(&bundle.Components).Error
+}
+
+// bundleComponentError turns the foreign key violation raised when removing a
+// product or variant that a bundle contains into ErrFailedPrecondition
+func bundleComponentError(err error, what string, id uuid.UUID) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation && pgErr.TableName == "bundle_components" {
+		return fmt.Errorf("%w: %s %s is a component of a bundle, remove it from the bundle first", domain.ErrFailedPrecondition, what, id)
+	}
+	return err
+}
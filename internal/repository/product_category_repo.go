@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// categoryTreeLock is the advisory lock key serialising changes to the shape
+// of the category tree, so a move never misses a concurrently added child
+const categoryTreeLock = "categories_tree"
+
+// lockCategoryTree takes the category tree lock until the transaction ends
+func lockCategoryTree(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", categoryTreeLock).Error
+}
+
+// categoryWriteError turns the sibling name index violation into ErrAlreadyExists
+func categoryWriteError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: the parent already has a category named %q", domain.ErrAlreadyExists, name)
+	}
+	return err
+}
+
+// findCategory loads a category, reporting a missing one as ErrNotFound
+func findCategory(db *gorm.DB, id uuid.UUID) (*domain.Category, error) {
+	var category domain.Category
+	if err := db.First(&category, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("category with ID %s %w", id, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CreateCategory stores a new category below its parent, or at the top level
+func (r *ProductRepositoryImpl) CreateCategory(ctx context.Context, category *domain.Category) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategoryTree(tx); err != nil {
+			return err
+		}
+		parentPath := ""
+		if category.ParentID != nil {
+			parent, err := findCategory(tx, *category.ParentID)
+			if err != nil {
+				return err
+			}
+			parentPath = parent.Path
+		}
+		if category.ID == uuid.Nil {
+			category.ID = uuid.New()
+		}
+		category.Path = domain.CategoryPath(parentPath, category.ID)
+		return categoryWriteError(tx.Omit(clause.Associations).Create(category).Error, category.Name)
+	})
+}
+
+// GetCategory retrieves a category by its ID
+func (r *ProductRepositoryImpl) GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	return findCategory(r.DB.WithContext(ctx), id)
+}
+
+// ListCategories returns one level of the category tree ordered by name, or
+// a whole subtree in depth-first order
+func (r *ProductRepositoryImpl) ListCategories(ctx context.Context, opts domain.CategoryListOptions) ([]domain.Category, error) {
+	db := r.DB.WithContext(ctx)
+	query := db.Model(&domain.Category{})
+	switch {
+	case opts.IncludeDescendants && opts.ParentID != nil:
+		parent, err := findCategory(db, *opts.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("path LIKE ? AND id <> ?", parent.Path+"%", parent.ID).Order("path")
+	case opts.IncludeDescendants:
+		query = query.Order("path")
+	case opts.ParentID != nil:
+		query = query.Where("parent_id = ?", *opts.ParentID).Order("name, id")
+	default:
+		query = query.Where("parent_id IS NULL").Order("name, id")
+	}
+
+	var categories []domain.Category
+	if err := query.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// UpdateCategory writes the name, description and parent of a category if the
+// stored version still matches category.Version, which is then incremented.
+// Moving a category to another parent rewrites the paths of its subtree.
+func (r *ProductRepositoryImpl) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategoryTree(tx); err != nil {
+			return err
+		}
+		stored, err := findCategory(tx, category.ID)
+		if err != nil {
+			return err
+		}
+		if stored.Version != category.Version {
+			return fmt.Errorf("category with ID %s was modified concurrently: %w", category.ID, domain.ErrVersionConflict)
+		}
+
+		category.Path = stored.Path
+		if !sameParent(stored.ParentID, category.ParentID) {
+			parentPath := ""
+			if category.ParentID != nil {
+				parent, err := findCategory(tx, *category.ParentID)
+				if err != nil {
+					return err
+				}
+				if parent.IsDescendantOf(stored.Path) {
+					return fmt.Errorf("%w: a category cannot be moved below itself", domain.ErrInvalidArgument)
+				}
+				parentPath = parent.Path
+			}
+			category.Path = domain.CategoryPath(parentPath, category.ID)
+			err := tx.Exec("UPDATE categories SET path = ? || substr(path, ?) WHERE path LIKE ? AND id <> ?",
+				category.Path, len(stored.Path)+1, stored.Path+"%", category.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		category.Version = stored.Version + 1
+		err = tx.Model(category).
+			Select("parent_id", "name", "description", "path", "version", "updated_at").
+			Updates(category).Error
+		if err != nil {
+			category.Version = stored.Version
+			return categoryWriteError(err, category.Name)
+		}
+		return nil
+	})
+}
+
+// sameParent compares two optional parent IDs
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// DeleteCategory removes a category without children or attribute
+// definitions if it is still at the given version. Products in it simply
+// lose the category.
+func (r *ProductRepositoryImpl) DeleteCategory(ctx context.Context, id uuid.UUID, version int64) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategoryTree(tx); err != nil {
+			return err
+		}
+		stored, err := findCategory(tx, id)
+		if err != nil {
+			return err
+		}
+		if stored.Version != version {
+			return fmt.Errorf("category with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+		}
+		var children int64
+		if err := tx.Model(&domain.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return fmt.Errorf("category with ID %s has subcategories, which must be moved or deleted first: %w", id, domain.ErrFailedPrecondition)
+		}
+		var definitions int64
+		if err := tx.Model(&domain.AttributeDefinition{}).Where("category_id = ?", id).Count(&definitions).Error; err != nil {
+			return err
+		}
+		if definitions > 0 {
+			return fmt.Errorf("category with ID %s has attribute definitions, which must be deleted first: %w", id, domain.ErrFailedPrecondition)
+		}
+		return tx.Delete(&domain.Category{}, "id = ?", id).Error
+	})
+}
+
+// replaceProductCategories makes the categories of a product exactly
+// product.Categories, which only need their IDs set
+func replaceProductCategories(tx *gorm.DB, product *domain.Product) error {
+	ids := product.CategoryIDs()
+	if len(ids) > 0 {
+		var found int64
+		if err := tx.Model(&domain.Category{}).Where("id IN ?", ids).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(ids)) {
+			return fmt.Errorf("%w: unknown category in category_ids", domain.ErrInvalidArgument)
+		}
+	}
+
+	stale := tx.Table("product_categories").Where("product_id = ?", product.ID)
+	if len(ids) > 0 {
+		stale = stale.Where("category_id NOT IN ?", ids)
+	}
+	if err := stale.Delete(nil).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		rows[i] = map[string]interface{}{"product_id": product.ID, "category_id": id}
+	}
+	return tx.Table("product_categories").Clauses(clause.OnConflict{DoNothing: true}).Create(rows).Error
+}
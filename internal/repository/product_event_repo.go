@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// committedEvents limits the change log to events whose transaction is older
+// than every transaction still running. Later events are held back until
+// that is the case, which keeps positions handed out to readers stable.
+const committedEvents = "transaction_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint"
+
+// ListProductEvents returns up to limit committed events after the position,
+// each with the current state of the product or subscription plan it is about
+func (r *ProductRepositoryImpl) ListProductEvents(ctx context.Context, after domain.ProductEventPosition, limit int) ([]domain.ProductEvent, error) {
+	var events []domain.ProductEvent
+	err := r.DB.WithContext(ctx).
+		Where(committedEvents).
+		Where("(transaction_id, sequence) > (?, ?)", after.TransactionID, after.Sequence).
+		Order("transaction_id, sequence").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+
+	var productIDs, planIDs []uuid.UUID
+	for _, event := range events {
+		switch event.Resource {
+		case domain.ProductEventResourceProduct:
+			productIDs = append(productIDs, event.ResourceID)
+		case domain.ProductEventResourceSubscriptionPlan:
+			planIDs = append(planIDs, event.ResourceID)
+		}
+	}
+
+	products := make(map[uuid.UUID]*domain.Product)
+	if len(productIDs) > 0 {
+		var found []domain.Product
+		err := r.DB.WithContext(ctx).Unscoped().
+			Preload("DigitalProduct").
+			Preload("PhysicalProduct").
+			Preload("SubscriptionProduct").
+			Preload("BundleProduct.Components", componentsInOrder).
+			Preload("Categories").
+			Preload("Media", mediaInDisplayOrder).
+			Where("id IN ?", productIDs).
+			Find(&found).Error
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			products[found[i].ID] = &found[i]
+		}
+	}
+	plans := make(map[uuid.UUID]*domain.SubscriptionPlan)
+	if len(planIDs) > 0 {
+		var found []domain.SubscriptionPlan
+		if err := r.DB.WithContext(ctx).Where("id IN ?", planIDs).Find(&found).Error; err != nil {
+			return nil, err
+		}
+		for i := range found {
+			plans[found[i].ID] = &found[i]
+		}
+	}
+
+	for i := range events {
+		switch events[i].Resource {
+		case domain.ProductEventResourceProduct:
+			events[i].Product = products[events[i].ResourceID]
+		case domain.ProductEventResourceSubscriptionPlan:
+			events[i].SubscriptionPlan = plans[events[i].ResourceID]
+		}
+	}
+	return events, nil
+}
+
+// LatestProductEventPosition returns the position after the newest committed
+// event, or the start of the log if it is empty
+func (r *ProductRepositoryImpl) LatestProductEventPosition(ctx context.Context) (domain.ProductEventPosition, error) {
+	var event domain.ProductEvent
+	err := r.DB.WithContext(ctx).
+		Where(committedEvents).
+		Order("transaction_id DESC, sequence DESC").
+		Take(&event).Error
+	if err == gorm.ErrRecordNotFound {
+		return domain.ProductEventPosition{}, nil
+	}
+	if err != nil {
+		return domain.ProductEventPosition{}, err
+	}
+	return event.Position(), nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many rows are fetched from the export cursor at a time
+const exportBatchSize = 500
+
+// exportCursor names the server-side cursor used by ExportProducts. Cursors
+// are scoped to their transaction, so concurrent exports do not collide.
+const exportCursor = "product_export"
+
+// ExportProducts walks every product matching the filter in ID order and
+// calls fn for each one. The products table is read through a server-side
+// cursor inside a read-only repeatable read transaction, so memory use stays
+// flat and the export is a consistent snapshot of the catalog. An error
+// returned by fn stops the export and is returned as is.
+func (r *ProductRepositoryImpl) ExportProducts(ctx context.Context, filter domain.ProductFilter, fn func(*domain.ExportedProduct) error) error {
+	options := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Session(&gorm.Session{DryRun: true}).Model(&domain.Product{})
+		if filter.ShowDeleted {
+			query = query.Unscoped()
+		}
+		stmt := applyProductFilter(query, filter).Select("id").Order("id").Find(&[]domain.Product{}).Statement
+		if stmt.Error != nil {
+			return stmt.Error
+		}
+		// The statement is already rendered for the driver, so it bypasses
+		// GORM's own placeholder handling
+		declare := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", exportCursor, stmt.SQL.String())
+		if _, err := tx.Statement.ConnPool.ExecContext(ctx, declare, stmt.Vars...); err != nil {
+			return err
+		}
+
+		fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", exportBatchSize, exportCursor)
+		for {
+			var ids []uuid.UUID
+			if err := tx.Raw(fetch).Scan(&ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				break
+			}
+			batch, err := loadExportBatch(tx, ids, filter.ShowDeleted)
+			if err != nil {
+				return err
+			}
+			for i := range batch {
+				if err := fn(&batch[i]); err != nil {
+					return err
+				}
+			}
+			if len(ids) < exportBatchSize {
+				break
+			}
+		}
+		return tx.Exec("CLOSE " + exportCursor).Error
+	}, options)
+}
+
+// loadExportBatch loads the products for one batch of cursor rows with their
+// details and subscription plans, keeping the cursor order
+func loadExportBatch(tx *gorm.DB, ids []uuid.UUID, showDeleted bool) ([]domain.ExportedProduct, error) {
+	query := tx.Preload("DigitalProduct").
+		Preload("PhysicalProduct").
+		Preload("SubscriptionProduct").
+		Preload("BundleProduct.Components", componentsInOrder).
+		Preload("Categories").
+		Preload("Media", mediaInDisplayOrder)
+	if showDeleted {
+		query = query.Unscoped()
+	}
+	var products []domain.Product
+	if err := query.Where("id IN ?", ids).Order("id").Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	var plans []domain.SubscriptionPlan
+	if err := tx.Where("product_id IN ?", ids).Order("product_id, plan_name, id").Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	plansByProduct := make(map[uuid.UUID][]domain.SubscriptionPlan)
+	for _, plan := range plans {
+		plansByProduct[plan.ProductID] = append(plansByProduct[plan.ProductID], plan)
+	}
+
+	batch := make([]domain.ExportedProduct, len(products))
+	for i, product := range products {
+		batch[i] = domain.ExportedProduct{
+			Product:           product,
+			SubscriptionPlans: plansByProduct[product.ID],
+		}
+	}
+	return batch, nil
+}
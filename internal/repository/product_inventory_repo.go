@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// whereStockItem restricts a query on a table embedding domain.StockItem to one item
+func whereStockItem(db *gorm.DB, item domain.StockItem) *gorm.DB {
+	db = db.Where("product_id = ? AND warehouse_id = ?", item.ProductID, item.WarehouseID)
+	if item.VariantID != nil {
+		return db.Where("variant_id = ?", *item.VariantID)
+	}
+	return db.Where("variant_id IS NULL")
+}
+
+// lockStockLevel loads the stock level of an item and locks its row until the
+// transaction ends, so concurrent writers queue up instead of overselling.
+// With create set a missing level is created empty first; otherwise it is
+// reported as gorm.ErrRecordNotFound.
+func lockStockLevel(tx *gorm.DB, item domain.StockItem, create bool) (*domain.StockLevel, error) {
+	if create {
+		level := domain.StockLevel{StockItem: item}
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error; err != nil {
+			return nil, err
+		}
+	}
+	var level domain.StockLevel
+	if err := whereStockItem(tx.Clauses(clause.Locking{Strength: "UPDATE"}), item).First(&level).Error; err != nil {
+		return nil, err
+	}
+	return &level, nil
+}
+
+// moveStock applies a change to a locked stock level and records it in the
+// ledger. Changes that would leave fewer units on hand than are reserved, or
+// either count negative, fail with ErrFailedPrecondition.
+func moveStock(tx *gorm.DB, level *domain.StockLevel, movement domain.StockMovement) error {
+	onHand, reserved := level.OnHand+movement.OnHandDelta, level.Reserved+movement.ReservedDelta
+	if onHand < 0 || reserved < 0 || reserved > onHand {
+		return fmt.Errorf("%w: not enough stock, %d on hand of which %d reserved", domain.ErrFailedPrecondition, level.OnHand, level.Reserved)
+	}
+	level.OnHand, level.Reserved, level.UpdatedAt = onHand, reserved, time.Now()
+	if err := tx.Model(level).Select("on_hand", "reserved", "updated_at").Updates(level).Error; err != nil {
+		return err
+	}
+
+	movement.StockItem = level.StockItem
+	movement.OnHandAfter, movement.ReservedAfter = onHand, reserved
+	movement.CreatedAt = level.UpdatedAt
+	return tx.Create(&movement).Error
+}
+
+// AdjustStock changes the number of units of an item on hand by delta,
+// creating its stock level on the first delivery
+func (r *ProductRepositoryImpl) AdjustStock(ctx context.Context, item domain.StockItem, delta int64, reason string) (*domain.StockLevel, error) {
+	var level *domain.StockLevel
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if level, err = lockStockLevel(tx, item, delta > 0); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("%w: not enough stock, the warehouse holds none of the item", domain.ErrFailedPrecondition)
+			}
+			return err
+		}
+		return moveStock(tx, level, domain.StockMovement{
+			Kind:        domain.StockMovementAdjustment,
+			OnHandDelta: delta,
+			Reason:      reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return level, nil
+}
+
+// GetStock returns the stock levels of a product, or of one of its variants,
+// in every warehouse that has held it
+func (r *ProductRepositoryImpl) GetStock(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID) ([]domain.StockLevel, error) {
+	query := r.DB.WithContext(ctx).Where("product_id = ?", productID)
+	if variantID != nil {
+		query = query.Where("variant_id = ?", *variantID)
+	}
+	var levels []domain.StockLevel
+	if err := query.Order("variant_id NULLS FIRST, warehouse_id").Find(&levels).Error; err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// allocateStockLevel picks the active warehouse to fulfil a reservation
+// from: the one first in priority order with enough units available,
+// preferring the one with the most on a tie. Its stock level is locked
+// until the transaction ends.
+func allocateStockLevel(tx *gorm.DB, reservation *domain.StockReservation) (*domain.StockLevel, error) {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "stock_levels"}}).
+		Joins("JOIN warehouses ON warehouses.id = stock_levels.warehouse_id AND warehouses.active").
+		Where("stock_levels.product_id = ? AND stock_levels.on_hand - stock_levels.reserved >= ?", reservation.ProductID, reservation.Quantity).
+		Order("warehouses.priority, stock_levels.on_hand - stock_levels.reserved DESC, stock_levels.warehouse_id")
+	if reservation.VariantID != nil {
+		query = query.Where("stock_levels.variant_id = ?", *reservation.VariantID)
+	} else {
+		query = query.Where("stock_levels.variant_id IS NULL")
+	}
+
+	var level domain.StockLevel
+	if err := query.First(&level).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: not enough stock, no active warehouse has %d units available", domain.ErrFailedPrecondition, reservation.Quantity)
+		}
+		return nil, err
+	}
+	return &level, nil
+}
+
+// ReserveStock stores a pending reservation after setting its units aside
+// from the available stock of its item. Without a warehouse the reservation
+// is allocated to one by allocateStockLevel.
+func (r *ProductRepositoryImpl) ReserveStock(ctx context.Context, reservation *domain.StockReservation) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var level *domain.StockLevel
+		var err error
+		if reservation.WarehouseID == uuid.Nil {
+			level, err = allocateStockLevel(tx, reservation)
+		} else {
+			level, err = lockStockLevel(tx, reservation.StockItem, false)
+		}
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("%w: not enough stock, the warehouse holds none of the item", domain.ErrFailedPrecondition)
+		}
+		if err != nil {
+			return err
+		}
+		reservation.WarehouseID = level.WarehouseID
+
+		if reservation.ID == uuid.Nil {
+			reservation.ID = uuid.New()
+		}
+		err = moveStock(tx, level, domain.StockMovement{
+			Kind:          domain.StockMovementReservation,
+			ReservedDelta: reservation.Quantity,
+			ReservationID: &reservation.ID,
+			Reason:        reservation.Reference,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Create(reservation).Error
+	})
+}
+
+// SettleReservation moves a pending reservation to status, released or
+// committed. Releasing returns its units to the available stock and
+// committing takes them off hand. A reservation that expired by now can only
+// be released.
+func (r *ProductRepositoryImpl) SettleReservation(ctx context.Context, id uuid.UUID, status domain.StockReservationStatus, now time.Time) (*domain.StockReservation, error) {
+	var reservation domain.StockReservation
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("stock reservation with ID %s %w", id, domain.ErrNotFound)
+			}
+			return err
+		}
+		if reservation.Status != domain.StockReservationPending {
+			return fmt.Errorf("stock reservation with ID %s is %s: %w", id, reservation.Status, domain.ErrFailedPrecondition)
+		}
+		if status == domain.StockReservationCommitted && !reservation.ExpiresAt.After(now) {
+			return fmt.Errorf("stock reservation with ID %s has expired: %w", id, domain.ErrFailedPrecondition)
+		}
+		return settleReservation(tx, &reservation, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// reservationMovements maps the final states of a reservation to the kind of
+// stock movement reaching them
+var reservationMovements = map[domain.StockReservationStatus]domain.StockMovementKind{
+	domain.StockReservationCommitted: domain.StockMovementCommit,
+	domain.StockReservationReleased:  domain.StockMovementRelease,
+	domain.StockReservationExpired:   domain.StockMovementExpiry,
+}
+
+// settleReservation moves a locked pending reservation to a final status and
+// updates the stock level of its item. If the level is gone because its
+// product or variant was removed, only the reservation changes.
+func settleReservation(tx *gorm.DB, reservation *domain.StockReservation, status domain.StockReservationStatus) error {
+	level, err := lockStockLevel(tx, reservation.StockItem, false)
+	switch {
+	case err == nil:
+		movement := domain.StockMovement{
+			Kind:          reservationMovements[status],
+			ReservedDelta: -reservation.Quantity,
+			ReservationID: &reservation.ID,
+			Reason:        reservation.Reference,
+		}
+		if status == domain.StockReservationCommitted {
+			movement.OnHandDelta = -reservation.Quantity
+		}
+		if err := moveStock(tx, level, movement); err != nil {
+			return err
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+	reservation.Status = status
+	return tx.Model(reservation).Select("status", "updated_at").Updates(reservation).Error
+}
+
+// ExpireReservations expires up to limit pending reservations whose expiry
+// passed by now and returns how many it expired. Rows are claimed with SKIP
+// LOCKED so several instances of the service can run the sweep at once, and
+// settled in item order so concurrent sweeps lock stock levels in the same order.
+func (r *ProductRepositoryImpl) ExpireReservations(ctx context.Context, now time.Time, limit int) (int, error) {
+	var reservations []domain.StockReservation
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at <= ?", domain.StockReservationPending, now).
+			Order("product_id, variant_id, warehouse_id, id").
+			Limit(limit).
+			Find(&reservations).Error
+		if err != nil {
+			return err
+		}
+		for i := range reservations {
+			if err := settleReservation(tx, &reservations[i], domain.StockReservationExpired); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(reservations), nil
+}
+
+// ListStockMovements returns a page of the stock ledger of a product, newest first
+func (r *ProductRepositoryImpl) ListStockMovements(ctx context.Context, opts domain.StockMovementOptions) ([]domain.StockMovement, error) {
+	query := r.DB.WithContext(ctx).Where("product_id = ?", opts.ProductID)
+	if opts.VariantID != nil {
+		query = query.Where("variant_id = ?", *opts.VariantID)
+	}
+	if opts.WarehouseID != nil {
+		query = query.Where("warehouse_id = ?", *opts.WarehouseID)
+	}
+	if opts.BeforeID > 0 {
+		query = query.Where("id < ?", opts.BeforeID)
+	}
+	var movements []domain.StockMovement
+	if err := query.Order("id DESC").Limit(opts.Limit).Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
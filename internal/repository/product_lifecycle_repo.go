@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"product-microservice/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ApplyDuePublications publishes the products in review whose publish_at has
+// passed, then archives the published products whose unpublish_at has
+// passed, and returns how many products changed. Each row is claimed by a
+// single conditional UPDATE, so concurrent schedulers never apply a
+// transition twice.
+func (r *ProductRepositoryImpl) ApplyDuePublications(ctx context.Context, now time.Time) (int64, error) {
+	var changed int64
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		published := tx.Model(&domain.Product{}).
+			Where("status = ? AND publish_at <= ?", domain.ProductStatusInReview, now).
+			Updates(map[string]interface{}{
+				"status":       domain.ProductStatusPublished,
+				"published_at": gorm.Expr("publish_at"),
+				"publish_at":   nil,
+				"updated_at":   now,
+				"version":      gorm.Expr("version + 1"),
+			})
+		if published.Error != nil {
+			return published.Error
+		}
+
+		archived := tx.Model(&domain.Product{}).
+			Where("status = ? AND unpublish_at <= ?", domain.ProductStatusPublished, now).
+			Updates(map[string]interface{}{
+				"status":       domain.ProductStatusArchived,
+				"unpublish_at": nil,
+				"updated_at":   now,
+				"version":      gorm.Expr("version + 1"),
+			})
+		if archived.Error != nil {
+			return archived.Error
+		}
+		changed = published.RowsAffected + archived.RowsAffected
+		return nil
+	})
+	return changed, err
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mediaInDisplayOrder orders preloaded product media by position
+func mediaInDisplayOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// lockProduct takes a row lock on a product, deleted or not, which
+// serialises changes to the order of its media
+func lockProduct(tx *gorm.DB, id uuid.UUID) error {
+	var product domain.Product
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&product, "id = ?", id).Error
+	if err == gorm.ErrRecordNotFound {
+		return fmt.Errorf("product with ID %s %w", id, domain.ErrNotFound)
+	}
+	return err
+}
+
+// CreateMedia stores new media of a product after its existing media
+func (r *ProductRepositoryImpl) CreateMedia(ctx context.Context, media *domain.ProductMedia) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, media.ProductID); err != nil {
+			return err
+		}
+		err := tx.Model(&domain.ProductMedia{}).
+			Select("coalesce(max(position) + 1, 0)").
+			Where("product_id = ?", media.ProductID).
+			Scan(&media.Position).Error
+		if err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(media).Error
+	})
+}
+
+// GetMedia retrieves product media by its ID
+func (r *ProductRepositoryImpl) GetMedia(ctx context.Context, id uuid.UUID) (*domain.ProductMedia, error) {
+	var media domain.ProductMedia
+	if err := r.DB.WithContext(ctx).First(&media, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("product media with ID %s %w", id, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+// ListMedia returns the media of a product in display order
+func (r *ProductRepositoryImpl) ListMedia(ctx context.Context, productID uuid.UUID) ([]domain.ProductMedia, error) {
+	var media []domain.ProductMedia
+	err := mediaInDisplayOrder(r.DB.WithContext(ctx).Where("product_id = ?", productID)).Find(&media).Error
+	if err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// ReorderMedia puts the media of a product in the order of ids, which must
+// list every media of the product exactly once, and returns them in that
+// order. Positions are not guarded by the media versions.
+func (r *ProductRepositoryImpl) ReorderMedia(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) ([]domain.ProductMedia, error) {
+	var media []domain.ProductMedia
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", productID).Find(&media).Error; err != nil {
+			return err
+		}
+		byID := make(map[uuid.UUID]*domain.ProductMedia, len(media))
+		for i := range media {
+			byID[media[i].ID] = &media[i]
+		}
+		if len(ids) != len(media) {
+			return fmt.Errorf("%w: media_ids must list all %d media of the product", domain.ErrInvalidArgument, len(media))
+		}
+
+		ordered := make([]domain.ProductMedia, len(ids))
+		for position, id := range ids {
+			item, ok := byID[id]
+			if !ok {
+				return fmt.Errorf("%w: media %s is not media of the product or is listed twice", domain.ErrInvalidArgument, id)
+			}
+			delete(byID, id)
+			if item.Position != int32(position) {
+				item.Position = int32(position)
+				err := tx.Model(item).Select("position", "updated_at").Updates(item).Error
+				if err != nil {
+					return err
+				}
+			}
+			ordered[position] = *item
+		}
+		media = ordered
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// DeleteMedia removes product media if it is still at the given version and
+// closes the gap it leaves in the order. The blob is left to the caller.
+func (r *ProductRepositoryImpl) DeleteMedia(ctx context.Context, id uuid.UUID, version int64) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		media, err := r.GetMedia(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := lockProduct(tx, media.ProductID); err != nil {
+			return err
+		}
+		// Read again under the lock, since the position may have moved
+		if err := tx.First(media, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("product media with ID %s %w", id, domain.ErrNotFound)
+			}
+			return err
+		}
+		if media.Version != version {
+			return fmt.Errorf("product media with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+		}
+		if err := tx.Delete(media).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.ProductMedia{}).
+			Where("product_id = ? AND position > ?", media.ProductID, media.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// exclusionViolation is the SQLSTATE raised when a write breaks an EXCLUDE
+// constraint, such as overlapping price list entries
+const exclusionViolation = "23P01"
+
+// CreatePriceListEntry stores a new price list entry after checking that the
+// product exists and, for plan prices, that the plan belongs to it
+func (r *ProductRepositoryImpl) CreatePriceListEntry(ctx context.Context, entry *domain.PriceListEntry) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Product{}).Where("id = ?", entry.ProductID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("product with ID %s %w", entry.ProductID, domain.ErrNotFound)
+		}
+		if entry.SubscriptionPlanID != nil {
+			if _, err := findSubscriptionPlan(tx, entry.ProductID, *entry.SubscriptionPlanID); err != nil {
+				return err
+			}
+		}
+
+		err := tx.Omit(clause.Associations).Create(entry).Error
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+			return fmt.Errorf("%w: another %s price for region %q overlaps this period",
+				domain.ErrAlreadyExists, entry.Price.Currency, entry.Region)
+		}
+		return err
+	})
+}
+
+// ListPriceListEntries returns the price list entries of a product, or only
+// those of one of its plans if planID is set
+func (r *ProductRepositoryImpl) ListPriceListEntries(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.PriceListEntry, error) {
+	query := r.DB.WithContext(ctx).Where("product_id = ?", productID)
+	if planID != nil {
+		query = query.Where("subscription_plan_id = ?", *planID)
+	}
+	var entries []domain.PriceListEntry
+	err := query.Order("subscription_plan_id NULLS FIRST, region, price_currency, effective_from NULLS FIRST").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// DeletePriceListEntry removes a price list entry if it is still at the given version
+func (r *ProductRepositoryImpl) DeletePriceListEntry(ctx context.Context, id uuid.UUID, version int64) error {
+	db := r.DB.WithContext(ctx)
+	result := db.Where("id = ? AND version = ?", id, version).Delete(&domain.PriceListEntry{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := db.Model(&domain.PriceListEntry{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("price list entry with ID %s %w", id, domain.ErrNotFound)
+		}
+		return fmt.Errorf("price list entry with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+	}
+	return nil
+}
+
+// FindEffectivePrices returns the entries in the query's currency that apply
+// at the query's time, for the query's region and for the default region
+func (r *ProductRepositoryImpl) FindEffectivePrices(ctx context.Context, query domain.PriceQuery) ([]domain.PriceListEntry, error) {
+	db := r.DB.WithContext(ctx).
+		Where("product_id = ? AND price_currency = ?", query.ProductID, query.Currency).
+		Where("region IN ?", []string{query.Region, domain.DefaultRegion}).
+		Where("(effective_from IS NULL OR effective_from <= ?) AND (effective_to IS NULL OR effective_to > ?)", query.At, query.At)
+	if query.SubscriptionPlanID != nil {
+		db = db.Where("subscription_plan_id = ?", *query.SubscriptionPlanID)
+	} else {
+		db = db.Where("subscription_plan_id IS NULL")
+	}
+
+	var entries []domain.PriceListEntry
+	if err := db.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetSubscriptionPlan returns a subscription plan of the given product
+func (r *ProductRepositoryImpl) GetSubscriptionPlan(ctx context.Context, productID, planID uuid.UUID) (*domain.SubscriptionPlan, error) {
+	return findSubscriptionPlan(r.DB.WithContext(ctx), productID, planID)
+}
+
+func findSubscriptionPlan(db *gorm.DB, productID, planID uuid.UUID) (*domain.SubscriptionPlan, error) {
+	var plan domain.SubscriptionPlan
+	if err := db.First(&plan, "id = ? AND product_id = ?", planID, productID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("subscription plan %s of product %s %w", planID, productID, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
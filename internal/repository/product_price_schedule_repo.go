@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListPriceHistory returns a page of the price history of a product and its
+// plans, or of one plan if opts.SubscriptionPlanID is set, newest first
+func (r *ProductRepositoryImpl) ListPriceHistory(ctx context.Context, opts domain.PriceHistoryOptions) ([]domain.PriceChange, error) {
+	query := r.DB.WithContext(ctx).Where("product_id = ?", opts.ProductID)
+	if opts.SubscriptionPlanID != nil {
+		query = query.Where("subscription_plan_id = ?", *opts.SubscriptionPlanID)
+	}
+	if opts.BeforeID > 0 {
+		query = query.Where("id < ?", opts.BeforeID)
+	}
+	var changes []domain.PriceChange
+	if err := query.Order("id DESC").Limit(opts.Limit).Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SchedulePriceChange stores a pending price change after checking that its
+// target exists and is priced in the same currency
+func (r *ProductRepositoryImpl) SchedulePriceChange(ctx context.Context, change *domain.ScheduledPriceChange) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := currentPrice(tx, change)
+		if err != nil {
+			return err
+		}
+		if current.Currency != change.Price.Currency {
+			return fmt.Errorf("%w: the new price must be in the current currency %s", domain.ErrInvalidArgument, current.Currency)
+		}
+		return tx.Create(change).Error
+	})
+}
+
+// ListScheduledPriceChanges returns the scheduled price changes of a product
+// and its plans, or of one plan if planID is set, in the order they take effect
+func (r *ProductRepositoryImpl) ListScheduledPriceChanges(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.ScheduledPriceChange, error) {
+	query := r.DB.WithContext(ctx).Where("product_id = ?", productID)
+	if planID != nil {
+		query = query.Where("subscription_plan_id = ?", *planID)
+	}
+	var changes []domain.ScheduledPriceChange
+	if err := query.Order("effective_at, id").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// CancelScheduledPriceChange cancels a pending price change at the given version
+func (r *ProductRepositoryImpl) CancelScheduledPriceChange(ctx context.Context, id uuid.UUID, version int64) (*domain.ScheduledPriceChange, error) {
+	var change domain.ScheduledPriceChange
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&change, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("scheduled price change with ID %s %w", id, domain.ErrNotFound)
+			}
+			return err
+		}
+		if change.Version != version {
+			return fmt.Errorf("scheduled price change with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+		}
+		if change.Status != domain.ScheduledPriceChangePending {
+			return fmt.Errorf("scheduled price change with ID %s is %s: %w", id, change.Status, domain.ErrFailedPrecondition)
+		}
+		change.Status = domain.ScheduledPriceChangeCancelled
+		change.Version++
+		return tx.Model(&change).Select("status", "version").Updates(&change).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ApplyDuePriceChanges applies up to limit pending price changes that took
+// effect at or before now, oldest first, and returns how many it processed.
+// Rows are claimed with SKIP LOCKED so several instances of the service can
+// run the scheduler at once. A change whose target is gone, or now priced in
+// another currency, is marked failed instead of blocking the queue.
+func (r *ProductRepositoryImpl) ApplyDuePriceChanges(ctx context.Context, now time.Time, limit int) (int, error) {
+	var changes []domain.ScheduledPriceChange
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND effective_at <= ?", domain.ScheduledPriceChangePending, now).
+			Order("effective_at, id").
+			Limit(limit).
+			Find(&changes).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range changes {
+			change := &changes[i]
+			// Each change gets a savepoint so a failed one leaves the others intact
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				return applyPriceChange(tx, change)
+			})
+			switch {
+			case err == nil:
+				appliedAt := time.Now()
+				change.Status, change.AppliedAt = domain.ScheduledPriceChangeApplied, &appliedAt
+			case errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrFailedPrecondition):
+				change.Status, change.Error = domain.ScheduledPriceChangeFailed, err.Error()
+			default:
+				return err
+			}
+			change.Version++
+			if err := tx.Model(change).Select("status", "error", "applied_at", "version").Updates(change).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(changes), nil
+}
+
+// applyPriceChange writes the price of a scheduled change to its target. The
+// change ID is exposed to the price history trigger for the transaction.
+func applyPriceChange(tx *gorm.DB, change *domain.ScheduledPriceChange) error {
+	current, err := currentPrice(tx, change)
+	if err != nil {
+		return err
+	}
+	if current.Currency != change.Price.Currency {
+		return fmt.Errorf("target is now priced in %s: %w", current.Currency, domain.ErrFailedPrecondition)
+	}
+	if err := tx.Exec("SELECT set_config('app.scheduled_price_change_id', ?, true)", change.ID.String()).Error; err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"price_amount": change.Price.Amount,
+		"version":      gorm.Expr("version + 1"),
+	}
+	if change.SubscriptionPlanID != nil {
+		return tx.Model(&domain.SubscriptionPlan{}).Where("id = ?", *change.SubscriptionPlanID).Updates(updates).Error
+	}
+	updates["updated_at"] = time.Now()
+	// Soft deleted products are updated too, so a restore brings back the right price
+	return tx.Unscoped().Model(&domain.Product{}).Where("id = ?", change.ProductID).Updates(updates).Error
+}
+
+// currentPrice loads the price a scheduled change would replace, locking the
+// row until the transaction ends
+func currentPrice(tx *gorm.DB, change *domain.ScheduledPriceChange) (domain.Money, error) {
+	locked := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	if change.SubscriptionPlanID != nil {
+		var plan domain.SubscriptionPlan
+		err := locked.First(&plan, "id = ? AND product_id = ?", *change.SubscriptionPlanID, change.ProductID).Error
+		if err == gorm.ErrRecordNotFound {
+			return domain.Money{}, fmt.Errorf("subscription plan %s of product %s %w", *change.SubscriptionPlanID, change.ProductID, domain.ErrNotFound)
+		}
+		return plan.Price, err
+	}
+
+	var product domain.Product
+	err := locked.Unscoped().First(&product, "id = ?", change.ProductID).Error
+	if err == gorm.ErrRecordNotFound {
+		return domain.Money{}, fmt.Errorf("product with ID %s %w", change.ProductID, domain.ErrNotFound)
+	}
+	return product.Price, err
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"product-microservice/internal/domain"
 	"context"
+	"strings"
+	"time"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ProductRepository interface
@@ -13,12 +16,85 @@ type ProductRepository interface {
 	Create(product *domain.Product) error
 	GetByID(id uuid.UUID) (*domain.Product, error)
 	Update(product *domain.Product) error
-	Delete(id uuid.UUID) error
+	Delete(id uuid.UUID, version int64) error
+	Restore(id uuid.UUID, version int64) error
+	Purge(id uuid.UUID) error
 	FindById(id string) (*domain.Product, error)
+	FindByExternalSKU(sku string) (*domain.Product, error)
 	GetAllProducts(ctx context.Context) ([]domain.Product, error)
 	GetDigitalProducts(ctx context.Context) ([]domain.Product, error)
 	GetPhysicalProducts(ctx context.Context) ([]domain.Product, error)
 	GetSubscriptionProducts(ctx context.Context) ([]domain.Product, error)
+	ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]domain.Product, int64, error)
+	SearchProducts(ctx context.Context, opts domain.ProductSearchOptions) ([]domain.ProductSearchHit, error)
+	ExportProducts(ctx context.Context, filter domain.ProductFilter, fn func(*domain.ExportedProduct) error) error
+	ListProductEvents(ctx context.Context, after domain.ProductEventPosition, limit int) ([]domain.ProductEvent, error)
+	LatestProductEventPosition(ctx context.Context) (domain.ProductEventPosition, error)
+	CreatePriceListEntry(ctx context.Context, entry *domain.PriceListEntry) error
+	ListPriceListEntries(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.PriceListEntry, error)
+	DeletePriceListEntry(ctx context.Context, id uuid.UUID, version int64) error
+	FindEffectivePrices(ctx context.Context, query domain.PriceQuery) ([]domain.PriceListEntry, error)
+	GetSubscriptionPlan(ctx context.Context, productID, planID uuid.UUID) (*domain.SubscriptionPlan, error)
+	ListPriceHistory(ctx context.Context, opts domain.PriceHistoryOptions) ([]domain.PriceChange, error)
+	SchedulePriceChange(ctx context.Context, change *domain.ScheduledPriceChange) error
+	ListScheduledPriceChanges(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.ScheduledPriceChange, error)
+	CancelScheduledPriceChange(ctx context.Context, id uuid.UUID, version int64) (*domain.ScheduledPriceChange, error)
+	ApplyDuePriceChanges(ctx context.Context, now time.Time, limit int) (int, error)
+	CreateVariant(ctx context.Context, variant *domain.ProductVariant) error
+	GetVariant(ctx context.Context, id uuid.UUID) (*domain.ProductVariant, error)
+	FindVariantBySKU(ctx context.Context, sku string) (*domain.ProductVariant, error)
+	ListVariants(ctx context.Context, productID uuid.UUID) ([]domain.ProductVariant, error)
+	UpdateVariant(ctx context.Context, variant *domain.ProductVariant) error
+	DeleteVariant(ctx context.Context, id uuid.UUID, version int64) error
+	CreateCategory(ctx context.Context, category *domain.Category) error
+	GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	ListCategories(ctx context.Context, opts domain.CategoryListOptions) ([]domain.Category, error)
+	UpdateCategory(ctx context.Context, category *domain.Category) error
+	DeleteCategory(ctx context.Context, id uuid.UUID, version int64) error
+	CreateAttributeDefinition(ctx context.Context, definition *domain.AttributeDefinition) error
+	GetAttributeDefinition(ctx context.Context, id uuid.UUID) (*domain.AttributeDefinition, error)
+	ListAttributeDefinitions(ctx context.Context, opts domain.AttributeDefinitionListOptions) ([]domain.AttributeDefinition, error)
+	ApplicableAttributeDefinitions(ctx context.Context, productType string, categoryIDs []uuid.UUID) ([]domain.AttributeDefinition, error)
+	UpdateAttributeDefinition(ctx context.Context, definition *domain.AttributeDefinition) error
+	DeleteAttributeDefinition(ctx context.Context, id uuid.UUID, version int64) error
+	CreateMedia(ctx context.Context, media *domain.ProductMedia) error
+	GetMedia(ctx context.Context, id uuid.UUID) (*domain.ProductMedia, error)
+	ListMedia(ctx context.Context, productID uuid.UUID) ([]domain.ProductMedia, error)
+	ReorderMedia(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) ([]domain.ProductMedia, error)
+	DeleteMedia(ctx context.Context, id uuid.UUID, version int64) error
+	UpsertTranslations(ctx context.Context, productID uuid.UUID, translations []domain.ProductTranslation) error
+	ListTranslations(ctx context.Context, productID uuid.UUID) ([]domain.ProductTranslation, error)
+	FindTranslations(ctx context.Context, productIDs []uuid.UUID, locales []string) ([]domain.ProductTranslation, error)
+	DeleteTranslation(ctx context.Context, productID uuid.UUID, locale string) error
+	ApplyDuePublications(ctx context.Context, now time.Time) (int64, error)
+	AdjustStock(ctx context.Context, item domain.StockItem, delta int64, reason string) (*domain.StockLevel, error)
+	GetStock(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID) ([]domain.StockLevel, error)
+	ReserveStock(ctx context.Context, reservation *domain.StockReservation) error
+	SettleReservation(ctx context.Context, id uuid.UUID, status domain.StockReservationStatus, now time.Time) (*domain.StockReservation, error)
+	ExpireReservations(ctx context.Context, now time.Time, limit int) (int, error)
+	ListStockMovements(ctx context.Context, opts domain.StockMovementOptions) ([]domain.StockMovement, error)
+	CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error
+	GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	ListWarehouses(ctx context.Context, opts domain.WarehouseListOptions) ([]domain.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error
+	DeleteWarehouse(ctx context.Context, id uuid.UUID, version int64) error
+	Transaction(ctx context.Context, fn func(repo ProductRepository) error) error
+}
+
+// productTypeColumns maps the product type names used by the API to the
+// foreign key column that marks a product as that type
+var productTypeColumns = map[string]string{
+	"digital":      "digital_product_id",
+	"physical":     "physical_product_id",
+	"subscription": "subscription_product_id",
+	"bundle":       "bundle_product_id",
+}
+
+// productSortColumns maps the sort fields of a listing to their columns
+var productSortColumns = map[domain.ProductSortField]string{
+	domain.ProductSortByName:      "name",
+	domain.ProductSortByPrice:     "price_amount",
+	domain.ProductSortByCreatedAt: "created_at",
 }
 
 // ProductRepositoryImpl struct implements ProductRepository interface
@@ -33,9 +109,23 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 	}
 }
 
-// Create product in the database
+// Transaction runs fn against a repository bound to a single database
+// transaction, committing if fn returns nil. Calling Transaction again on the
+// repository passed to fn creates a savepoint.
+func (r *ProductRepositoryImpl) Transaction(ctx context.Context, fn func(repo ProductRepository) error) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&ProductRepositoryImpl{DB: tx})
+	})
+}
+
+// Create product in the database together with its category memberships
 func (r *ProductRepositoryImpl) Create(product *domain.Product) error {
-	return r.DB.Create(product).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Categories", "Media").Create(product).Error; err != nil {
+			return err
+		}
+		return replaceProductCategories(tx, product)
+	})
 }
 
 // GetByID retrieves a product by its ID, including related data for DigitalProduct, PhysicalProduct, and SubscriptionProduct
@@ -45,12 +135,15 @@ func (r *ProductRepositoryImpl) GetByID(id uuid.UUID) (*domain.Product, error) {
 	err := r.DB.Preload("DigitalProduct").
 		Preload("PhysicalProduct").
 		Preload("SubscriptionProduct").
+		Preload("BundleProduct.Components", componentsInOrder).
+		Preload("Categories").
+		Preload("Media", mediaInDisplayOrder).
 		First(&product, "id = ?", id).Error
 
 	// If no product is found, return a specific error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("product with ID %s not found", id)
+			return nil, fmt.Errorf("product with ID %s %w", id, domain.ErrNotFound)
 		}
 		// Return other errors as they occur
 		return nil, fmt.Errorf("failed to fetch product with ID %s: %v", id, err)
@@ -59,14 +152,152 @@ func (r *ProductRepositoryImpl) GetByID(id uuid.UUID) (*domain.Product, error) {
 	return &product, nil
 }
 
-// Update product in the database
+// Update product and its type-specific details in a single transaction. The
+// write only succeeds if the stored version still matches product.Version,
+// which is then incremented.
 func (r *ProductRepositoryImpl) Update(product *domain.Product) error {
-	return r.DB.Save(product).Error
+	expected := product.Version
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// Save skips existing associations, so the child rows are written explicitly
+		if product.DigitalProduct != nil {
+			if err := tx.Save(product.DigitalProduct).Error; err != nil {
+				return err
+			}
+		}
+		if product.PhysicalProduct != nil {
+			if err := tx.Save(product.PhysicalProduct).Error; err != nil {
+				return err
+			}
+		}
+		if product.SubscriptionProduct != nil {
+			if err := tx.Save(product.SubscriptionProduct).Error; err != nil {
+				return err
+			}
+		}
+		if product.BundleProduct != nil {
+			if err := saveBundle(tx, product.BundleProduct); err != nil {
+				return err
+			}
+		}
+
+		product.Version = expected + 1
+		result := tx.Model(product).
+			Omit(clause.Associations).
+			Select("*").
+			Where("version = ?", expected).
+			Updates(product)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("product with ID %s was modified concurrently: %w", product.ID, domain.ErrVersionConflict)
+		}
+		return replaceProductCategories(tx, product)
+	})
+	if err != nil {
+		product.Version = expected
+	}
+	return err
 }
 
-// Delete product from the database
-func (r *ProductRepositoryImpl) Delete(id uuid.UUID) error {
-	return r.DB.Delete(&domain.Product{}, "id = ?", id).Error
+// Delete soft deletes a product if it is still at the given version. The
+// version is bumped so etags read before the delete cannot restore it.
+func (r *ProductRepositoryImpl) Delete(id uuid.UUID, version int64) error {
+	result := r.DB.Model(&domain.Product{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(map[string]interface{}{
+			"deleted_at": time.Now(),
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.versionMismatch(id)
+	}
+	return nil
+}
+
+// Restore clears the soft delete marker of a product at the given version
+func (r *ProductRepositoryImpl) Restore(id uuid.UUID, version int64) error {
+	result := r.DB.Unscoped().Model(&domain.Product{}).
+		Where("id = ? AND version = ? AND deleted_at IS NOT NULL", id, version).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var product domain.Product
+		if err := r.DB.Unscoped().Select("id", "version", "deleted_at").First(&product, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("product with ID %s %w", id, domain.ErrNotFound)
+			}
+			return err
+		}
+		if !product.DeletedAt.Valid {
+			return fmt.Errorf("product with ID %s is not deleted: %w", id, domain.ErrFailedPrecondition)
+		}
+		return fmt.Errorf("product with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+	}
+	return nil
+}
+
+// Purge permanently removes a product, deleted or not, together with its
+// type-specific details and subscription plans
+func (r *ProductRepositoryImpl) Purge(id uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var product domain.Product
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("product with ID %s %w", id, domain.ErrNotFound)
+			}
+			return err
+		}
+
+		if err := tx.Where("product_id = ?", id).Delete(&domain.SubscriptionPlan{}).Error; err != nil {
+			return err
+		}
+		// The product row references its details, so it goes before them
+		if err := tx.Unscoped().Delete(&domain.Product{}, "id = ?", id).Error; err != nil {
+			return bundleComponentError(err, "product", id)
+		}
+		if product.DigitalProductID != nil {
+			if err := tx.Delete(&domain.DigitalProduct{}, "id = ?", *product.DigitalProductID).Error; err != nil {
+				return err
+			}
+		}
+		if product.PhysicalProductID != nil {
+			if err := tx.Delete(&domain.PhysicalProduct{}, "id = ?", *product.PhysicalProductID).Error; err != nil {
+				return err
+			}
+		}
+		if product.SubscriptionProductID != nil {
+			if err := tx.Delete(&domain.SubscriptionProduct{}, "id = ?", *product.SubscriptionProductID).Error; err != nil {
+				return err
+			}
+		}
+		if product.BundleProductID != nil {
+			if err := tx.Delete(&domain.BundleProduct{}, "id = ?", *product.BundleProductID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// versionMismatch explains why a conditional write touched no rows
+func (r *ProductRepositoryImpl) versionMismatch(id uuid.UUID) error {
+	var count int64
+	if err := r.DB.Model(&domain.Product{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("product with ID %s %w", id, domain.ErrNotFound)
+	}
+	return fmt.Errorf("product with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
 }
 
 func (r *ProductRepositoryImpl) GetAllProducts(ctx context.Context) ([]domain.Product, error) {
@@ -101,10 +332,135 @@ func (r *ProductRepositoryImpl) GetSubscriptionProducts(ctx context.Context) ([]
 	return products, nil
 }
 
+// ListProducts returns one page of products using keyset pagination together with
+// the total number of rows matching the filter
+func (r *ProductRepositoryImpl) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]domain.Product, int64, error) {
+	query := r.DB.WithContext(ctx).Model(&domain.Product{})
+	if opts.Filter.ShowDeleted {
+		query = query.Unscoped()
+	}
+	query = applyProductFilter(query, opts.Filter)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := productSortColumns[opts.OrderBy]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: unsupported sort field %q", domain.ErrInvalidArgument, opts.OrderBy)
+	}
+	direction, comparator := "ASC", ">"
+	if opts.Descending {
+		direction, comparator = "DESC", "<"
+	}
+
+	page := query.Session(&gorm.Session{})
+	if opts.After != nil {
+		var value interface{}
+		switch opts.OrderBy {
+		case domain.ProductSortByName:
+			value = opts.After.Name
+		case domain.ProductSortByPrice:
+			value = opts.After.Price
+		case domain.ProductSortByCreatedAt:
+			value = opts.After.CreatedAt
+		}
+		// Row comparison keeps the query on the (column, id) index
+		page = page.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparator), value, opts.After.ID)
+	}
+
+	var products []domain.Product
+	err := page.Preload("DigitalProduct").
+		Preload("PhysicalProduct").
+		Preload("SubscriptionProduct").
+		Preload("BundleProduct.Components", componentsInOrder).
+		Preload("Categories").
+		Preload("Media", mediaInDisplayOrder).
+		Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).
+		Limit(opts.Limit).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+// applyProductFilter adds the WHERE clauses for a product filter to the query
+func applyProductFilter(query *gorm.DB, filter domain.ProductFilter) *gorm.DB {
+	if column, ok := productTypeColumns[filter.Type]; ok {
+		query = query.Where(column + " IS NOT NULL")
+	}
+	// Amounts in different currencies are not comparable, so a price bound
+	// also restricts the listing to its currency
+	if filter.MinPrice != nil {
+		query = query.Where("price_currency = ? AND price_amount >= ?", filter.MinPrice.Currency, filter.MinPrice.Amount)
+	}
+	if filter.MaxPrice != nil {
+		query = query.Where("price_currency = ? AND price_amount <= ?", filter.MaxPrice.Currency, filter.MaxPrice.Amount)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at > ?", *filter.CreatedAfter)
+	}
+	if filter.NamePrefix != "" {
+		query = query.Where("name LIKE ?", escapeLike(filter.NamePrefix)+"%")
+	}
+	if filter.CategoryID != nil {
+		if filter.IncludeDescendants {
+			// The subtree is every category whose path starts with the category's path
+			query = query.Where(`EXISTS (SELECT 1 FROM product_categories pc JOIN categories c ON c.id = pc.category_id
+				WHERE pc.product_id = products.id AND c.path LIKE (SELECT path FROM categories WHERE id = ?) || '%')`, *filter.CategoryID)
+		} else {
+			query = query.Where("EXISTS (SELECT 1 FROM product_categories pc WHERE pc.product_id = products.id AND pc.category_id = ?)", *filter.CategoryID)
+		}
+	}
+	if filter.Tag != "" {
+		query = query.Where("tags @> ?::jsonb", domain.Tags{filter.Tag})
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	for _, attribute := range filter.Attributes {
+		if attribute.Equals != nil {
+			query = query.Where("attributes @> ?::jsonb", domain.AttributeValues{attribute.Name: attribute.Equals})
+		}
+		// The CASE keeps the cast away from values that are not numbers
+		number := "CASE WHEN jsonb_typeof(attributes -> ?) = 'number' THEN (attributes ->> ?)::numeric END"
+		if attribute.Min != nil {
+			query = query.Where(number+" >= ?", attribute.Name, attribute.Name, *attribute.Min)
+		}
+		if attribute.Max != nil {
+			query = query.Where(number+" <= ?", attribute.Name, attribute.Name, *attribute.Max)
+		}
+	}
+	return query
+}
+
+// escapeLike escapes the LIKE wildcards in a user supplied string
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r *ProductRepositoryImpl) FindById(id string) (*domain.Product, error) {
     var product domain.Product
     if err := r.DB.Where("id = ?", id).First(&product).Error; err != nil {
         return nil, err
     }
     return &product, nil
-}
\ No newline at end of file
+}
+
+// FindByExternalSKU looks up a product by its external SKU. Soft deleted
+// products still own their SKU, so they are reported rather than hidden.
+func (r *ProductRepositoryImpl) FindByExternalSKU(sku string) (*domain.Product, error) {
+	var product domain.Product
+	if err := r.DB.Unscoped().Where("external_sku = ?", sku).First(&product).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("product with SKU %q %w", sku, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	if product.DeletedAt.Valid {
+		return nil, fmt.Errorf("product with SKU %q is deleted and must be restored first: %w", sku, domain.ErrFailedPrecondition)
+	}
+	return &product, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// searchHighlightOptions configures ts_headline for names and description snippets
+const (
+	nameHighlightOptions    = "StartSel=<b>, StopSel=</b>, HighlightAll=true"
+	snippetHighlightOptions = "StartSel=<b>, StopSel=</b>, MaxFragments=2, MaxWords=25, MinWords=8"
+)
+
+// searchRow is the ranked projection returned by the search query
+type searchRow struct {
+	ID                 uuid.UUID
+	Rank               float64
+	NameHighlight      string
+	DescriptionSnippet string
+}
+
+// SearchProducts runs a ranked full-text search over product names and
+// descriptions. Names that are merely similar to the query (typos) are matched
+// through trigram similarity so "headphnes" still finds "headphones".
+func (r *ProductRepositoryImpl) SearchProducts(ctx context.Context, opts domain.ProductSearchOptions) ([]domain.ProductSearchHit, error) {
+	var query *gorm.DB
+	if len(opts.Locales) == 0 {
+		query = r.DB.WithContext(ctx).
+			Table("products, websearch_to_tsquery('english', ?) AS query", opts.Query).
+			Select(
+				"products.id, "+
+					"ts_rank_cd(products.search_vector, query) + similarity(products.name, ?) AS rank, "+
+					"ts_headline('english', products.name, query, ?) AS name_highlight, "+
+					"ts_headline('english', coalesce(products.description, ''), query, ?) AS description_snippet",
+				opts.Query, nameHighlightOptions, snippetHighlightOptions,
+			).
+			Where("products.search_vector @@ query OR products.name % ?", opts.Query)
+	} else {
+		query = localizedSearchQuery(r.DB.WithContext(ctx), opts)
+	}
+	query = query.Where("products.deleted_at IS NULL")
+	if len(opts.Statuses) > 0 {
+		query = query.Where("products.status IN ?", opts.Statuses)
+	}
+
+	if len(opts.Types) > 0 {
+		var conditions []string
+		for _, productType := range opts.Types {
+			column, ok := productTypeColumns[productType]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown product type %q", domain.ErrInvalidArgument, productType)
+			}
+			conditions = append(conditions, "products."+column+" IS NOT NULL")
+		}
+		query = query.Where(strings.Join(conditions, " OR "))
+	}
+
+	var rows []searchRow
+	err := query.Order("rank DESC, products.id").
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	// Load the matched products with their details, then restore rank order
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var products []domain.Product
+	err = r.DB.WithContext(ctx).Preload("DigitalProduct").
+		Preload("PhysicalProduct").
+		Preload("SubscriptionProduct").
+		Preload("BundleProduct.Components", componentsInOrder).
+		Preload("Categories").
+		Preload("Media", mediaInDisplayOrder).
+		Where("id IN ?", ids).
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]domain.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	hits := make([]domain.ProductSearchHit, 0, len(rows))
+	for _, row := range rows {
+		product, ok := byID[row.ID]
+		if !ok {
+			// Deleted between the two queries
+			continue
+		}
+		hits = append(hits, domain.ProductSearchHit{
+			Product:            product,
+			Rank:               row.Rank,
+			NameHighlight:      row.NameHighlight,
+			DescriptionSnippet: row.DescriptionSnippet,
+		})
+	}
+	return hits, nil
+}
+
+// localizedSearchQuery matches products on their own text or on their most
+// preferred translation into opts.Locales, which is also what gets
+// highlighted. Translations can be in any language, so they are indexed and
+// queried with the language-neutral 'simple' configuration.
+func localizedSearchQuery(db *gorm.DB, opts domain.ProductSearchOptions) *gorm.DB {
+	return db.
+		Table("products, websearch_to_tsquery('english', ?) AS query, websearch_to_tsquery('simple', ?) AS localized_query", opts.Query, opts.Query).
+		Joins(`LEFT JOIN LATERAL (
+			SELECT t.name, t.description, t.search_vector FROM product_translations t
+			WHERE t.product_id = products.id AND t.locale IN ?
+			ORDER BY array_position(ARRAY[?]::text[], t.locale::text)
+			LIMIT 1
+		) AS translation ON true`, opts.Locales, opts.Locales).
+		Select(
+			"products.id, "+
+				"greatest(ts_rank_cd(products.search_vector, query) + similarity(products.name, ?), "+
+				"coalesce(ts_rank_cd(translation.search_vector, localized_query) + similarity(translation.name, ?), 0)) AS rank, "+
+				"CASE WHEN translation.name IS NULL THEN ts_headline('english', products.name, query, ?) "+
+				"ELSE ts_headline('simple', translation.name, localized_query, ?) END AS name_highlight, "+
+				"CASE WHEN coalesce(translation.description, '') = '' THEN ts_headline('english', coalesce(products.description, ''), query, ?) "+
+				"ELSE ts_headline('simple', translation.description, localized_query, ?) END AS description_snippet",
+			opts.Query, opts.Query, nameHighlightOptions, nameHighlightOptions, snippetHighlightOptions, snippetHighlightOptions,
+		).
+		Where("products.search_vector @@ query OR products.name % ? OR translation.search_vector @@ localized_query OR translation.name % ?",
+			opts.Query, opts.Query)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertTranslations inserts the translations of a product or replaces the
+// stored ones for the same locales, leaving other locales alone
+func (r *ProductRepositoryImpl) UpsertTranslations(ctx context.Context, productID uuid.UUID, translations []domain.ProductTranslation) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("product with ID %s %w", productID, domain.ErrNotFound)
+		}
+		if len(translations) == 0 {
+			return nil
+		}
+		for i := range translations {
+			translations[i].ProductID = productID
+		}
+		return tx.Omit(clause.Associations).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+			}).
+			Create(&translations).Error
+	})
+}
+
+// ListTranslations returns the translations of a product ordered by locale
+func (r *ProductRepositoryImpl) ListTranslations(ctx context.Context, productID uuid.UUID) ([]domain.ProductTranslation, error) {
+	var translations []domain.ProductTranslation
+	err := r.DB.WithContext(ctx).Where("product_id = ?", productID).Order("locale").Find(&translations).Error
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// FindTranslations returns the translations of the given products into any
+// of the given locales, in no particular order
+func (r *ProductRepositoryImpl) FindTranslations(ctx context.Context, productIDs []uuid.UUID, locales []string) ([]domain.ProductTranslation, error) {
+	if len(productIDs) == 0 || len(locales) == 0 {
+		return nil, nil
+	}
+	var translations []domain.ProductTranslation
+	err := r.DB.WithContext(ctx).
+		Where("product_id IN ? AND locale IN ?", productIDs, locales).
+		Find(&translations).Error
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// DeleteTranslation removes the translation of a product into a locale
+func (r *ProductRepositoryImpl) DeleteTranslation(ctx context.Context, productID uuid.UUID, locale string) error {
+	result := r.DB.WithContext(ctx).
+		Where("product_id = ? AND locale = ?", productID, locale).
+		Delete(&domain.ProductTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("translation of product %s into %q %w", productID, locale, domain.ErrNotFound)
+	}
+	return nil
+}
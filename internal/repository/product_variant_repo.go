@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// uniqueViolation is the SQLSTATE raised when a write breaks a unique index
+const uniqueViolation = "23505"
+
+// variantConflicts explains which unique index of product_variants a write broke
+var variantConflicts = map[string]string{
+	"idx_product_variants_sku":     "another variant has this SKU",
+	"idx_product_variants_barcode": "another variant has this barcode",
+	"idx_product_variants_options": "another variant of the product has the same options",
+}
+
+// variantWriteError turns unique violations on product_variants into ErrAlreadyExists
+func variantWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		if reason, ok := variantConflicts[pgErr.ConstraintName]; ok {
+			return fmt.Errorf("%w: %s", domain.ErrAlreadyExists, reason)
+		}
+		return fmt.Errorf("%w: %s", domain.ErrAlreadyExists, pgErr.Message)
+	}
+	return err
+}
+
+// CreateVariant stores a new variant of an existing product
+func (r *ProductRepositoryImpl) CreateVariant(ctx context.Context, variant *domain.ProductVariant) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Product{}).Where("id = ?", variant.ProductID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("product with ID %s %w", variant.ProductID, domain.ErrNotFound)
+		}
+		return variantWriteError(tx.Omit(clause.Associations).Create(variant).Error)
+	})
+}
+
+// GetVariant retrieves a variant by its ID
+func (r *ProductRepositoryImpl) GetVariant(ctx context.Context, id uuid.UUID) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	if err := r.DB.WithContext(ctx).First(&variant, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("product variant with ID %s %w", id, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// FindVariantBySKU retrieves the variant with the given SKU
+func (r *ProductRepositoryImpl) FindVariantBySKU(ctx context.Context, sku string) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	if err := r.DB.WithContext(ctx).First(&variant, "sku = ?", sku).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("product variant with SKU %q %w", sku, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// ListVariants returns the variants of a product in the order they were created
+func (r *ProductRepositoryImpl) ListVariants(ctx context.Context, productID uuid.UUID) ([]domain.ProductVariant, error) {
+	var variants []domain.ProductVariant
+	err := r.DB.WithContext(ctx).Where("product_id = ?", productID).Order("created_at, id").Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// UpdateVariant writes every field of a variant if the stored version still
+// matches variant.Version, which is then incremented
+func (r *ProductRepositoryImpl) UpdateVariant(ctx context.Context, variant *domain.ProductVariant) error {
+	expected := variant.Version
+	variant.Version = expected + 1
+	result := r.DB.WithContext(ctx).Model(variant).
+		Omit(clause.Associations, "created_at").
+		Select("*").
+		Where("version = ?", expected).
+		Updates(variant)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = fmt.Errorf("product variant with ID %s was modified concurrently: %w", variant.ID, domain.ErrVersionConflict)
+	}
+	if result.Error != nil {
+		variant.Version = expected
+		return variantWriteError(result.Error)
+	}
+	return nil
+}
+
+// DeleteVariant removes a variant if it is still at the given version
+func (r *ProductRepositoryImpl) DeleteVariant(ctx context.Context, id uuid.UUID, version int64) error {
+	db := r.DB.WithContext(ctx)
+	result := db.Where("id = ? AND version = ?", id, version).Delete(&domain.ProductVariant{})
+	if result.Error != nil {
+		return bundleComponentError(result.Error, "product variant", id)
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := db.Model(&domain.ProductVariant{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("product variant with ID %s %w", id, domain.ErrNotFound)
+		}
+		return fmt.Errorf("product variant with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// warehouseWriteError turns the name index violation into ErrAlreadyExists
+func warehouseWriteError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: a warehouse named %q already exists", domain.ErrAlreadyExists, name)
+	}
+	return err
+}
+
+// findWarehouse loads a warehouse, reporting a missing one as ErrNotFound
+func findWarehouse(db *gorm.DB, id uuid.UUID) (*domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+	if err := db.First(&warehouse, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("warehouse with ID %s %w", id, domain.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// CreateWarehouse stores a new warehouse
+func (r *ProductRepositoryImpl) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error {
+	// Select every column so an inactive warehouse is not stored with a default
+	err := r.DB.WithContext(ctx).Select("*").Create(warehouse).Error
+	return warehouseWriteError(err, warehouse.Name)
+}
+
+// GetWarehouse retrieves a warehouse by its ID
+func (r *ProductRepositoryImpl) GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	return findWarehouse(r.DB.WithContext(ctx), id)
+}
+
+// ListWarehouses returns warehouses in allocation order: by priority, then name
+func (r *ProductRepositoryImpl) ListWarehouses(ctx context.Context, opts domain.WarehouseListOptions) ([]domain.Warehouse, error) {
+	query := r.DB.WithContext(ctx).Order("priority, name, id")
+	if opts.ActiveOnly {
+		query = query.Where("active")
+	}
+	var warehouses []domain.Warehouse
+	if err := query.Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+// UpdateWarehouse writes every field of a warehouse if the stored version
+// still matches warehouse.Version, which is then incremented
+func (r *ProductRepositoryImpl) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error {
+	expected := warehouse.Version
+	warehouse.Version = expected + 1
+	result := r.DB.WithContext(ctx).Model(warehouse).
+		Omit("created_at").
+		Select("*").
+		Where("version = ?", expected).
+		Updates(warehouse)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = fmt.Errorf("warehouse with ID %s was modified concurrently: %w", warehouse.ID, domain.ErrVersionConflict)
+	}
+	if result.Error != nil {
+		warehouse.Version = expected
+		return warehouseWriteError(result.Error, warehouse.Name)
+	}
+	return nil
+}
+
+// DeleteWarehouse removes a warehouse that holds no stock if it is still at
+// the given version. Its empty stock levels go with it; the stock ledger and
+// settled reservations keep referring to it.
+func (r *ProductRepositoryImpl) DeleteWarehouse(ctx context.Context, id uuid.UUID, version int64) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stored, err := findWarehouse(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id)
+		if err != nil {
+			return err
+		}
+		if stored.Version != version {
+			return fmt.Errorf("warehouse with ID %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+		}
+		var stocked int64
+		if err := tx.Model(&domain.StockLevel{}).Where("warehouse_id = ? AND on_hand > 0", id).Count(&stocked).Error; err != nil {
+			return err
+		}
+		if stocked > 0 {
+			return fmt.Errorf("warehouse with ID %s still holds stock, which must be moved out or the warehouse deactivated: %w", id, domain.ErrFailedPrecondition)
+		}
+		return tx.Delete(&domain.Warehouse{}, "id = ?", id).Error
+	})
+}
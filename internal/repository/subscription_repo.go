@@ -2,7 +2,7 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"gorm.io/gorm"
 	"product-microservice/internal/domain"
 	"github.com/google/uuid"
@@ -12,7 +12,7 @@ type SubscriptionRepository interface {
 	Save(ctx context.Context, plan *domain.SubscriptionPlan) (*domain.SubscriptionPlan, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.SubscriptionPlan, error)
 	FindByProductID(ctx context.Context, productID uuid.UUID) ([]*domain.SubscriptionPlan, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
 	Update(ctx context.Context, subscription *domain.SubscriptionPlan) error
 	ListAll(ctx context.Context) ([]*domain.SubscriptionPlan, error)
 }
@@ -41,7 +41,7 @@ func (r *subscriptionRepository) FindByID(ctx context.Context, id uuid.UUID) (*d
 	plan := &domain.SubscriptionPlan{}
 	if err := r.db.WithContext(ctx).Where("id = ?", id).First(plan).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New("subscription plan not found")
+			return nil, fmt.Errorf("subscription plan %w", domain.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -57,32 +57,58 @@ func (r *subscriptionRepository) FindByProductID(ctx context.Context, productID
 	return plans, nil
 }
 
-// Delete removes a subscription plan from the database by its ID
-func (r *subscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.SubscriptionPlan{}).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return errors.New("subscription plan not found")
-		}
-		return err
+// Delete removes a subscription plan from the database by its ID if it is still at the given version
+func (r *subscriptionRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND version = ?", id, version).Delete(&domain.SubscriptionPlan{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.versionMismatch(ctx, id)
 	}
 	return nil
 }
 
-// Update updates an existing subscription plan in the database
+// Update updates an existing subscription plan in the database. The write only
+// succeeds if the stored version still matches subscription.Version, which is
+// then incremented.
 func (r *subscriptionRepository) Update(ctx context.Context, subscription *domain.SubscriptionPlan) error {
+	expected := subscription.Version
+	subscription.Version = expected + 1
+
 	// Update the subscription plan in the database
-	if err := r.db.Save(subscription).Error; err != nil {
-		return err
+	result := r.db.WithContext(ctx).Model(subscription).
+		Select("*").
+		Where("version = ?", expected).
+		Updates(subscription)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = r.versionMismatch(ctx, subscription.ID)
+	}
+	if result.Error != nil {
+		subscription.Version = expected
+		return result.Error
 	}
 	return nil
 }
 
+// versionMismatch explains why a conditional write touched no rows
+func (r *subscriptionRepository) versionMismatch(ctx context.Context, id uuid.UUID) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.SubscriptionPlan{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("subscription plan %w", domain.ErrNotFound)
+	}
+	return fmt.Errorf("subscription plan %s was modified concurrently: %w", id, domain.ErrVersionConflict)
+}
+
 // ListAll fetches all subscription plans from the database
 func (r *subscriptionRepository) ListAll(ctx context.Context) ([]*domain.SubscriptionPlan, error) {
 	var plans []*domain.SubscriptionPlan
 
 	// Query the database for all subscription plans without conditions
-	if err := r.db.Find(&plans).Error; err != nil {
+	if err := r.db.WithContext(ctx).Find(&plans).Error; err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// pageToken is the decoded form of the opaque page_token handed to clients.
+// The sort order is embedded so a token cannot be replayed against a
+// different ordering.
+type pageToken struct {
+	OrderBy    domain.ProductSortField `json:"o"`
+	Descending bool                    `json:"d,omitempty"`
+	Name       string                  `json:"n,omitempty"`
+	Price      *domain.Amount          `json:"p,omitempty"`
+	CreatedAt  time.Time               `json:"c,omitempty"`
+	ID         uuid.UUID               `json:"id"`
+}
+
+// parseOrderBy parses an order_by expression such as "price desc"
+func parseOrderBy(orderBy string) (domain.ProductSortField, bool, error) {
+	fields := strings.Fields(strings.ToLower(orderBy))
+	if len(fields) == 0 {
+		return domain.ProductSortByCreatedAt, false, nil
+	}
+	if len(fields) > 2 {
+		return "", false, fmt.Errorf("%w: malformed order_by %q", domain.ErrInvalidArgument, orderBy)
+	}
+
+	field := domain.ProductSortField(fields[0])
+	switch field {
+	case domain.ProductSortByName, domain.ProductSortByPrice, domain.ProductSortByCreatedAt:
+	default:
+		return "", false, fmt.Errorf("%w: cannot order by %q", domain.ErrInvalidArgument, fields[0])
+	}
+
+	descending := false
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "asc":
+		case "desc":
+			descending = true
+		default:
+			return "", false, fmt.Errorf("%w: unknown sort direction %q", domain.ErrInvalidArgument, fields[1])
+		}
+	}
+	return field, descending, nil
+}
+
+// encodePageToken builds the token pointing after the given product
+func encodePageToken(orderBy domain.ProductSortField, descending bool, last domain.Product) string {
+	token := pageToken{OrderBy: orderBy, Descending: descending, ID: last.ID}
+	switch orderBy {
+	case domain.ProductSortByName:
+		token.Name = last.Name
+	case domain.ProductSortByPrice:
+		token.Price = &last.Price.Amount
+	case domain.ProductSortByCreatedAt:
+		token.CreatedAt = last.CreatedAt
+	}
+	raw, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodePageToken validates a client supplied token against the requested ordering
+func decodePageToken(s string, orderBy domain.ProductSortField, descending bool) (*domain.ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed page_token", domain.ErrInvalidArgument)
+	}
+
+	var token pageToken
+	if err := json.Unmarshal(raw, &token); err != nil {
+		return nil, fmt.Errorf("%w: malformed page_token", domain.ErrInvalidArgument)
+	}
+	if token.OrderBy != orderBy || token.Descending != descending {
+		return nil, fmt.Errorf("%w: page_token does not match order_by", domain.ErrInvalidArgument)
+	}
+
+	cursor := &domain.ProductCursor{
+		Name:      token.Name,
+		CreatedAt: token.CreatedAt,
+		ID:        token.ID,
+	}
+	if token.Price != nil {
+		cursor.Price = *token.Price
+	}
+	return cursor, nil
+}
+
+// offsetToken is the decoded form of page tokens for ranked results, where
+// keyset pagination is not possible because the sort key is computed
+type offsetToken struct {
+	Offset int `json:"off"`
+}
+
+func encodeOffsetToken(offset int) string {
+	raw, _ := json.Marshal(offsetToken{Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeOffsetToken(s string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed page_token", domain.ErrInvalidArgument)
+	}
+
+	var token offsetToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.Offset < 0 {
+		return 0, fmt.Errorf("%w: malformed page_token", domain.ErrInvalidArgument)
+	}
+	return token.Offset, nil
+}
+
+// normalizePageSize applies the default and maximum page sizes
+func normalizePageSize(pageSize int32) (int, error) {
+	switch {
+	case pageSize < 0:
+		return 0, fmt.Errorf("%w: page_size must not be negative", domain.ErrInvalidArgument)
+	case pageSize == 0:
+		return defaultPageSize, nil
+	case pageSize > maxPageSize:
+		return maxPageSize, nil
+	}
+	return int(pageSize), nil
+}
+
+// historyToken is the decoded form of price history page tokens, pointing
+// after the last row of the previous page
+type historyToken struct {
+	BeforeID int64 `json:"b"`
+}
+
+func encodeHistoryToken(beforeID int64) string {
+	raw, _ := json.Marshal(historyToken{BeforeID: beforeID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeHistoryToken(s string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed page_token", domain.ErrInvalidArgument)
+	}
+
+	var token historyToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.BeforeID <= 0 {
+		return 0, fmt.Errorf("%w: malformed page_token", domain.ErrInvalidArgument)
+	}
+	return token.BeforeID, nil
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// attributeNamePattern restricts attribute names to identifiers, so they can
+// appear in update mask paths such as "attributes.screen_size"
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// attributeFieldSetters lists every path UpdateAttributeDefinition accepts in
+// its update mask. The name, type and scope of a definition are immutable
+// since product values depend on them.
+var attributeFieldSetters = map[string]func(dst, src *domain.AttributeDefinition){
+	"description": func(dst, src *domain.AttributeDefinition) { dst.Description = src.Description },
+	"required":    func(dst, src *domain.AttributeDefinition) { dst.Required = src.Required },
+	"unit":        func(dst, src *domain.AttributeDefinition) { dst.Unit = src.Unit },
+	"enum_values": func(dst, src *domain.AttributeDefinition) { dst.EnumValues = src.EnumValues },
+}
+
+// validateAttributeDefinition normalises and checks the fields a client can set
+func validateAttributeDefinition(definition *domain.AttributeDefinition) error {
+	if !attributeNamePattern.MatchString(definition.Name) {
+		return fmt.Errorf("%w: attribute name %q must be lower case letters, digits and underscores, starting with a letter", domain.ErrInvalidArgument, definition.Name)
+	}
+	switch definition.Type {
+	case domain.AttributeTypeString, domain.AttributeTypeNumber, domain.AttributeTypeBool, domain.AttributeTypeEnum:
+	default:
+		return fmt.Errorf("%w: attribute type is required", domain.ErrInvalidArgument)
+	}
+
+	if (definition.ProductType == nil) == (definition.CategoryID == nil) {
+		return fmt.Errorf("%w: exactly one of product_type and category_id is required", domain.ErrInvalidArgument)
+	}
+	if definition.ProductType != nil {
+		if err := domain.ValidateProductType(*definition.ProductType); err != nil {
+			return fmt.Errorf("product_type: %w", err)
+		}
+	}
+
+	definition.Unit = strings.TrimSpace(definition.Unit)
+	if definition.Unit != "" && definition.Type != domain.AttributeTypeNumber {
+		return fmt.Errorf("%w: only number attributes have a unit", domain.ErrInvalidArgument)
+	}
+
+	if definition.Type != domain.AttributeTypeEnum {
+		if len(definition.EnumValues) > 0 {
+			return fmt.Errorf("%w: only enum attributes have enum_values", domain.ErrInvalidArgument)
+		}
+		return nil
+	}
+	if len(definition.EnumValues) == 0 {
+		return fmt.Errorf("%w: enum attributes need enum_values", domain.ErrInvalidArgument)
+	}
+	seen := make(map[string]bool, len(definition.EnumValues))
+	values := make(domain.EnumValues, len(definition.EnumValues))
+	for i, value := range definition.EnumValues {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return fmt.Errorf("%w: enum values cannot be blank", domain.ErrInvalidArgument)
+		}
+		if seen[value] {
+			return fmt.Errorf("%w: duplicate enum value %q", domain.ErrInvalidArgument, value)
+		}
+		seen[value] = true
+		values[i] = value
+	}
+	definition.EnumValues = values
+	return nil
+}
+
+// validateAttributes checks the attribute values of a product against the
+// definitions that apply to it: every value must be defined and of the right
+// type and, if requireAll is set, every required attribute must be set.
+// Required attributes are only enforced when a product is created or
+// published or its attributes are updated, so a newly required attribute
+// does not block unrelated updates before it is backfilled.
+func (s *productService) validateAttributes(ctx context.Context, product *domain.Product, requireAll bool) error {
+	definitions, err := s.ProductRepo.ApplicableAttributeDefinitions(ctx, product.Type(), product.CategoryIDs())
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*domain.AttributeDefinition, len(definitions))
+	for i := range definitions {
+		byName[definitions[i].Name] = &definitions[i]
+	}
+
+	for name, value := range product.Attributes {
+		definition, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("%w: attribute %q is not defined for the type or categories of the product", domain.ErrInvalidArgument, name)
+		}
+		if err := definition.CheckValue(value); err != nil {
+			return err
+		}
+	}
+	for _, definition := range definitions {
+		if _, ok := product.Attributes[definition.Name]; requireAll && definition.Required && !ok {
+			return fmt.Errorf("%w: attribute %q is required", domain.ErrInvalidArgument, definition.Name)
+		}
+	}
+	if product.Attributes == nil {
+		product.Attributes = domain.AttributeValues{}
+	}
+	return nil
+}
+
+// updatesAttributes reports whether an update mask writes any attribute
+func updatesAttributes(paths []string) bool {
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "attributes" || strings.HasPrefix(path, "attributes.") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAttributeFilters converts the attribute filters of a listing and
+// checks them against the definitions of the attributes they name
+func (s *productService) parseAttributeFilters(ctx context.Context, pbFilters []*pb.AttributeFilter) ([]domain.AttributeFilter, error) {
+	if len(pbFilters) == 0 {
+		return nil, nil
+	}
+	names := make([]string, len(pbFilters))
+	for i, pbFilter := range pbFilters {
+		names[i] = pbFilter.GetName()
+	}
+	definitions, err := s.ProductRepo.ListAttributeDefinitions(ctx, domain.AttributeDefinitionListOptions{Names: names})
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*domain.AttributeDefinition, len(definitions))
+	for i := range definitions {
+		byName[definitions[i].Name] = &definitions[i]
+	}
+
+	filters := make([]domain.AttributeFilter, len(pbFilters))
+	for i, pbFilter := range pbFilters {
+		definition, ok := byName[pbFilter.GetName()]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown attribute %q in attribute_filters", domain.ErrInvalidArgument, pbFilter.GetName())
+		}
+		filter := domain.AttributeFilter{Name: definition.Name, Min: pbFilter.Min, Max: pbFilter.Max}
+		if pbFilter.GetEquals() != nil {
+			filter.Equals = mapper.AttributeValueFromProto(pbFilter.GetEquals())
+			if err := definition.CheckValue(filter.Equals); err != nil {
+				return nil, err
+			}
+		}
+		if (filter.Min != nil || filter.Max != nil) && definition.Type != domain.AttributeTypeNumber {
+			return nil, fmt.Errorf("%w: min and max only apply to number attributes, not %q", domain.ErrInvalidArgument, definition.Name)
+		}
+		if filter.Equals == nil && filter.Min == nil && filter.Max == nil {
+			return nil, fmt.Errorf("%w: attribute filter on %q needs equals, min or max", domain.ErrInvalidArgument, definition.Name)
+		}
+		filters[i] = filter
+	}
+	return filters, nil
+}
+
+// CreateAttributeDefinition adds an attribute definition
+func (s *productService) CreateAttributeDefinition(ctx context.Context, definition *domain.AttributeDefinition) (*domain.AttributeDefinition, error) {
+	if err := validateAttributeDefinition(definition); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.CreateAttributeDefinition(ctx, definition); err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+// GetAttributeDefinition retrieves an attribute definition by its ID
+func (s *productService) GetAttributeDefinition(ctx context.Context, id uuid.UUID) (*domain.AttributeDefinition, error) {
+	return s.ProductRepo.GetAttributeDefinition(ctx, id)
+}
+
+// ListAttributeDefinitions lists the attribute definitions of a product type
+// or category, or all of them
+func (s *productService) ListAttributeDefinitions(ctx context.Context, opts domain.AttributeDefinitionListOptions) ([]domain.AttributeDefinition, error) {
+	return s.ProductRepo.ListAttributeDefinitions(ctx, opts)
+}
+
+// UpdateAttributeDefinition updates the fields of a definition named in the
+// mask, or all mutable ones if the mask is empty. update.Version must be the
+// version the client read.
+func (s *productService) UpdateAttributeDefinition(ctx context.Context, id uuid.UUID, update *domain.AttributeDefinition, updateMask []string) (*domain.AttributeDefinition, error) {
+	definition, err := s.ProductRepo.GetAttributeDefinition(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.Version != definition.Version {
+		return nil, fmt.Errorf("attribute definition with ID %s has changed since it was read: %w", id, domain.ErrVersionConflict)
+	}
+
+	paths := updateMask
+	if len(paths) == 0 {
+		paths = []string{"description", "required", "unit", "enum_values"}
+	}
+	setters := make([]func(dst, src *domain.AttributeDefinition), 0, len(paths))
+	for _, path := range paths {
+		setter, ok := attributeFieldSetters[strings.TrimSpace(path)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown or immutable update_mask path %q", domain.ErrInvalidArgument, path)
+		}
+		setters = append(setters, setter)
+	}
+	for _, setter := range setters {
+		setter(definition, update)
+	}
+
+	if err := validateAttributeDefinition(definition); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.UpdateAttributeDefinition(ctx, definition); err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+// DeleteAttributeDefinition removes a definition no product uses at the given version
+func (s *productService) DeleteAttributeDefinition(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.ProductRepo.DeleteAttributeDefinition(ctx, id, version)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// maxBatchSize caps the number of items accepted by a single batch call
+const maxBatchSize = 1000
+
+// BatchResult is the outcome of one item of a batch call. Exactly one of
+// Product and Err is set, except for successful deletes where both are nil.
+type BatchResult struct {
+	Product *domain.Product
+	Err     error
+}
+
+// ProductUpdate is a single item of a batch update
+type ProductUpdate struct {
+	ID         uuid.UUID
+	Product    *domain.Product
+	UpdateMask []string
+}
+
+// ProductDeletion is a single item of a batch delete
+type ProductDeletion struct {
+	ID      uuid.UUID
+	Version int64
+}
+
+func (s *productService) BatchCreateProducts(ctx context.Context, products []*domain.Product, allOrNothing bool) ([]BatchResult, error) {
+	return s.runBatch(ctx, len(products), allOrNothing, func(tx *productService, i int) (*domain.Product, error) {
+		return tx.CreateProduct(ctx, products[i])
+	})
+}
+
+func (s *productService) BatchGetProducts(ctx context.Context, ids []uuid.UUID, allOrNothing bool) ([]BatchResult, error) {
+	return s.runBatch(ctx, len(ids), allOrNothing, func(tx *productService, i int) (*domain.Product, error) {
+		return tx.GetProductByID(ids[i])
+	})
+}
+
+func (s *productService) BatchUpdateProducts(ctx context.Context, updates []ProductUpdate, allOrNothing bool) ([]BatchResult, error) {
+	return s.runBatch(ctx, len(updates), allOrNothing, func(tx *productService, i int) (*domain.Product, error) {
+		return tx.UpdateProduct(ctx, updates[i].ID, updates[i].Product, updates[i].UpdateMask)
+	})
+}
+
+func (s *productService) BatchDeleteProducts(ctx context.Context, deletions []ProductDeletion, allOrNothing bool) ([]BatchResult, error) {
+	return s.runBatch(ctx, len(deletions), allOrNothing, func(tx *productService, i int) (*domain.Product, error) {
+		return nil, tx.DeleteProduct(deletions[i].ID, deletions[i].Version)
+	})
+}
+
+// withRepo returns a copy of the service that runs against repo, so batch
+// items keep every other dependency of a single call
+func (s *productService) withRepo(repo repository.ProductRepository) *productService {
+	tx := *s
+	tx.ProductRepo = repo
+	return &tx
+}
+
+// runBatch executes n items inside one transaction. In all-or-nothing mode the
+// first failure rolls everything back and is returned with its item index.
+// Otherwise each item runs in its own savepoint so a failing item is rolled
+// back on its own and reported in its result while the rest are committed.
+func (s *productService) runBatch(ctx context.Context, n int, allOrNothing bool, item func(tx *productService, i int) (*domain.Product, error)) ([]BatchResult, error) {
+	if n == 0 {
+		return nil, fmt.Errorf("%w: batch must contain at least one item", domain.ErrInvalidArgument)
+	}
+	if n > maxBatchSize {
+		return nil, fmt.Errorf("%w: batch size %d exceeds the limit of %d", domain.ErrInvalidArgument, n, maxBatchSize)
+	}
+
+	results := make([]BatchResult, n)
+	err := s.ProductRepo.Transaction(ctx, func(repo repository.ProductRepository) error {
+		for i := 0; i < n; i++ {
+			if allOrNothing {
+				product, err := item(s.withRepo(repo), i)
+				if err != nil {
+					return fmt.Errorf("item %d: %w", i, err)
+				}
+				results[i] = BatchResult{Product: product}
+				continue
+			}
+
+			var product *domain.Product
+			err := repo.Transaction(ctx, func(itemRepo repository.ProductRepository) error {
+				var err error
+				product, err = item(s.withRepo(itemRepo), i)
+				return err
+			})
+			if err != nil {
+				results[i] = BatchResult{Err: err}
+				continue
+			}
+			results[i] = BatchResult{Product: product}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
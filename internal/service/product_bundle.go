@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-microservice/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// prepareBundle checks the components of a bundle product against the
+// catalog and, for percent off pricing, sets the product price to the
+// discounted sum of the component prices. Other products are left alone.
+// That price is a snapshot: it is worked out again whenever the bundle is
+// created, updated, imported or published, but not when a component price
+// changes on its own.
+func (s *productService) prepareBundle(ctx context.Context, product *domain.Product) error {
+	bundle := product.BundleProduct
+	if bundle == nil {
+		return nil
+	}
+	switch bundle.Pricing {
+	case "":
+		bundle.Pricing = domain.BundlePricingFixed
+	case domain.BundlePricingFixed, domain.BundlePricingPercentOff:
+	default:
+		return fmt.Errorf("%w: unknown bundle pricing %q", domain.ErrInvalidArgument, bundle.Pricing)
+	}
+	if bundle.Pricing == domain.BundlePricingFixed && !bundle.DiscountPercent.IsZero() {
+		return fmt.Errorf("%w: discount_percent only applies to percent off pricing", domain.ErrInvalidArgument)
+	}
+	if err := domain.ValidatePercent(bundle.DiscountPercent); err != nil {
+		return fmt.Errorf("discount_percent: %w", err)
+	}
+	if len(bundle.Components) == 0 {
+		return fmt.Errorf("%w: a bundle needs at least one component", domain.ErrInvalidArgument)
+	}
+
+	type key struct {
+		productID uuid.UUID
+		variantID uuid.UUID
+	}
+	seen := make(map[key]bool, len(bundle.Components))
+	var sum *domain.Money
+	for i := range bundle.Components {
+		component := &bundle.Components[i]
+		component.Position = int32(i)
+		if component.Quantity < 1 {
+			return fmt.Errorf("%w: component quantity must be at least 1", domain.ErrInvalidArgument)
+		}
+		if product.ID != uuid.Nil && component.ProductID == product.ID {
+			return fmt.Errorf("%w: a bundle cannot contain itself", domain.ErrInvalidArgument)
+		}
+		k := key{productID: component.ProductID}
+		if component.VariantID != nil {
+			k.variantID = *component.VariantID
+		}
+		if seen[k] {
+			return fmt.Errorf("%w: product %s is listed more than once, use its quantity instead", domain.ErrInvalidArgument, component.ProductID)
+		}
+		seen[k] = true
+
+		price, err := s.componentPrice(ctx, component)
+		if err != nil {
+			return err
+		}
+		if sum == nil {
+			sum = &domain.Money{Currency: price.Currency}
+		} else if price.Currency != sum.Currency {
+			return fmt.Errorf("%w: bundle components must all be priced in one currency", domain.ErrInvalidArgument)
+		}
+		line, err := price.Amount.MulInt(int64(component.Quantity))
+		if err != nil {
+			return err
+		}
+		if sum.Amount, err = sum.Amount.Add(line); err != nil {
+			return err
+		}
+	}
+
+	if bundle.Pricing == domain.BundlePricingPercentOff {
+		price, err := domain.PercentOff(*sum, bundle.DiscountPercent)
+		if err != nil {
+			return err
+		}
+		product.Price = price
+		return nil
+	}
+	if product.Price.Currency != sum.Currency {
+		return fmt.Errorf("%w: the price of a bundle must be in the currency of its components %s", domain.ErrInvalidArgument, sum.Currency)
+	}
+	return nil
+}
+
+// componentPrice loads the product, and variant if any, of a bundle
+// component, makes sure it can be bundled and returns the price of one
+func (s *productService) componentPrice(ctx context.Context, component *domain.BundleComponent) (domain.Money, error) {
+	product, err := s.ProductRepo.GetByID(component.ProductID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Money{}, fmt.Errorf("%w: component product %s does not exist", domain.ErrInvalidArgument, component.ProductID)
+	}
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if product.BundleProduct != nil {
+		return domain.Money{}, fmt.Errorf("%w: product %s is a bundle, bundles cannot contain other bundles", domain.ErrInvalidArgument, product.ID)
+	}
+	if product.Status != domain.ProductStatusPublished {
+		return domain.Money{}, fmt.Errorf("%w: component product %s is %s, only published products can be bundled", domain.ErrFailedPrecondition, product.ID, product.Status)
+	}
+	if component.VariantID == nil {
+		return product.Price, nil
+	}
+
+	variant, err := s.ProductRepo.GetVariant(ctx, *component.VariantID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Money{}, fmt.Errorf("%w: component variant %s does not exist", domain.ErrInvalidArgument, *component.VariantID)
+	}
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if variant.ProductID != product.ID {
+		return domain.Money{}, fmt.Errorf("%w: variant %s does not belong to product %s", domain.ErrInvalidArgument, variant.ID, product.ID)
+	}
+	return variant.Price(product.Price), nil
+}
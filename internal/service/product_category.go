@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxTagLength bounds the length of a product tag in characters
+const maxTagLength = 64
+
+// categoryFieldSetters lists every path UpdateCategory accepts in its update mask
+var categoryFieldSetters = map[string]func(dst, src *domain.Category){
+	"name":        func(dst, src *domain.Category) { dst.Name = src.Name },
+	"description": func(dst, src *domain.Category) { dst.Description = src.Description },
+	"parent_id":   func(dst, src *domain.Category) { dst.ParentID = src.ParentID },
+}
+
+// normalizeTag lower-cases and trims a tag
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("%w: tags cannot be blank", domain.ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(tag) > maxTagLength {
+		return "", fmt.Errorf("%w: tag %q is longer than %d characters", domain.ErrInvalidArgument, tag, maxTagLength)
+	}
+	return tag, nil
+}
+
+// normalizeTags normalises every tag of a product and drops duplicates,
+// keeping the first occurrence
+func normalizeTags(tags domain.Tags) (domain.Tags, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make(domain.Tags, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := normalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// dedupeCategories drops repeated categories of a product
+func dedupeCategories(categories []domain.Category) []domain.Category {
+	seen := make(map[uuid.UUID]bool, len(categories))
+	deduped := make([]domain.Category, 0, len(categories))
+	for _, category := range categories {
+		if !seen[category.ID] {
+			seen[category.ID] = true
+			deduped = append(deduped, category)
+		}
+	}
+	return deduped
+}
+
+// validateCategory normalises and checks the fields a client can set
+func validateCategory(category *domain.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return fmt.Errorf("%w: category name cannot be empty", domain.ErrInvalidArgument)
+	}
+	if category.ParentID != nil && *category.ParentID == category.ID {
+		return fmt.Errorf("%w: a category cannot be its own parent", domain.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// CreateCategory adds a category below its parent, or at the top level
+func (s *productService) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	if err := validateCategory(category); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// GetCategory retrieves a category by its ID
+func (s *productService) GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	return s.ProductRepo.GetCategory(ctx, id)
+}
+
+// ListCategories lists the children of a category, or its whole subtree
+func (s *productService) ListCategories(ctx context.Context, opts domain.CategoryListOptions) ([]domain.Category, error) {
+	return s.ProductRepo.ListCategories(ctx, opts)
+}
+
+// UpdateCategory updates the fields of a category named in the mask, or all
+// of them if the mask is empty. Changing parent_id moves the category
+// together with its subtree. update.Version must be the version the client read.
+func (s *productService) UpdateCategory(ctx context.Context, id uuid.UUID, update *domain.Category, updateMask []string) (*domain.Category, error) {
+	category, err := s.ProductRepo.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.Version != category.Version {
+		return nil, fmt.Errorf("category with ID %s has changed since it was read: %w", id, domain.ErrVersionConflict)
+	}
+
+	paths := updateMask
+	if len(paths) == 0 {
+		paths = []string{"name", "description", "parent_id"}
+	}
+	setters := make([]func(dst, src *domain.Category), 0, len(paths))
+	for _, path := range paths {
+		setter, ok := categoryFieldSetters[strings.TrimSpace(path)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown or immutable update_mask path %q", domain.ErrInvalidArgument, path)
+		}
+		setters = append(setters, setter)
+	}
+	for _, setter := range setters {
+		setter(category, update)
+	}
+
+	if err := validateCategory(category); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// DeleteCategory removes a category without subcategories at the given version
+func (s *productService) DeleteCategory(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.ProductRepo.DeleteCategory(ctx, id, version)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"product-microservice/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultReservationTTL is how long a reservation holds when the caller does not say
+	defaultReservationTTL = 15 * time.Minute
+	// maxReservationTTL caps how long a reservation can hold stock
+	maxReservationTTL = 24 * time.Hour
+	// reservationExpiryInterval is how often expired reservations are released
+	reservationExpiryInterval = 30 * time.Second
+	// reservationExpiryBatchSize caps the reservations expired per transaction
+	reservationExpiryBatchSize = 100
+)
+
+// checkStockItem makes sure stock can be kept for an item: the product must
+// be a physical product and the variant, if any, one of its own. The
+// warehouse is checked by the caller.
+func (s *productService) checkStockItem(ctx context.Context, item domain.StockItem) error {
+	product, err := s.ProductRepo.GetByID(item.ProductID)
+	if err != nil {
+		return err
+	}
+	if product.PhysicalProduct == nil {
+		return fmt.Errorf("%w: stock is only tracked for physical products", domain.ErrInvalidArgument)
+	}
+	if item.VariantID == nil {
+		return nil
+	}
+	variant, err := s.ProductRepo.GetVariant(ctx, *item.VariantID)
+	if err != nil {
+		return err
+	}
+	if variant.ProductID != item.ProductID {
+		return fmt.Errorf("%w: variant %s does not belong to product %s", domain.ErrInvalidArgument, variant.ID, item.ProductID)
+	}
+	return nil
+}
+
+// AdjustStock changes the units of an item on hand in a warehouse by delta
+func (s *productService) AdjustStock(ctx context.Context, item domain.StockItem, delta int64, reason string) (*domain.StockLevel, error) {
+	if delta == 0 {
+		return nil, fmt.Errorf("%w: delta cannot be zero", domain.ErrInvalidArgument)
+	}
+	if item.WarehouseID == uuid.Nil {
+		return nil, fmt.Errorf("%w: warehouse_id is required", domain.ErrInvalidArgument)
+	}
+	if _, err := s.ProductRepo.GetWarehouse(ctx, item.WarehouseID); err != nil {
+		return nil, err
+	}
+	if err := s.checkStockItem(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.ProductRepo.AdjustStock(ctx, item, delta, strings.TrimSpace(reason))
+}
+
+// GetStock returns the stock levels of a product, or of one of its variants,
+// in every warehouse
+func (s *productService) GetStock(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID) ([]domain.StockLevel, error) {
+	if _, err := s.ProductRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.ProductRepo.GetStock(ctx, productID, variantID)
+}
+
+// ReserveStock sets units of an item aside for ttl, or for
+// defaultReservationTTL if ttl is zero. Without a warehouse the units are
+// allocated from the first active warehouse by priority that has enough.
+func (s *productService) ReserveStock(ctx context.Context, reservation *domain.StockReservation, ttl time.Duration) (*domain.StockReservation, error) {
+	if reservation.Quantity < 1 {
+		return nil, fmt.Errorf("%w: quantity must be at least 1", domain.ErrInvalidArgument)
+	}
+	if ttl == 0 {
+		ttl = defaultReservationTTL
+	}
+	if ttl < 0 || ttl > maxReservationTTL {
+		return nil, fmt.Errorf("%w: ttl must be positive and at most %s", domain.ErrInvalidArgument, maxReservationTTL)
+	}
+	if reservation.WarehouseID != uuid.Nil {
+		warehouse, err := s.ProductRepo.GetWarehouse(ctx, reservation.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		if !warehouse.Active {
+			return nil, fmt.Errorf("%w: warehouse %s is inactive", domain.ErrFailedPrecondition, warehouse.ID)
+		}
+	}
+	if err := s.checkStockItem(ctx, reservation.StockItem); err != nil {
+		return nil, err
+	}
+
+	reservation.Status = domain.StockReservationPending
+	reservation.Reference = strings.TrimSpace(reservation.Reference)
+	reservation.ExpiresAt = time.Now().Add(ttl)
+	if err := s.ProductRepo.ReserveStock(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ReleaseReservation gives the units of a pending reservation back to the available stock
+func (s *productService) ReleaseReservation(ctx context.Context, id uuid.UUID) (*domain.StockReservation, error) {
+	return s.ProductRepo.SettleReservation(ctx, id, domain.StockReservationReleased, time.Now())
+}
+
+// CommitReservation takes the units of a pending reservation off hand, as
+// when the order holding it ships
+func (s *productService) CommitReservation(ctx context.Context, id uuid.UUID) (*domain.StockReservation, error) {
+	return s.ProductRepo.SettleReservation(ctx, id, domain.StockReservationCommitted, time.Now())
+}
+
+// ListStockMovements lists the stock ledger of a product newest first,
+// optionally narrowed to one variant and one warehouse
+func (s *productService) ListStockMovements(ctx context.Context, productID uuid.UUID, variantID, warehouseID *uuid.UUID, pageSize int32, pageToken string) ([]domain.StockMovement, string, error) {
+	limit, err := normalizePageSize(pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+	opts := domain.StockMovementOptions{
+		ProductID:   productID,
+		VariantID:   variantID,
+		WarehouseID: warehouseID,
+		// Fetch one extra row to learn whether another page follows
+		Limit: limit + 1,
+	}
+	if pageToken != "" {
+		if opts.BeforeID, err = decodeHistoryToken(pageToken); err != nil {
+			return nil, "", err
+		}
+	}
+
+	movements, err := s.ProductRepo.ListStockMovements(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	nextPageToken := ""
+	if len(movements) > limit {
+		movements = movements[:limit]
+		nextPageToken = encodeHistoryToken(movements[limit-1].ID)
+	}
+	return movements, nextPageToken, nil
+}
+
+// ExpireReservations releases every pending reservation that expired by now
+// and returns how many there were
+func (s *productService) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		n, err := s.ProductRepo.ExpireReservations(ctx, now, reservationExpiryBatchSize)
+		total += n
+		if err != nil || n < reservationExpiryBatchSize {
+			return total, err
+		}
+	}
+}
+
+// RunReservationExpiry expires reservations every reservationExpiryInterval
+// until ctx is done. Errors are logged and retried on the next tick.
+func (s *productService) RunReservationExpiry(ctx context.Context) {
+	ticker := time.NewTicker(reservationExpiryInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := s.ExpireReservations(ctx, time.Now()); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to expire stock reservations: %v", err)
+		} else if n > 0 {
+			log.Printf("Expired %d stock reservations", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
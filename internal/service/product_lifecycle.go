@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// publishSchedulerInterval is how often the publish scheduler looks for due publications
+const publishSchedulerInterval = 30 * time.Second
+
+// PublishProduct publishes a product in review at the given version, now or
+// at publishAt, and schedules it to be archived at unpublishAt if set. For a
+// product that is already published only unpublishAt is changed.
+func (s *productService) PublishProduct(ctx context.Context, id uuid.UUID, version int64, publishAt, unpublishAt *time.Time) (*domain.Product, error) {
+	product, err := s.ProductRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.Version != version {
+		return nil, fmt.Errorf("product with ID %s has changed since it was read: %w", id, domain.ErrVersionConflict)
+	}
+
+	now := time.Now()
+	if publishAt != nil && !publishAt.After(now) {
+		return nil, fmt.Errorf("%w: publish_at must be in the future", domain.ErrInvalidArgument)
+	}
+	if unpublishAt != nil {
+		goesLive := now
+		if publishAt != nil {
+			goesLive = *publishAt
+		}
+		if !unpublishAt.After(goesLive) {
+			return nil, fmt.Errorf("%w: unpublish_at must be after the product is published", domain.ErrInvalidArgument)
+		}
+	}
+
+	if product.Status == domain.ProductStatusPublished {
+		if publishAt != nil {
+			return nil, fmt.Errorf("%w: product with ID %s is already published", domain.ErrFailedPrecondition, id)
+		}
+	} else {
+		if !product.Status.CanTransitionTo(domain.ProductStatusPublished) {
+			return nil, fmt.Errorf("%w: a %s product cannot be published, it has to be in review", domain.ErrFailedPrecondition, product.Status)
+		}
+		// The components of a bundle may have been archived since it was written
+		if err := s.prepareBundle(ctx, product); err != nil {
+			return nil, err
+		}
+		if err := s.validateAttributes(ctx, product, true); err != nil {
+			return nil, err
+		}
+		if publishAt != nil {
+			product.PublishAt = publishAt
+		} else {
+			product.Status, product.PublishAt, product.PublishedAt = domain.ProductStatusPublished, nil, &now
+		}
+	}
+	product.UnpublishAt = unpublishAt
+
+	if err := s.ProductRepo.Update(product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// ArchiveProduct archives a product at the given version and cancels any
+// publication scheduled for it
+func (s *productService) ArchiveProduct(ctx context.Context, id uuid.UUID, version int64) (*domain.Product, error) {
+	product, err := s.ProductRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.Version != version {
+		return nil, fmt.Errorf("product with ID %s has changed since it was read: %w", id, domain.ErrVersionConflict)
+	}
+	if !product.Status.CanTransitionTo(domain.ProductStatusArchived) {
+		return nil, fmt.Errorf("%w: a %s product cannot be archived", domain.ErrFailedPrecondition, product.Status)
+	}
+
+	product.Status, product.PublishAt, product.UnpublishAt = domain.ProductStatusArchived, nil, nil
+	if err := s.ProductRepo.Update(product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// ApplyDuePublications publishes and archives the products whose scheduled
+// times have passed by now and returns how many changed
+func (s *productService) ApplyDuePublications(ctx context.Context, now time.Time) (int64, error) {
+	return s.ProductRepo.ApplyDuePublications(ctx, now)
+}
+
+// RunPublishScheduler applies due publications every publishSchedulerInterval
+// until ctx is done. Errors are logged and retried on the next tick.
+func (s *productService) RunPublishScheduler(ctx context.Context) {
+	ticker := time.NewTicker(publishSchedulerInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := s.ApplyDuePublications(ctx, time.Now()); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to apply scheduled publications: %v", err)
+		} else if n > 0 {
+			log.Printf("Published or archived %d scheduled products", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseStatuses converts the lifecycle states a listing is restricted to,
+// rejecting unspecified and unknown values
+func parseStatuses(pbStatuses []pb.ProductStatus) ([]domain.ProductStatus, error) {
+	statuses := make([]domain.ProductStatus, 0, len(pbStatuses))
+	for _, pbStatus := range pbStatuses {
+		status := mapper.ProductStatusFromProto(pbStatus)
+		if status == "" {
+			return nil, fmt.Errorf("%w: unknown product status %v", domain.ErrInvalidArgument, pbStatus)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
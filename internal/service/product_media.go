@@ -0,0 +1,202 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"image"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"product-microservice/internal/domain"
+	"regexp"
+	"strings"
+
+	// Image formats whose dimensions are read from uploads
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxMediaSize bounds the size of a single upload in bytes
+	maxMediaSize = 20 << 20
+	// sniffLength is the number of leading bytes content types are detected from
+	sniffLength = 512
+)
+
+// mediaExtension matches the file name extensions kept in storage keys
+var mediaExtension = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+// decodableImageTypes are the image types whose dimensions are read; an
+// upload declared as one of them must decode
+var decodableImageTypes = map[string]bool{"image/gif": true, "image/jpeg": true, "image/png": true}
+
+// mediaInspector learns the checksum, size, content type and image
+// dimensions of an upload while it streams through to the blob store
+type mediaInspector struct {
+	hash hash.Hash
+	size int64
+	pipe *io.PipeWriter
+	done chan mediaSniff
+}
+
+// mediaSniff is what the content of an upload reveals about itself
+type mediaSniff struct {
+	contentType   string
+	width, height int
+}
+
+func newMediaInspector() *mediaInspector {
+	pipeReader, pipeWriter := io.Pipe()
+	inspector := &mediaInspector{hash: sha256.New(), pipe: pipeWriter, done: make(chan mediaSniff, 1)}
+	go func() {
+		var sniff mediaSniff
+		content := bufio.NewReaderSize(pipeReader, sniffLength)
+		header, _ := content.Peek(sniffLength)
+		sniff.contentType = http.DetectContentType(header)
+		if config, _, err := image.DecodeConfig(content); err == nil {
+			sniff.width, sniff.height = config.Width, config.Height
+		}
+		// Keep consuming so writes never block
+		io.Copy(io.Discard, content)
+		inspector.done <- sniff
+	}()
+	return inspector
+}
+
+// Write implements io.Writer, failing once the upload exceeds maxMediaSize
+func (m *mediaInspector) Write(p []byte) (int, error) {
+	m.size += int64(len(p))
+	if m.size > maxMediaSize {
+		return 0, fmt.Errorf("%w: media cannot be larger than %d bytes", domain.ErrInvalidArgument, maxMediaSize)
+	}
+	m.hash.Write(p)
+	m.pipe.Write(p)
+	return len(p), nil
+}
+
+// finish waits until the content seen so far has been inspected
+func (m *mediaInspector) finish() mediaSniff {
+	m.pipe.Close()
+	return <-m.done
+}
+
+// normalizeContentType validates a declared content type and strips its parameters
+func normalizeContentType(contentType string) (string, error) {
+	if strings.TrimSpace(contentType) == "" {
+		return "", nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid content_type %q", domain.ErrInvalidArgument, contentType)
+	}
+	return mediaType, nil
+}
+
+// mediaStorageKey returns the blob key of media, keeping a plain extension
+// of the uploaded file name so the file is served with a fitting type
+func mediaStorageKey(media *domain.ProductMedia) string {
+	key := fmt.Sprintf("products/%s/%s", media.ProductID, media.ID)
+	if ext := strings.ToLower(filepath.Ext(media.FileName)); mediaExtension.MatchString(ext) {
+		key += ext
+	}
+	return key
+}
+
+// UploadProductMedia stores the content of a new image or attachment of a
+// product and appends it to the product's media. The content type is
+// detected from the content unless media.ContentType is set.
+func (s *productService) UploadProductMedia(ctx context.Context, media *domain.ProductMedia, content io.Reader) (*domain.ProductMedia, error) {
+	if s.MediaStore == nil {
+		return nil, fmt.Errorf("%w: media storage is not configured", domain.ErrFailedPrecondition)
+	}
+	if media.ProductID == uuid.Nil {
+		return nil, fmt.Errorf("%w: product_id is required", domain.ErrInvalidArgument)
+	}
+	contentType, err := normalizeContentType(media.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ProductRepo.GetByID(media.ProductID); err != nil {
+		return nil, err
+	}
+
+	media.ID = uuid.New()
+	media.FileName = filepath.Base(strings.TrimSpace(media.FileName))
+	if media.FileName == "." || media.FileName == "/" {
+		media.FileName = ""
+	}
+	media.StorageKey = mediaStorageKey(media)
+
+	inspector := newMediaInspector()
+	url, err := s.MediaStore.Put(ctx, media.StorageKey, io.TeeReader(content, inspector))
+	sniff := inspector.finish()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store media: %w", err)
+	}
+	if inspector.size == 0 {
+		s.deleteBlob(media.StorageKey)
+		return nil, fmt.Errorf("%w: media content is empty", domain.ErrInvalidArgument)
+	}
+
+	if contentType == "" {
+		contentType, _, _ = mime.ParseMediaType(sniff.contentType)
+	}
+	if decodableImageTypes[contentType] && sniff.width == 0 {
+		s.deleteBlob(media.StorageKey)
+		return nil, fmt.Errorf("%w: content is not a valid %s image", domain.ErrInvalidArgument, contentType)
+	}
+	media.URL = url
+	media.ContentType = contentType
+	media.Size = inspector.size
+	media.Checksum = hex.EncodeToString(inspector.hash.Sum(nil))
+	media.Width, media.Height = int32(sniff.width), int32(sniff.height)
+
+	if err := s.ProductRepo.CreateMedia(ctx, media); err != nil {
+		s.deleteBlob(media.StorageKey)
+		return nil, err
+	}
+	return media, nil
+}
+
+// ListProductMedia lists the media of a product in display order
+func (s *productService) ListProductMedia(ctx context.Context, productID uuid.UUID) ([]domain.ProductMedia, error) {
+	return s.ProductRepo.ListMedia(ctx, productID)
+}
+
+// ReorderProductMedia puts the media of a product in the given order
+func (s *productService) ReorderProductMedia(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) ([]domain.ProductMedia, error) {
+	return s.ProductRepo.ReorderMedia(ctx, productID, mediaIDs)
+}
+
+// DeleteProductMedia removes media at the given version together with its content
+func (s *productService) DeleteProductMedia(ctx context.Context, id uuid.UUID, version int64) error {
+	media, err := s.ProductRepo.GetMedia(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.ProductRepo.DeleteMedia(ctx, id, version); err != nil {
+		return err
+	}
+	s.deleteBlob(media.StorageKey)
+	return nil
+}
+
+// deleteBlob removes content that is no longer referenced. Failures only
+// leave an orphaned blob behind, so they are logged rather than returned.
+func (s *productService) deleteBlob(key string) {
+	if s.MediaStore == nil {
+		return
+	}
+	if err := s.MediaStore.Delete(context.Background(), key); err != nil {
+		log.Printf("Failed to delete media blob %s: %v", key, err)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxRegionLength bounds storefront region codes such as "EU" or "US-CA"
+const maxRegionLength = 16
+
+// normalizeRegion upper-cases a region code and checks it only holds letters,
+// digits and hyphens. The empty string is the default region.
+func normalizeRegion(region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if len(region) > maxRegionLength {
+		return "", fmt.Errorf("%w: region %q is longer than %d characters", domain.ErrInvalidArgument, region, maxRegionLength)
+	}
+	for _, r := range region {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return "", fmt.Errorf("%w: invalid region %q", domain.ErrInvalidArgument, region)
+		}
+	}
+	return region, nil
+}
+
+// validatePriceListEntry normalises the region of an entry and checks its
+// price and effective period
+func validatePriceListEntry(entry *domain.PriceListEntry) error {
+	if entry.ProductID == uuid.Nil {
+		return fmt.Errorf("%w: product_id is required", domain.ErrInvalidArgument)
+	}
+	region, err := normalizeRegion(entry.Region)
+	if err != nil {
+		return err
+	}
+	entry.Region = region
+	if err := entry.Price.Validate(); err != nil {
+		return fmt.Errorf("price: %w", err)
+	}
+	if entry.Price.Amount.Sign() < 0 {
+		return fmt.Errorf("%w: price cannot be negative", domain.ErrInvalidArgument)
+	}
+	if entry.EffectiveFrom != nil && entry.EffectiveTo != nil && !entry.EffectiveFrom.Before(*entry.EffectiveTo) {
+		return fmt.Errorf("%w: effective_from must be before effective_to", domain.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// CreatePriceListEntry adds a regional price for a product or one of its plans
+func (s *productService) CreatePriceListEntry(ctx context.Context, entry *domain.PriceListEntry) (*domain.PriceListEntry, error) {
+	if err := validatePriceListEntry(entry); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.CreatePriceListEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ListPriceListEntries lists the price list of a product, or of one of its
+// plans if planID is set
+func (s *productService) ListPriceListEntries(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.PriceListEntry, error) {
+	return s.ProductRepo.ListPriceListEntries(ctx, productID, planID)
+}
+
+// DeletePriceListEntry removes a price list entry at the given version
+func (s *productService) DeletePriceListEntry(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.ProductRepo.DeletePriceListEntry(ctx, id, version)
+}
+
+// GetPrice resolves the price to charge for a product or plan. An entry for
+// the requested region wins over one for the default region, which wins over
+// the base price stored on the product or plan. The currency defaults to the
+// currency of the base price and the time to now.
+func (s *productService) GetPrice(ctx context.Context, query domain.PriceQuery) (*domain.ResolvedPrice, error) {
+	region, err := normalizeRegion(query.Region)
+	if err != nil {
+		return nil, err
+	}
+	query.Region = region
+	if query.At.IsZero() {
+		query.At = time.Now()
+	}
+
+	product, err := s.ProductRepo.GetByID(query.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	base := product.Price
+	if query.SubscriptionPlanID != nil {
+		plan, err := s.ProductRepo.GetSubscriptionPlan(ctx, query.ProductID, *query.SubscriptionPlanID)
+		if err != nil {
+			return nil, err
+		}
+		base = plan.Price
+	}
+
+	if query.Currency == "" {
+		query.Currency = base.Currency
+	} else if !domain.IsCurrencyCode(query.Currency) {
+		return nil, fmt.Errorf("%w: unknown ISO 4217 currency code %q", domain.ErrInvalidArgument, query.Currency)
+	}
+
+	entries, err := s.ProductRepo.FindEffectivePrices(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var fallback *domain.PriceListEntry
+	for i := range entries {
+		entry := &entries[i]
+		if !entry.EffectiveAt(query.At) || entry.Price.Currency != query.Currency {
+			continue
+		}
+		switch entry.Region {
+		case query.Region:
+			source := domain.PriceSourceRegion
+			if entry.Region == domain.DefaultRegion {
+				source = domain.PriceSourceDefaultRegion
+			}
+			return &domain.ResolvedPrice{Price: entry.Price, Source: source, Entry: entry}, nil
+		case domain.DefaultRegion:
+			fallback = entry
+		}
+	}
+	if fallback != nil {
+		return &domain.ResolvedPrice{Price: fallback.Price, Source: domain.PriceSourceDefaultRegion, Entry: fallback}, nil
+	}
+
+	if base.Currency == query.Currency {
+		return &domain.ResolvedPrice{Price: base, Source: domain.PriceSourceBasePrice}, nil
+	}
+	return nil, fmt.Errorf("%w: no %s price for product %s in region %q", domain.ErrNotFound, query.Currency, query.ProductID, query.Region)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"product-microservice/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// priceSchedulerInterval is how often the price scheduler looks for due changes
+	priceSchedulerInterval = 30 * time.Second
+	// priceSchedulerBatchSize is the number of due changes applied per transaction
+	priceSchedulerBatchSize = 100
+)
+
+// ListPriceHistory returns a page of the price history of a product and its
+// plans, or of one plan if planID is set, newest first
+func (s *productService) ListPriceHistory(ctx context.Context, productID uuid.UUID, planID *uuid.UUID, pageSize int32, pageToken string) ([]domain.PriceChange, string, error) {
+	limit, err := normalizePageSize(pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+	opts := domain.PriceHistoryOptions{
+		ProductID:          productID,
+		SubscriptionPlanID: planID,
+		// Fetch one extra row to learn whether another page follows
+		Limit: limit + 1,
+	}
+	if pageToken != "" {
+		if opts.BeforeID, err = decodeHistoryToken(pageToken); err != nil {
+			return nil, "", err
+		}
+	}
+
+	changes, err := s.ProductRepo.ListPriceHistory(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	nextPageToken := ""
+	if len(changes) > limit {
+		changes = changes[:limit]
+		nextPageToken = encodeHistoryToken(changes[limit-1].ID)
+	}
+	return changes, nextPageToken, nil
+}
+
+// SchedulePriceChange schedules a new price for a product or one of its plans
+func (s *productService) SchedulePriceChange(ctx context.Context, change *domain.ScheduledPriceChange) (*domain.ScheduledPriceChange, error) {
+	if change.ProductID == uuid.Nil {
+		return nil, fmt.Errorf("%w: product_id is required", domain.ErrInvalidArgument)
+	}
+	if change.SubscriptionPlanID != nil {
+		if err := validatePlanPrice(change.Price); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := change.Price.Validate(); err != nil {
+			return nil, fmt.Errorf("price: %w", err)
+		}
+		if change.Price.Amount.Sign() < 0 {
+			return nil, fmt.Errorf("%w: price cannot be negative", domain.ErrInvalidArgument)
+		}
+	}
+	if !change.EffectiveAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: effective_at must be in the future", domain.ErrInvalidArgument)
+	}
+
+	if err := s.ProductRepo.SchedulePriceChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// ListScheduledPriceChanges lists the scheduled price changes of a product,
+// or of one of its plans if planID is set
+func (s *productService) ListScheduledPriceChanges(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.ScheduledPriceChange, error) {
+	return s.ProductRepo.ListScheduledPriceChanges(ctx, productID, planID)
+}
+
+// CancelScheduledPriceChange cancels a pending price change at the given version
+func (s *productService) CancelScheduledPriceChange(ctx context.Context, id uuid.UUID, version int64) (*domain.ScheduledPriceChange, error) {
+	return s.ProductRepo.CancelScheduledPriceChange(ctx, id, version)
+}
+
+// ApplyDuePriceChanges applies every scheduled price change that has taken
+// effect by now and returns how many were processed
+func (s *productService) ApplyDuePriceChanges(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		n, err := s.ProductRepo.ApplyDuePriceChanges(ctx, now, priceSchedulerBatchSize)
+		total += n
+		if err != nil || n < priceSchedulerBatchSize {
+			return total, err
+		}
+	}
+}
+
+// RunPriceScheduler applies due price changes every priceSchedulerInterval
+// until ctx is done. Errors are logged and retried on the next tick.
+func (s *productService) RunPriceScheduler(ctx context.Context) {
+	ticker := time.NewTicker(priceSchedulerInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := s.ApplyDuePriceChanges(ctx, time.Now()); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to apply scheduled price changes: %v", err)
+		} else if n > 0 {
+			log.Printf("Applied %d scheduled price changes", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -4,25 +4,93 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"product-microservice/internal/blobstore"
 	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
 	"product-microservice/internal/repository"
+	"product-microservice/internal/shipping"
 	pb "product-microservice/proto/product"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type ProductService interface {
-	CreateProduct(product *domain.Product) (*domain.Product, error)
+	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	GetProductByID(id uuid.UUID) (*domain.Product, error)
-	UpdateProduct(id uuid.UUID, updatedProduct *domain.Product) (*domain.Product, error)
-	DeleteProduct(id uuid.UUID) error
+	UpdateProduct(ctx context.Context, id uuid.UUID, updatedProduct *domain.Product, updateMask []string) (*domain.Product, error)
+	DeleteProduct(id uuid.UUID, version int64) error
+	RestoreProduct(id uuid.UUID, version int64) (*domain.Product, error)
+	PurgeProduct(ctx context.Context, id uuid.UUID) error
+	ImportProduct(ctx context.Context, product *domain.Product) (bool, *domain.Product, error)
 	ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error)
+	SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error)
+	ExportProducts(ctx context.Context, req *pb.ExportProductsRequest, fn func(*pb.ExportedProduct) error) error
+	WatchProducts(ctx context.Context, req *pb.WatchProductsRequest, fn func(*pb.ProductEvent) error) error
+	BatchCreateProducts(ctx context.Context, products []*domain.Product, allOrNothing bool) ([]BatchResult, error)
+	BatchGetProducts(ctx context.Context, ids []uuid.UUID, allOrNothing bool) ([]BatchResult, error)
+	BatchUpdateProducts(ctx context.Context, updates []ProductUpdate, allOrNothing bool) ([]BatchResult, error)
+	BatchDeleteProducts(ctx context.Context, deletions []ProductDeletion, allOrNothing bool) ([]BatchResult, error)
 	FindProductById(ctx context.Context, id string) (*domain.Product, error)
+	CreatePriceListEntry(ctx context.Context, entry *domain.PriceListEntry) (*domain.PriceListEntry, error)
+	ListPriceListEntries(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.PriceListEntry, error)
+	DeletePriceListEntry(ctx context.Context, id uuid.UUID, version int64) error
+	GetPrice(ctx context.Context, query domain.PriceQuery) (*domain.ResolvedPrice, error)
+	ListPriceHistory(ctx context.Context, productID uuid.UUID, planID *uuid.UUID, pageSize int32, pageToken string) ([]domain.PriceChange, string, error)
+	SchedulePriceChange(ctx context.Context, change *domain.ScheduledPriceChange) (*domain.ScheduledPriceChange, error)
+	ListScheduledPriceChanges(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.ScheduledPriceChange, error)
+	CancelScheduledPriceChange(ctx context.Context, id uuid.UUID, version int64) (*domain.ScheduledPriceChange, error)
+	ApplyDuePriceChanges(ctx context.Context, now time.Time) (int, error)
+	CreateProductVariant(ctx context.Context, variant *domain.ProductVariant) (*domain.ProductVariant, error)
+	GetProductVariant(ctx context.Context, id uuid.UUID) (*domain.ProductVariant, error)
+	GetProductVariantBySKU(ctx context.Context, sku string) (*domain.ProductVariant, error)
+	ListProductVariants(ctx context.Context, productID uuid.UUID) ([]domain.ProductVariant, error)
+	UpdateProductVariant(ctx context.Context, id uuid.UUID, update *domain.ProductVariant, updateMask []string) (*domain.ProductVariant, error)
+	DeleteProductVariant(ctx context.Context, id uuid.UUID, version int64) error
+	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
+	GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	ListCategories(ctx context.Context, opts domain.CategoryListOptions) ([]domain.Category, error)
+	UpdateCategory(ctx context.Context, id uuid.UUID, update *domain.Category, updateMask []string) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID, version int64) error
+	CreateAttributeDefinition(ctx context.Context, definition *domain.AttributeDefinition) (*domain.AttributeDefinition, error)
+	GetAttributeDefinition(ctx context.Context, id uuid.UUID) (*domain.AttributeDefinition, error)
+	ListAttributeDefinitions(ctx context.Context, opts domain.AttributeDefinitionListOptions) ([]domain.AttributeDefinition, error)
+	UpdateAttributeDefinition(ctx context.Context, id uuid.UUID, update *domain.AttributeDefinition, updateMask []string) (*domain.AttributeDefinition, error)
+	DeleteAttributeDefinition(ctx context.Context, id uuid.UUID, version int64) error
+	UploadProductMedia(ctx context.Context, media *domain.ProductMedia, content io.Reader) (*domain.ProductMedia, error)
+	ListProductMedia(ctx context.Context, productID uuid.UUID) ([]domain.ProductMedia, error)
+	ReorderProductMedia(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) ([]domain.ProductMedia, error)
+	DeleteProductMedia(ctx context.Context, id uuid.UUID, version int64) error
+	UpsertProductTranslations(ctx context.Context, productID uuid.UUID, translations []domain.ProductTranslation) ([]domain.ProductTranslation, error)
+	ListProductTranslations(ctx context.Context, productID uuid.UUID) ([]domain.ProductTranslation, error)
+	DeleteProductTranslation(ctx context.Context, productID uuid.UUID, locale string) error
+	LocalizeProducts(ctx context.Context, locale string, products ...*domain.Product) error
+	PublishProduct(ctx context.Context, id uuid.UUID, version int64, publishAt, unpublishAt *time.Time) (*domain.Product, error)
+	ArchiveProduct(ctx context.Context, id uuid.UUID, version int64) (*domain.Product, error)
+	ApplyDuePublications(ctx context.Context, now time.Time) (int64, error)
+	AdjustStock(ctx context.Context, item domain.StockItem, delta int64, reason string) (*domain.StockLevel, error)
+	GetStock(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID) ([]domain.StockLevel, error)
+	ReserveStock(ctx context.Context, reservation *domain.StockReservation, ttl time.Duration) (*domain.StockReservation, error)
+	ReleaseReservation(ctx context.Context, id uuid.UUID) (*domain.StockReservation, error)
+	CommitReservation(ctx context.Context, id uuid.UUID) (*domain.StockReservation, error)
+	ListStockMovements(ctx context.Context, productID uuid.UUID, variantID, warehouseID *uuid.UUID, pageSize int32, pageToken string) ([]domain.StockMovement, string, error)
+	ExpireReservations(ctx context.Context, now time.Time) (int, error)
+	CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error)
+	GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	ListWarehouses(ctx context.Context, opts domain.WarehouseListOptions) ([]domain.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, id uuid.UUID, update *domain.Warehouse, updateMask []string) (*domain.Warehouse, error)
+	DeleteWarehouse(ctx context.Context, id uuid.UUID, version int64) error
+	EstimateShipping(ctx context.Context, lines []ShippingLine, destinationCountry string, warehouseID *uuid.UUID) (*shipping.Estimate, error)
 }
 
 type productService struct {
 	ProductRepo repository.ProductRepository
+	// MediaStore keeps the content of product media; uploads fail while it is nil
+	MediaStore blobstore.Store
+	// ShippingRates prices shipments; estimates fail while it is nil
+	ShippingRates *shipping.RateTable
 }
 
 func NewProductService(productRepo repository.ProductRepository) *productService {
@@ -31,7 +99,20 @@ func NewProductService(productRepo repository.ProductRepository) *productService
 	}
 }
 
-func (s *productService) CreateProduct(product *domain.Product) (*domain.Product, error) {
+func (s *productService) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	// Every product starts as a draft and is published through review
+	product.Status = domain.ProductStatusDraft
+	product.PublishAt, product.UnpublishAt, product.PublishedAt = nil, nil, nil
+	if err := s.prepareBundle(ctx, product); err != nil {
+		return nil, err
+	}
+	if err := validateProduct(product); err != nil {
+		return nil, err
+	}
+	if err := s.validateAttributes(ctx, product, true); err != nil {
+		return nil, err
+	}
+
 	err := s.ProductRepo.Create(product)
 	if err != nil {
 		return nil, err
@@ -44,87 +125,309 @@ func (s *productService) GetProductByID(id uuid.UUID) (*domain.Product, error) {
 	product, err := s.ProductRepo.GetByID(id)
 	if err != nil {
 		// Return an error if the product is not found or another error occurs
-		return nil, fmt.Errorf("error fetching product with ID %s: %v", id, err)
+		return nil, fmt.Errorf("error fetching product with ID %s: %w", id, err)
 	}
 	return product, nil
 }
 
-func (s *productService) UpdateProduct(id uuid.UUID, updatedProduct *domain.Product) (*domain.Product, error) {
+func (s *productService) UpdateProduct(ctx context.Context, id uuid.UUID, updatedProduct *domain.Product, updateMask []string) (*domain.Product, error) {
 	// Get the current product details by ID
 	product, err := s.ProductRepo.GetByID(id)
 	if err != nil {
-		return nil, fmt.Errorf("product not found: %v", err)
+		return nil, fmt.Errorf("product not found: %w", err)
 	}
 
-	// Update product fields
-	product.Name = updatedProduct.Name
-	product.Description = updatedProduct.Description
-	product.Price = updatedProduct.Price
+	// Reject stale writes before touching anything; the repository re-checks
+	// the version atomically to catch concurrent updates
+	if updatedProduct.Version != product.Version {
+		return nil, fmt.Errorf("product with ID %s has changed since it was read: %w", id, domain.ErrVersionConflict)
+	}
+
+	// Update only the fields named in the mask
+	before := *product
+	if err := applyUpdateMask(product, updatedProduct, updateMask); err != nil {
+		return nil, err
+	}
+	if err := s.prepareBundle(ctx, product); err != nil {
+		return nil, err
+	}
+	if err := validateProduct(product); err != nil {
+		return nil, err
+	}
+	if err := s.checkVariantsAfterProductChange(ctx, &before, product); err != nil {
+		return nil, err
+	}
+	if err := s.validateAttributes(ctx, product, updatesAttributes(updateMask)); err != nil {
+		return nil, err
+	}
 
 	// Update product in the database
 	err = s.ProductRepo.Update(product)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update product: %v", err)
+		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
 
 	return product, nil
 }
 
 
-func (s *productService) DeleteProduct(id uuid.UUID) error {
-	return s.ProductRepo.Delete(id)
+func (s *productService) DeleteProduct(id uuid.UUID, version int64) error {
+	return s.ProductRepo.Delete(id, version)
+}
+
+func (s *productService) RestoreProduct(id uuid.UUID, version int64) (*domain.Product, error) {
+	if err := s.ProductRepo.Restore(id, version); err != nil {
+		return nil, err
+	}
+	return s.ProductRepo.GetByID(id)
+}
+
+// PurgeProduct permanently removes a product and then the content of its media
+func (s *productService) PurgeProduct(ctx context.Context, id uuid.UUID) error {
+	var media []domain.ProductMedia
+	if s.MediaStore != nil {
+		var err error
+		if media, err = s.ProductRepo.ListMedia(ctx, id); err != nil {
+			return err
+		}
+	}
+	if err := s.ProductRepo.Purge(id); err != nil {
+		return err
+	}
+	for i := range media {
+		s.deleteBlob(media[i].StorageKey)
+	}
+	return nil
+}
+
+// ImportProduct upserts a product keyed by its external SKU. It reports
+// whether a new product was created.
+func (s *productService) ImportProduct(ctx context.Context, product *domain.Product) (bool, *domain.Product, error) {
+	if product.ExternalSKU == nil {
+		return false, nil, fmt.Errorf("%w: external_sku is required for imports", domain.ErrInvalidArgument)
+	}
+
+	// CreateProduct and UpdateProduct validate the row and price bundles
+	existing, err := s.ProductRepo.FindByExternalSKU(*product.ExternalSKU)
+	if errors.Is(err, domain.ErrNotFound) {
+		created, err := s.CreateProduct(ctx, product)
+		return true, created, err
+	}
+	if err != nil {
+		return false, nil, err
+	}
+
+	// Imports are authoritative, so they overwrite whatever version is stored
+	product.Version = existing.Version
+	updated, err := s.UpdateProduct(ctx, existing.ID, product, importUpdatePaths(product))
+	return false, updated, err
 }
 
 func (s *productService) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
-	var products []domain.Product
-	var err error
+	orderBy, descending, err := parseOrderBy(req.GetOrderBy())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetType() != "" {
+		if err := domain.ValidateProductType(req.GetType()); err != nil {
+			return nil, err
+		}
+	}
 
-	// Determine which product type to fetch
-	switch req.Type {
-	case "digital":
-		products, err = s.ProductRepo.GetDigitalProducts(ctx)
-	case "physical":
-		products, err = s.ProductRepo.GetPhysicalProducts(ctx)
-	case "subscription":
-		products, err = s.ProductRepo.GetSubscriptionProducts(ctx)
-	default:
-		products, err = s.ProductRepo.GetAllProducts(ctx)
+	pageSize, err := normalizePageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
 	}
 
+	locales, err := domain.LocaleFallbacks(req.GetLocale())
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert products to the protobuf response format
-	var pbProducts []*pb.Product
-	for _, product := range products {
-		pbProduct := &pb.Product{
-			Id:          product.ID.String(),
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       float32(product.Price),
-			CreatedAt:   timestamppb.New(product.CreatedAt),
-			UpdatedAt:   timestamppb.New(product.UpdatedAt),
+	opts := domain.ProductListOptions{
+		Filter: domain.ProductFilter{
+			Type:        req.GetType(),
+			NamePrefix:  req.GetNamePrefix(),
+			ShowDeleted: req.GetShowDeleted(),
+		},
+		OrderBy:    orderBy,
+		Descending: descending,
+		// Fetch one extra row to find out whether another page exists
+		Limit: pageSize + 1,
+	}
+	if req.GetMinPrice() != nil {
+		minPrice := mapper.MoneyFromProto(req.GetMinPrice())
+		if err := minPrice.Validate(); err != nil {
+			return nil, fmt.Errorf("min_price: %w", err)
 		}
-
-		// Populate the product type-specific fields
-		if product.DigitalProduct != nil {
-			pbProduct.ProductType = &pb.Product_DigitalProduct{
-				DigitalProduct: &pb.DigitalProduct{
-					FileSize:     product.DigitalProduct.FileSize,
-					DownloadLink: product.DigitalProduct.DownloadLink,
-				},
-			}
+		opts.Filter.MinPrice = &minPrice
+	}
+	if req.GetMaxPrice() != nil {
+		maxPrice := mapper.MoneyFromProto(req.GetMaxPrice())
+		if err := maxPrice.Validate(); err != nil {
+			return nil, fmt.Errorf("max_price: %w", err)
+		}
+		opts.Filter.MaxPrice = &maxPrice
+	}
+	if opts.Filter.MinPrice != nil && opts.Filter.MaxPrice != nil &&
+		opts.Filter.MinPrice.Currency != opts.Filter.MaxPrice.Currency {
+		return nil, fmt.Errorf("%w: min_price and max_price must use the same currency", domain.ErrInvalidArgument)
+	}
+	if req.GetCreatedAfter() != nil {
+		createdAfter := req.GetCreatedAfter().AsTime()
+		opts.Filter.CreatedAfter = &createdAfter
+	}
+	if req.GetCategoryId() != "" {
+		categoryID, err := uuid.Parse(req.GetCategoryId())
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid category ID format: %v", domain.ErrInvalidArgument, err)
+		}
+		opts.Filter.CategoryID = &categoryID
+		opts.Filter.IncludeDescendants = req.GetIncludeDescendants()
+	}
+	if req.GetTag() != "" {
+		if opts.Filter.Tag, err = normalizeTag(req.GetTag()); err != nil {
+			return nil, err
 		}
-		pbProducts = append(pbProducts, pbProduct)
+	}
+	if opts.Filter.Attributes, err = s.parseAttributeFilters(ctx, req.GetAttributeFilters()); err != nil {
+		return nil, err
+	}
+	if opts.Filter.Statuses, err = parseStatuses(req.GetStatuses()); err != nil {
+		return nil, err
+	}
+	if req.GetPageToken() != "" {
+		if opts.After, err = decodePageToken(req.GetPageToken(), orderBy, descending); err != nil {
+			return nil, err
+		}
+	}
+
+	products, total, err := s.ProductRepo.ListProducts(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextPageToken string
+	if len(products) > pageSize {
+		products = products[:pageSize]
+		nextPageToken = encodePageToken(orderBy, descending, products[len(products)-1])
+	}
+
+	// Localize after the page token is taken, which holds the product's own name
+	localized := make([]*domain.Product, len(products))
+	for i := range products {
+		localized[i] = &products[i]
+	}
+	if err := s.localizeProducts(ctx, locales, localized); err != nil {
+		return nil, err
 	}
 
 	// Return the response with products
 	return &pb.ListProductsResponse{
-		Products: pbProducts,
+		Products:      mapper.ProductsToProto(products),
+		NextPageToken: nextPageToken,
+		TotalSize:     int32(total),
 	}, nil
 }
 
+func (s *productService) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, fmt.Errorf("%w: query cannot be empty", domain.ErrInvalidArgument)
+	}
+
+	pageSize, err := normalizePageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	locales, err := domain.LocaleFallbacks(req.GetLocale())
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := parseStatuses(req.GetStatuses())
+	if err != nil {
+		return nil, err
+	}
+	for _, productType := range req.GetTypes() {
+		if err := domain.ValidateProductType(productType); err != nil {
+			return nil, err
+		}
+	}
+
+	offset := 0
+	if req.GetPageToken() != "" {
+		if offset, err = decodeOffsetToken(req.GetPageToken()); err != nil {
+			return nil, err
+		}
+	}
+
+	hits, err := s.ProductRepo.SearchProducts(ctx, domain.ProductSearchOptions{
+		Query:   query,
+		Types:   req.GetTypes(),
+		Locales:  locales,
+		Statuses: statuses,
+		Limit:    pageSize + 1,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var nextPageToken string
+	if len(hits) > pageSize {
+		hits = hits[:pageSize]
+		nextPageToken = encodeOffsetToken(offset + pageSize)
+	}
+
+	localized := make([]*domain.Product, len(hits))
+	for i := range hits {
+		localized[i] = &hits[i].Product
+	}
+	if err := s.localizeProducts(ctx, locales, localized); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pb.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, &pb.SearchResult{
+			Product:            mapper.ProductToProto(&hit.Product),
+			Rank:               float32(hit.Rank),
+			NameHighlight:      hit.NameHighlight,
+			DescriptionSnippet: hit.DescriptionSnippet,
+		})
+	}
+
+	return &pb.SearchProductsResponse{
+		Results:       results,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// ExportProducts streams every product matching the request to fn, in ID
+// order, with its type details and subscription plans
+func (s *productService) ExportProducts(ctx context.Context, req *pb.ExportProductsRequest, fn func(*pb.ExportedProduct) error) error {
+	if req.GetType() != "" {
+		if err := domain.ValidateProductType(req.GetType()); err != nil {
+			return err
+		}
+	}
+
+	statuses, err := parseStatuses(req.GetStatuses())
+	if err != nil {
+		return err
+	}
+	filter := domain.ProductFilter{
+		Type:        req.GetType(),
+		ShowDeleted: req.GetShowDeleted(),
+		Statuses:    statuses,
+	}
+	return s.ProductRepo.ExportProducts(ctx, filter, func(exported *domain.ExportedProduct) error {
+		return fn(mapper.ExportedProductToProto(exported))
+	})
+}
+
 func (s *productService) FindProductById(ctx context.Context, id string) (*domain.Product, error) {
 	if id == "" {
 		return nil, errors.New("product name cannot be empty")
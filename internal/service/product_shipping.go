@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/shipping"
+
+	"github.com/google/uuid"
+)
+
+// ShippingLine is a quantity of a product, or of one of its variants, to ship
+type ShippingLine struct {
+	ProductID uuid.UUID
+	VariantID *uuid.UUID
+	Quantity  int64
+}
+
+// shippingItems returns what has to be shipped for a line: the product itself
+// if it is physical, the physical components of a bundle, and nothing for
+// digital and subscription products
+func (s *productService) shippingItems(ctx context.Context, line ShippingLine) ([]shipping.Item, error) {
+	product, err := s.ProductRepo.GetByID(line.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if bundle := product.BundleProduct; bundle != nil {
+		var items []shipping.Item
+		for _, component := range bundle.Components {
+			componentItems, err := s.shippingItems(ctx, ShippingLine{
+				ProductID: component.ProductID,
+				VariantID: component.VariantID,
+				Quantity:  line.Quantity * int64(component.Quantity),
+			})
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, componentItems...)
+		}
+		return items, nil
+	}
+	if product.PhysicalProduct == nil {
+		return nil, nil
+	}
+
+	weight := product.PhysicalProduct.Weight
+	if line.VariantID != nil {
+		variant, err := s.ProductRepo.GetVariant(ctx, *line.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ProductID != product.ID {
+			return nil, fmt.Errorf("%w: variant %s does not belong to product %s", domain.ErrInvalidArgument, variant.ID, product.ID)
+		}
+		if override := variant.WeightOverride(); override != nil {
+			weight = *override
+		}
+	}
+	if weight.Unit == "" {
+		return nil, fmt.Errorf("product %s has no weight to estimate shipping with: %w", product.ID, domain.ErrFailedPrecondition)
+	}
+	return []shipping.Item{{
+		Weight:     weight,
+		Dimensions: product.PhysicalProduct.Dimensions,
+		Quantity:   line.Quantity,
+	}}, nil
+}
+
+// EstimateShipping quotes every carrier for shipping the lines to a
+// destination country from a warehouse, or from the active warehouse first
+// in allocation order if warehouseID is nil
+func (s *productService) EstimateShipping(ctx context.Context, lines []ShippingLine, destinationCountry string, warehouseID *uuid.UUID) (*shipping.Estimate, error) {
+	if s.ShippingRates == nil {
+		return nil, fmt.Errorf("%w: shipping rates are not configured", domain.ErrFailedPrecondition)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", domain.ErrInvalidArgument)
+	}
+	destination, err := normalizeCountryCode(destinationCountry)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := ""
+	if warehouseID != nil {
+		warehouse, err := s.ProductRepo.GetWarehouse(ctx, *warehouseID)
+		if err != nil {
+			return nil, err
+		}
+		origin = warehouse.Address.CountryCode
+	} else {
+		warehouses, err := s.ProductRepo.ListWarehouses(ctx, domain.WarehouseListOptions{ActiveOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		if len(warehouses) > 0 {
+			origin = warehouses[0].Address.CountryCode
+		}
+	}
+
+	var items []shipping.Item
+	for _, line := range lines {
+		if line.Quantity < 1 {
+			return nil, fmt.Errorf("%w: quantity must be at least 1", domain.ErrInvalidArgument)
+		}
+		lineItems, err := s.shippingItems(ctx, line)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, lineItems...)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: none of the items are physical products that ship", domain.ErrInvalidArgument)
+	}
+	return s.ShippingRates.Estimate(origin, destination, items)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// UpsertProductTranslations stores the name and description of a product in
+// each given locale, replacing earlier translations into those locales, and
+// returns every translation of the product
+func (s *productService) UpsertProductTranslations(ctx context.Context, productID uuid.UUID, translations []domain.ProductTranslation) ([]domain.ProductTranslation, error) {
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("%w: product_id is required", domain.ErrInvalidArgument)
+	}
+	if len(translations) == 0 {
+		return nil, fmt.Errorf("%w: at least one translation is required", domain.ErrInvalidArgument)
+	}
+	seen := make(map[string]bool, len(translations))
+	for i := range translations {
+		translation := &translations[i]
+		locale, err := domain.CanonicalLocale(translation.Locale)
+		if err != nil {
+			return nil, err
+		}
+		if seen[locale] {
+			return nil, fmt.Errorf("%w: more than one translation into %q", domain.ErrInvalidArgument, locale)
+		}
+		seen[locale] = true
+		translation.Locale = locale
+		translation.Name = strings.TrimSpace(translation.Name)
+		if translation.Name == "" {
+			return nil, fmt.Errorf("%w: the %s translation needs a name", domain.ErrInvalidArgument, locale)
+		}
+		translation.Description = strings.TrimSpace(translation.Description)
+	}
+
+	if err := s.ProductRepo.UpsertTranslations(ctx, productID, translations); err != nil {
+		return nil, err
+	}
+	return s.ProductRepo.ListTranslations(ctx, productID)
+}
+
+// ListProductTranslations lists the translations of a product ordered by locale
+func (s *productService) ListProductTranslations(ctx context.Context, productID uuid.UUID) ([]domain.ProductTranslation, error) {
+	return s.ProductRepo.ListTranslations(ctx, productID)
+}
+
+// DeleteProductTranslation removes the translation of a product into a locale
+func (s *productService) DeleteProductTranslation(ctx context.Context, productID uuid.UUID, locale string) error {
+	locale, err := domain.CanonicalLocale(locale)
+	if err != nil {
+		return err
+	}
+	return s.ProductRepo.DeleteTranslation(ctx, productID, locale)
+}
+
+// LocalizeProducts replaces the names and descriptions of products with their
+// translations for a locale or Accept-Language style priority list
+func (s *productService) LocalizeProducts(ctx context.Context, locale string, products ...*domain.Product) error {
+	locales, err := domain.LocaleFallbacks(locale)
+	if err != nil {
+		return err
+	}
+	return s.localizeProducts(ctx, locales, products)
+}
+
+// localizeProducts takes the name of each product from its translation into
+// the first of locales it has one for, and the description from the first
+// of those translations with a description. Products keep their own text
+// where no translation applies.
+func (s *productService) localizeProducts(ctx context.Context, locales []string, products []*domain.Product) error {
+	if len(locales) == 0 || len(products) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	translations, err := s.ProductRepo.FindTranslations(ctx, ids, locales)
+	if err != nil {
+		return err
+	}
+
+	type key struct {
+		productID uuid.UUID
+		locale    string
+	}
+	byKey := make(map[key]*domain.ProductTranslation, len(translations))
+	for i := range translations {
+		byKey[key{translations[i].ProductID, translations[i].Locale}] = &translations[i]
+	}
+	for _, product := range products {
+		nameDone, descriptionDone := false, false
+		for _, locale := range locales {
+			translation, ok := byKey[key{product.ID, locale}]
+			if !ok {
+				continue
+			}
+			if !nameDone {
+				product.Name, product.Locale = translation.Name, locale
+				nameDone = true
+			}
+			if !descriptionDone && translation.Description != "" {
+				product.Description = translation.Description
+				descriptionDone = true
+			}
+		}
+	}
+	return nil
+}
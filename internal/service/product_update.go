@@ -0,0 +1,295 @@
+package service
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+)
+
+// productFieldSetter copies one field mask path from the update onto the stored product
+type productFieldSetter func(dst, src *domain.Product) error
+
+// productFieldSetters lists every path UpdateProduct accepts in its update mask.
+// Paths use the protobuf field names so clients can build masks directly from
+// the Product message.
+var productFieldSetters = map[string]productFieldSetter{
+	"name":        func(dst, src *domain.Product) error { dst.Name = src.Name; return nil },
+	"description": func(dst, src *domain.Product) error { dst.Description = src.Description; return nil },
+	"price":       func(dst, src *domain.Product) error { dst.Price = src.Price; return nil },
+	"external_sku": func(dst, src *domain.Product) error {
+		dst.ExternalSKU = src.ExternalSKU
+		return nil
+	},
+	"option_names": func(dst, src *domain.Product) error {
+		dst.OptionNames = src.OptionNames
+		return nil
+	},
+	"tags": func(dst, src *domain.Product) error {
+		dst.Tags = src.Tags
+		return nil
+	},
+	"category_ids": func(dst, src *domain.Product) error {
+		dst.Categories = src.Categories
+		return nil
+	},
+	"attributes": func(dst, src *domain.Product) error {
+		dst.Attributes = src.Attributes
+		return nil
+	},
+	"status": func(dst, src *domain.Product) error {
+		if src.Status == dst.Status {
+			return nil
+		}
+		// Publishing and archiving have their own methods
+		if src.Status != domain.ProductStatusDraft && src.Status != domain.ProductStatusInReview {
+			return fmt.Errorf("%w: status can only be set to draft or in review, use PublishProduct or ArchiveProduct", domain.ErrInvalidArgument)
+		}
+		if !dst.Status.CanTransitionTo(src.Status) {
+			return fmt.Errorf("%w: a %s product cannot move to %s", domain.ErrFailedPrecondition, dst.Status, src.Status)
+		}
+		dst.Status = src.Status
+		// A scheduled publication only applies while the product is in review
+		dst.PublishAt, dst.UnpublishAt = nil, nil
+		return nil
+	},
+
+	"digital_product": func(dst, src *domain.Product) error {
+		details, err := digitalDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.DigitalProduct.FileSize = details.FileSize
+		dst.DigitalProduct.DownloadLink = details.DownloadLink
+		return nil
+	},
+	"digital_product.file_size": func(dst, src *domain.Product) error {
+		details, err := digitalDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.DigitalProduct.FileSize = details.FileSize
+		return nil
+	},
+	"digital_product.download_link": func(dst, src *domain.Product) error {
+		details, err := digitalDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.DigitalProduct.DownloadLink = details.DownloadLink
+		return nil
+	},
+
+	"physical_product": func(dst, src *domain.Product) error {
+		details, err := physicalDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.PhysicalProduct.Weight = details.Weight
+		dst.PhysicalProduct.Dimensions = details.Dimensions
+		return nil
+	},
+	"physical_product.weight": func(dst, src *domain.Product) error {
+		details, err := physicalDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.PhysicalProduct.Weight = details.Weight
+		return nil
+	},
+	"physical_product.dimensions": func(dst, src *domain.Product) error {
+		details, err := physicalDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.PhysicalProduct.Dimensions = details.Dimensions
+		return nil
+	},
+
+	"subscription_product": func(dst, src *domain.Product) error {
+		details, err := subscriptionDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.SubscriptionProduct.SubscriptionPeriod = details.SubscriptionPeriod
+		dst.SubscriptionProduct.RenewalPrice = details.RenewalPrice
+		return nil
+	},
+	"subscription_product.subscription_period": func(dst, src *domain.Product) error {
+		details, err := subscriptionDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.SubscriptionProduct.SubscriptionPeriod = details.SubscriptionPeriod
+		return nil
+	},
+	"subscription_product.renewal_price": func(dst, src *domain.Product) error {
+		details, err := subscriptionDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.SubscriptionProduct.RenewalPrice = details.RenewalPrice
+		return nil
+	},
+
+	"bundle_product": func(dst, src *domain.Product) error {
+		details, err := bundleDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.BundleProduct.Pricing = details.Pricing
+		dst.BundleProduct.DiscountPercent = details.DiscountPercent
+		dst.BundleProduct.Components = details.Components
+		return nil
+	},
+	"bundle_product.components": func(dst, src *domain.Product) error {
+		details, err := bundleDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.BundleProduct.Components = details.Components
+		return nil
+	},
+	"bundle_product.pricing": func(dst, src *domain.Product) error {
+		details, err := bundleDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.BundleProduct.Pricing = details.Pricing
+		return nil
+	},
+	"bundle_product.discount_percent": func(dst, src *domain.Product) error {
+		details, err := bundleDetails(dst, src)
+		if err != nil {
+			return err
+		}
+		dst.BundleProduct.DiscountPercent = details.DiscountPercent
+		return nil
+	},
+}
+
+// defaultUpdatePaths is used when the client sends an empty update mask: the
+// top-level fields plus the details of the kind present in the update
+func defaultUpdatePaths(src *domain.Product) []string {
+	paths := []string{"name", "description", "price"}
+	switch {
+	case src.DigitalProduct != nil:
+		paths = append(paths, "digital_product")
+	case src.PhysicalProduct != nil:
+		paths = append(paths, "physical_product")
+	case src.SubscriptionProduct != nil:
+		paths = append(paths, "subscription_product")
+	case src.BundleProduct != nil:
+		paths = append(paths, "bundle_product")
+	}
+	return paths
+}
+
+// importUpdatePaths is the update mask for an import row whose SKU already
+// exists: the paths of an empty mask plus the option names, tags, categories
+// and attributes the row sets. CSV rows have no columns for those, so they
+// keep the values already stored.
+func importUpdatePaths(src *domain.Product) []string {
+	paths := defaultUpdatePaths(src)
+	if len(src.OptionNames) > 0 {
+		paths = append(paths, "option_names")
+	}
+	if len(src.Tags) > 0 {
+		paths = append(paths, "tags")
+	}
+	if len(src.Categories) > 0 {
+		paths = append(paths, "category_ids")
+	}
+	if len(src.Attributes) > 0 {
+		paths = append(paths, "attributes")
+	}
+	return paths
+}
+
+// applyUpdateMask validates every path before copying anything so a bad mask
+// leaves the stored product untouched
+func applyUpdateMask(dst, src *domain.Product, paths []string) error {
+	if len(paths) == 0 {
+		paths = defaultUpdatePaths(src)
+	}
+
+	setters := make([]productFieldSetter, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if name, ok := strings.CutPrefix(path, "attributes."); ok {
+			setters = append(setters, attributeSetter(name))
+			continue
+		}
+		setter, ok := productFieldSetters[path]
+		if !ok {
+			return fmt.Errorf("%w: unknown or immutable update_mask path %q", domain.ErrInvalidArgument, path)
+		}
+		setters = append(setters, setter)
+	}
+
+	for _, setter := range setters {
+		if err := setter(dst, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attributeSetter returns the setter for an "attributes.<name>" path, which
+// sets or, when the update has no value for it, clears a single attribute
+func attributeSetter(name string) productFieldSetter {
+	return func(dst, src *domain.Product) error {
+		value, ok := src.Attributes[name]
+		if !ok {
+			delete(dst.Attributes, name)
+			return nil
+		}
+		if dst.Attributes == nil {
+			dst.Attributes = domain.AttributeValues{}
+		}
+		dst.Attributes[name] = value
+		return nil
+	}
+}
+
+// The details helpers make sure the stored product is of the kind being
+// updated and return the incoming details, treating an unset oneof as zero values
+
+func digitalDetails(dst, src *domain.Product) (domain.DigitalProduct, error) {
+	if dst.DigitalProduct == nil {
+		return domain.DigitalProduct{}, fmt.Errorf("%w: product %s is not a digital product", domain.ErrInvalidArgument, dst.ID)
+	}
+	if src.DigitalProduct == nil {
+		return domain.DigitalProduct{}, nil
+	}
+	return *src.DigitalProduct, nil
+}
+
+func physicalDetails(dst, src *domain.Product) (domain.PhysicalProduct, error) {
+	if dst.PhysicalProduct == nil {
+		return domain.PhysicalProduct{}, fmt.Errorf("%w: product %s is not a physical product", domain.ErrInvalidArgument, dst.ID)
+	}
+	if src.PhysicalProduct == nil {
+		return domain.PhysicalProduct{}, nil
+	}
+	return *src.PhysicalProduct, nil
+}
+
+func subscriptionDetails(dst, src *domain.Product) (domain.SubscriptionProduct, error) {
+	if dst.SubscriptionProduct == nil {
+		return domain.SubscriptionProduct{}, fmt.Errorf("%w: product %s is not a subscription product", domain.ErrInvalidArgument, dst.ID)
+	}
+	if src.SubscriptionProduct == nil {
+		return domain.SubscriptionProduct{}, nil
+	}
+	return *src.SubscriptionProduct, nil
+}
+
+func bundleDetails(dst, src *domain.Product) (domain.BundleProduct, error) {
+	if dst.BundleProduct == nil {
+		return domain.BundleProduct{}, fmt.Errorf("%w: product %s is not a bundle product", domain.ErrInvalidArgument, dst.ID)
+	}
+	if src.BundleProduct == nil {
+		return domain.BundleProduct{}, nil
+	}
+	return *src.BundleProduct, nil
+}
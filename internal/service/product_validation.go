@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+)
+
+// validateProduct checks the rules every product must satisfy before it is
+// written, whether it arrives through CreateProduct, a batch or an import
+func validateProduct(product *domain.Product) error {
+	if strings.TrimSpace(product.Name) == "" {
+		return fmt.Errorf("%w: product name cannot be empty", domain.ErrInvalidArgument)
+	}
+	if err := product.Price.Validate(); err != nil {
+		return fmt.Errorf("price: %w", err)
+	}
+	if product.Price.Amount.Sign() < 0 {
+		return fmt.Errorf("%w: product price cannot be negative", domain.ErrInvalidArgument)
+	}
+	if product.ExternalSKU != nil && strings.TrimSpace(*product.ExternalSKU) == "" {
+		return fmt.Errorf("%w: external_sku cannot be blank", domain.ErrInvalidArgument)
+	}
+
+	optionNames, err := normalizeOptionNames(product.OptionNames)
+	if err != nil {
+		return err
+	}
+	product.OptionNames = optionNames
+	tags, err := normalizeTags(product.Tags)
+	if err != nil {
+		return err
+	}
+	product.Tags = tags
+	product.Categories = dedupeCategories(product.Categories)
+
+	kinds := 0
+	if details := product.DigitalProduct; details != nil {
+		kinds++
+		if details.FileSize < 0 {
+			return fmt.Errorf("%w: file_size cannot be negative", domain.ErrInvalidArgument)
+		}
+	}
+	if details := product.PhysicalProduct; details != nil {
+		kinds++
+		if err := details.Weight.Validate(); err != nil {
+			return err
+		}
+		if err := details.Dimensions.Validate(); err != nil {
+			return err
+		}
+	}
+	if details := product.SubscriptionProduct; details != nil {
+		kinds++
+		if err := details.RenewalPrice.Validate(); err != nil {
+			return fmt.Errorf("renewal_price: %w", err)
+		}
+		if details.RenewalPrice.Amount.Sign() < 0 {
+			return fmt.Errorf("%w: renewal_price cannot be negative", domain.ErrInvalidArgument)
+		}
+		if details.RenewalPrice.Currency != product.Price.Currency {
+			return fmt.Errorf("%w: renewal_price must be in the product currency %s", domain.ErrInvalidArgument, product.Price.Currency)
+		}
+	}
+	if product.BundleProduct != nil {
+		kinds++
+	}
+	if kinds > 1 {
+		return fmt.Errorf("%w: a product can only be of one type", domain.ErrInvalidArgument)
+	}
+	return nil
+}
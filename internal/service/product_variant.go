@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// variantFieldSetter copies one field mask path from the update onto the stored variant
+type variantFieldSetter func(dst, src *domain.ProductVariant)
+
+// variantFieldSetters lists every path UpdateProductVariant accepts in its update mask
+var variantFieldSetters = map[string]variantFieldSetter{
+	"sku":     func(dst, src *domain.ProductVariant) { dst.SKU = src.SKU },
+	"barcode": func(dst, src *domain.ProductVariant) { dst.Barcode = src.Barcode },
+	"options": func(dst, src *domain.ProductVariant) { dst.Options = src.Options },
+	"price_override": func(dst, src *domain.ProductVariant) {
+		dst.SetPriceOverride(src.PriceOverride())
+	},
+	"weight_override": func(dst, src *domain.ProductVariant) {
+		dst.SetWeightOverride(src.WeightOverride())
+	},
+}
+
+// normalizeOptionNames trims the option axes of a product and checks they
+// are named and distinct
+func normalizeOptionNames(names domain.OptionNames) (domain.OptionNames, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make(domain.OptionNames, len(names))
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: option names cannot be blank", domain.ErrInvalidArgument)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate option name %q", domain.ErrInvalidArgument, name)
+		}
+		seen[name] = true
+		normalized[i] = name
+	}
+	return normalized, nil
+}
+
+// validateVariant normalises a variant and checks it against its product
+func validateVariant(variant *domain.ProductVariant, product *domain.Product) error {
+	variant.SKU = strings.TrimSpace(variant.SKU)
+	if variant.SKU == "" {
+		return fmt.Errorf("%w: sku is required", domain.ErrInvalidArgument)
+	}
+	if variant.Barcode != nil && !domain.ValidGTIN(*variant.Barcode) {
+		return fmt.Errorf("%w: barcode %q is not a valid GTIN-8, GTIN-12, GTIN-13 or GTIN-14", domain.ErrInvalidArgument, *variant.Barcode)
+	}
+
+	options := make(domain.VariantOptions, len(variant.Options))
+	for name, value := range variant.Options {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return fmt.Errorf("%w: option %q needs a value", domain.ErrInvalidArgument, name)
+		}
+		options[strings.TrimSpace(name)] = value
+	}
+	if !options.Matches(product.OptionNames) {
+		return fmt.Errorf("%w: options must set exactly the option names of the product %v", domain.ErrInvalidArgument, []string(product.OptionNames))
+	}
+	variant.Options = options
+
+	if price := variant.PriceOverride(); price != nil {
+		if err := price.Validate(); err != nil {
+			return fmt.Errorf("price_override: %w", err)
+		}
+		if price.Amount.Sign() < 0 {
+			return fmt.Errorf("%w: price_override cannot be negative", domain.ErrInvalidArgument)
+		}
+		if price.Currency != product.Price.Currency {
+			return fmt.Errorf("%w: price_override must be in the product currency %s", domain.ErrInvalidArgument, product.Price.Currency)
+		}
+	}
+	if weight := variant.WeightOverride(); weight != nil {
+		if product.PhysicalProduct == nil {
+			return fmt.Errorf("%w: only variants of physical products can override the weight", domain.ErrInvalidArgument)
+		}
+		if weight.Unit == "" {
+			return fmt.Errorf("%w: weight_override needs a unit of g, kg, lb or oz", domain.ErrInvalidArgument)
+		}
+		if err := weight.Validate(); err != nil {
+			return fmt.Errorf("weight_override: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkVariantsAfterProductChange makes sure a product update keeps its
+// variants valid: their options must still match the option names, and
+// price overrides the product currency
+func (s *productService) checkVariantsAfterProductChange(ctx context.Context, before, after *domain.Product) error {
+	if before.OptionNames.Equal(after.OptionNames) && before.Price.Currency == after.Price.Currency {
+		return nil
+	}
+	variants, err := s.ProductRepo.ListVariants(ctx, after.ID)
+	if err != nil {
+		return err
+	}
+	for i := range variants {
+		if !variants[i].Options.Matches(after.OptionNames) {
+			return fmt.Errorf("product %s has variants with other options, which must be updated or deleted first: %w", after.ID, domain.ErrFailedPrecondition)
+		}
+		if price := variants[i].PriceOverride(); price != nil && price.Currency != after.Price.Currency {
+			return fmt.Errorf("product %s has variants priced in %s, which must be updated or deleted first: %w", after.ID, price.Currency, domain.ErrFailedPrecondition)
+		}
+	}
+	return nil
+}
+
+// CreateProductVariant adds a variant to a product
+func (s *productService) CreateProductVariant(ctx context.Context, variant *domain.ProductVariant) (*domain.ProductVariant, error) {
+	if variant.ProductID == uuid.Nil {
+		return nil, fmt.Errorf("%w: product_id is required", domain.ErrInvalidArgument)
+	}
+	product, err := s.ProductRepo.GetByID(variant.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateVariant(variant, product); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.CreateVariant(ctx, variant); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// GetProductVariant retrieves a variant by its ID
+func (s *productService) GetProductVariant(ctx context.Context, id uuid.UUID) (*domain.ProductVariant, error) {
+	return s.ProductRepo.GetVariant(ctx, id)
+}
+
+// GetProductVariantBySKU retrieves the variant with the given SKU
+func (s *productService) GetProductVariantBySKU(ctx context.Context, sku string) (*domain.ProductVariant, error) {
+	return s.ProductRepo.FindVariantBySKU(ctx, strings.TrimSpace(sku))
+}
+
+// ListProductVariants lists the variants of a product
+func (s *productService) ListProductVariants(ctx context.Context, productID uuid.UUID) ([]domain.ProductVariant, error) {
+	return s.ProductRepo.ListVariants(ctx, productID)
+}
+
+// UpdateProductVariant updates the fields of a variant named in the mask, or
+// all of them if the mask is empty. update.Version must be the version the
+// client read.
+func (s *productService) UpdateProductVariant(ctx context.Context, id uuid.UUID, update *domain.ProductVariant, updateMask []string) (*domain.ProductVariant, error) {
+	variant, err := s.ProductRepo.GetVariant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.Version != variant.Version {
+		return nil, fmt.Errorf("product variant with ID %s has changed since it was read: %w", id, domain.ErrVersionConflict)
+	}
+
+	paths := updateMask
+	if len(paths) == 0 {
+		paths = []string{"sku", "barcode", "options", "price_override", "weight_override"}
+	}
+	setters := make([]variantFieldSetter, 0, len(paths))
+	for _, path := range paths {
+		setter, ok := variantFieldSetters[strings.TrimSpace(path)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown or immutable update_mask path %q", domain.ErrInvalidArgument, path)
+		}
+		setters = append(setters, setter)
+	}
+	for _, setter := range setters {
+		setter(variant, update)
+	}
+
+	product, err := s.ProductRepo.GetByID(variant.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateVariant(variant, product); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.UpdateVariant(ctx, variant); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// DeleteProductVariant removes a variant at the given version
+func (s *productService) DeleteProductVariant(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.ProductRepo.DeleteVariant(ctx, id, version)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+)
+
+// warehouseFieldSetters lists every path UpdateWarehouse accepts in its update mask
+var warehouseFieldSetters = map[string]func(dst, src *domain.Warehouse){
+	"name":      func(dst, src *domain.Warehouse) { dst.Name = src.Name },
+	"address":   func(dst, src *domain.Warehouse) { dst.Address = src.Address },
+	"time_zone": func(dst, src *domain.Warehouse) { dst.TimeZone = src.TimeZone },
+	"priority":  func(dst, src *domain.Warehouse) { dst.Priority = src.Priority },
+	"active":    func(dst, src *domain.Warehouse) { dst.Active = src.Active },
+}
+
+// validateAddress normalises and checks a postal address. The first line,
+// the city and the country are required.
+func validateAddress(address *domain.Address) error {
+	for _, field := range []*string{&address.Line1, &address.Line2, &address.City, &address.Region, &address.PostalCode} {
+		*field = strings.TrimSpace(*field)
+	}
+	if address.Line1 == "" || address.City == "" {
+		return fmt.Errorf("%w: address line1 and city are required", domain.ErrInvalidArgument)
+	}
+	countryCode, err := normalizeCountryCode(address.CountryCode)
+	if err != nil {
+		return err
+	}
+	address.CountryCode = countryCode
+	return nil
+}
+
+// normalizeCountryCode upper-cases and checks an ISO 3166-1 alpha-2 country code
+func normalizeCountryCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	region, err := language.ParseRegion(code)
+	if err != nil || len(code) != 2 || !region.IsCountry() {
+		return "", fmt.Errorf("%w: country_code %q is not an ISO 3166-1 alpha-2 country code", domain.ErrInvalidArgument, code)
+	}
+	return code, nil
+}
+
+// validateWarehouse normalises and checks the fields a client can set
+func validateWarehouse(warehouse *domain.Warehouse) error {
+	warehouse.Name = strings.TrimSpace(warehouse.Name)
+	if warehouse.Name == "" {
+		return fmt.Errorf("%w: warehouse name cannot be empty", domain.ErrInvalidArgument)
+	}
+	if err := validateAddress(&warehouse.Address); err != nil {
+		return err
+	}
+	// LoadLocation takes "" and "Local" for UTC and the host's zone, neither of
+	// which names a place
+	warehouse.TimeZone = strings.TrimSpace(warehouse.TimeZone)
+	if warehouse.TimeZone == "" || warehouse.TimeZone == "Local" {
+		return fmt.Errorf("%w: time_zone must be an IANA time zone such as \"Europe/Berlin\"", domain.ErrInvalidArgument)
+	}
+	if _, err := time.LoadLocation(warehouse.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time_zone %q", domain.ErrInvalidArgument, warehouse.TimeZone)
+	}
+	return nil
+}
+
+// CreateWarehouse adds a warehouse
+func (s *productService) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	if err := validateWarehouse(warehouse); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.CreateWarehouse(ctx, warehouse); err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// GetWarehouse retrieves a warehouse by its ID
+func (s *productService) GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	return s.ProductRepo.GetWarehouse(ctx, id)
+}
+
+// ListWarehouses lists warehouses in the order stock is allocated from them
+func (s *productService) ListWarehouses(ctx context.Context, opts domain.WarehouseListOptions) ([]domain.Warehouse, error) {
+	return s.ProductRepo.ListWarehouses(ctx, opts)
+}
+
+// UpdateWarehouse updates the fields of a warehouse named in the mask, or all
+// of them if the mask is empty. update.Version must be the version the client read.
+func (s *productService) UpdateWarehouse(ctx context.Context, id uuid.UUID, update *domain.Warehouse, updateMask []string) (*domain.Warehouse, error) {
+	warehouse, err := s.ProductRepo.GetWarehouse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.Version != warehouse.Version {
+		return nil, fmt.Errorf("warehouse with ID %s has changed since it was read: %w", id, domain.ErrVersionConflict)
+	}
+
+	paths := updateMask
+	if len(paths) == 0 {
+		paths = []string{"name", "address", "time_zone", "priority", "active"}
+	}
+	setters := make([]func(dst, src *domain.Warehouse), 0, len(paths))
+	for _, path := range paths {
+		setter, ok := warehouseFieldSetters[strings.TrimSpace(path)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown or immutable update_mask path %q", domain.ErrInvalidArgument, path)
+		}
+		setters = append(setters, setter)
+	}
+	for _, setter := range setters {
+		setter(warehouse, update)
+	}
+
+	if err := validateWarehouse(warehouse); err != nil {
+		return nil, err
+	}
+	if err := s.ProductRepo.UpdateWarehouse(ctx, warehouse); err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// DeleteWarehouse removes a warehouse that holds no stock at the given version
+func (s *productService) DeleteWarehouse(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.ProductRepo.DeleteWarehouse(ctx, id, version)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+	"time"
+)
+
+const (
+	// watchBatchSize is the number of change log entries read per query
+	watchBatchSize = 500
+	// watchPollInterval is how long a caught up watcher waits before
+	// looking for new changes
+	watchPollInterval = time.Second
+)
+
+// resumeToken is the decoded form of the opaque resume_token of a product event
+type resumeToken struct {
+	TransactionID int64 `json:"tx"`
+	Sequence      int64 `json:"seq"`
+}
+
+func encodeResumeToken(position domain.ProductEventPosition) string {
+	raw, _ := json.Marshal(resumeToken{TransactionID: position.TransactionID, Sequence: position.Sequence})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeResumeToken(s string) (domain.ProductEventPosition, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.ProductEventPosition{}, fmt.Errorf("%w: malformed resume_token", domain.ErrInvalidArgument)
+	}
+
+	var token resumeToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.Sequence <= 0 {
+		return domain.ProductEventPosition{}, fmt.Errorf("%w: malformed resume_token", domain.ErrInvalidArgument)
+	}
+	return domain.ProductEventPosition{TransactionID: token.TransactionID, Sequence: token.Sequence}, nil
+}
+
+// WatchProducts sends every change committed after the resume token, or
+// after the call if there is none, to fn until ctx is done or fn fails.
+// Changes are recorded by the database itself, so the stream covers every
+// writer, including other instances of the service.
+func (s *productService) WatchProducts(ctx context.Context, req *pb.WatchProductsRequest, fn func(*pb.ProductEvent) error) error {
+	var position domain.ProductEventPosition
+	var err error
+	if req.GetResumeToken() != "" {
+		position, err = decodeResumeToken(req.GetResumeToken())
+	} else {
+		position, err = s.ProductRepo.LatestProductEventPosition(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for {
+		events, err := s.ProductRepo.ListProductEvents(ctx, position, watchBatchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for i := range events {
+			position = events[i].Position()
+			if err := fn(mapper.ProductEventToProto(&events[i], encodeResumeToken(position))); err != nil {
+				return err
+			}
+		}
+		if len(events) == watchBatchSize {
+			// More changes are waiting
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
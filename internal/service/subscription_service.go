@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"product-microservice/internal/domain"
 	"product-microservice/internal/repository"
 	"github.com/google/uuid"
@@ -10,11 +10,11 @@ import (
 
 // SubscriptionService defines the interface for subscription-related business logic
 type SubscriptionService interface {
-	CreateSubscriptionPlan(ctx context.Context, productID uuid.UUID, planName string, duration int, price float64) (*domain.SubscriptionPlan, error)
+	CreateSubscriptionPlan(ctx context.Context, productID uuid.UUID, planName string, duration int, price domain.Money) (*domain.SubscriptionPlan, error)
 	GetSubscriptionPlanByID(ctx context.Context, id uuid.UUID) (*domain.SubscriptionPlan, error)
-	ListSubscriptionPlans(ctx context.Context) ([]*domain.SubscriptionPlan, error)
-	DeleteSubscriptionPlan(ctx context.Context, id uuid.UUID) error
-	UpdateSubscriptionPlan(ctx context.Context, id uuid.UUID, planName string, price float64, durationDays int) (*domain.SubscriptionPlan, error)
+	ListSubscriptionPlans(ctx context.Context, productID uuid.UUID) ([]*domain.SubscriptionPlan, error)
+	DeleteSubscriptionPlan(ctx context.Context, id uuid.UUID, version int64) error
+	UpdateSubscriptionPlan(ctx context.Context, id uuid.UUID, version int64, planName string, price domain.Money, durationDays int) (*domain.SubscriptionPlan, error)
 }
 
 // subscriptionService is the implementation of SubscriptionService
@@ -28,17 +28,17 @@ func NewSubscriptionService(repo repository.SubscriptionRepository) Subscription
 }
 
 // CreateSubscriptionPlan creates a new subscription plan
-func (s *subscriptionService) CreateSubscriptionPlan(ctx context.Context, productID uuid.UUID, planName string, duration int, price float64) (*domain.SubscriptionPlan, error) {
+func (s *subscriptionService) CreateSubscriptionPlan(ctx context.Context, productID uuid.UUID, planName string, duration int, price domain.Money) (*domain.SubscriptionPlan, error) {
 	if planName == "" {
-		return nil, errors.New("subscription plan name cannot be empty")
+		return nil, fmt.Errorf("%w: subscription plan name cannot be empty", domain.ErrInvalidArgument)
 	}
 
 	if duration <= 0 {
-		return nil, errors.New("subscription plan duration must be greater than zero")
+		return nil, fmt.Errorf("%w: subscription plan duration must be greater than zero", domain.ErrInvalidArgument)
 	}
 
-	if price <= 0 {
-		return nil, errors.New("subscription plan price must be greater than zero")
+	if err := validatePlanPrice(price); err != nil {
+		return nil, err
 	}
 
 	plan := &domain.SubscriptionPlan{
@@ -47,6 +47,7 @@ func (s *subscriptionService) CreateSubscriptionPlan(ctx context.Context, produc
 		PlanName:  planName,
 		Duration:  duration,
 		Price:     price,
+		Version:   1,
 	}
 
 	// Save the plan in the repository
@@ -58,8 +59,13 @@ func (s *subscriptionService) GetSubscriptionPlanByID(ctx context.Context, id uu
 	return s.repo.FindByID(ctx, id)
 }
 
-// ListSubscriptionPlans fetches all subscription plans from the repository
-func (s *subscriptionService) ListSubscriptionPlans(ctx context.Context) ([]*domain.SubscriptionPlan, error) {
+// ListSubscriptionPlans fetches the subscription plans of a product, or all
+// plans if productID is uuid.Nil
+func (s *subscriptionService) ListSubscriptionPlans(ctx context.Context, productID uuid.UUID) ([]*domain.SubscriptionPlan, error) {
+	if productID != uuid.Nil {
+		return s.repo.FindByProductID(ctx, productID)
+	}
+
 	// Fetch all subscription plans without any conditions
 	plans, err := s.repo.ListAll(ctx)
 	if err != nil {
@@ -69,19 +75,28 @@ func (s *subscriptionService) ListSubscriptionPlans(ctx context.Context) ([]*dom
 }
 
 // DeleteSubscriptionPlan deletes a subscription plan by its ID
-func (s *subscriptionService) DeleteSubscriptionPlan(ctx context.Context, id uuid.UUID) error {
+func (s *subscriptionService) DeleteSubscriptionPlan(ctx context.Context, id uuid.UUID, version int64) error {
 	// Delegate to the repository to delete the subscription
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, version)
 }
 
 // UpdateSubscriptionPlan updates a subscription plan by its ID
-func (s *subscriptionService) UpdateSubscriptionPlan(ctx context.Context, id uuid.UUID, planName string, price float64, durationDays int) (*domain.SubscriptionPlan, error) {
+func (s *subscriptionService) UpdateSubscriptionPlan(ctx context.Context, id uuid.UUID, version int64, planName string, price domain.Money, durationDays int) (*domain.SubscriptionPlan, error) {
     // Find the subscription plan by ID
     subscription, err := s.repo.FindByID(ctx, id)
     if err != nil {
         return nil, err
     }
 
+    // Reject stale writes early; the repository re-checks atomically
+    if subscription.Version != version {
+        return nil, fmt.Errorf("subscription plan %s has changed since it was read: %w", id, domain.ErrVersionConflict)
+    }
+
+    if err := validatePlanPrice(price); err != nil {
+        return nil, err
+    }
+
     // Update the subscription's fields
     subscription.PlanName = planName
     subscription.Price = price
@@ -95,3 +110,14 @@ func (s *subscriptionService) UpdateSubscriptionPlan(ctx context.Context, id uui
     return subscription, nil
 }
 
+
+// validatePlanPrice checks that a plan price is a positive amount in a known currency
+func validatePlanPrice(price domain.Money) error {
+	if err := price.Validate(); err != nil {
+		return fmt.Errorf("price: %w", err)
+	}
+	if price.Amount.Sign() <= 0 {
+		return fmt.Errorf("%w: subscription plan price must be greater than zero", domain.ErrInvalidArgument)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package shipping
+
+import (
+	"fmt"
+	"math"
+	"product-microservice/internal/domain"
+	"sort"
+)
+
+// Item is a number of units of one product to ship. Each unit is packed on
+// its own, so its weight and dimensions are those of its parcel; without
+// dimensions only its actual weight counts.
+type Item struct {
+	Weight     domain.Weight
+	Dimensions domain.Dimensions
+	Quantity   int64
+}
+
+// Quote is the price of a shipment with one carrier
+type Quote struct {
+	CarrierCode string
+	CarrierName string
+	// DimensionalWeightKg is the volume of the parcels over the carrier's
+	// divisor, and BillableWeightKg the sum over the parcels of the greater
+	// of their actual and dimensional weight
+	DimensionalWeightKg float64
+	BillableWeightKg    float64
+	Price               domain.Money
+}
+
+// Estimate is what shipping items along a route costs with every carrier
+// serving its zone, cheapest first
+type Estimate struct {
+	Zone           string
+	ActualWeightKg float64
+	Quotes         []Quote
+}
+
+// zone returns the first zone covering a route
+func (t *RateTable) zone(origin, destination string) *Zone {
+	for i := range t.Zones {
+		if t.Zones[i].matches(origin, destination) {
+			return &t.Zones[i]
+		}
+	}
+	return nil
+}
+
+// Estimate quotes every carrier serving the zone of the route from origin to
+// destination, both country codes, for the items. The origin may be empty if
+// not known. Each parcel is billed by the greater of its actual and
+// dimensional weight, and the shipment is priced by the sum of them. Carriers
+// whose brackets the shipment is too heavy for are left out.
+func (t *RateTable) Estimate(origin, destination string, items []Item) (*Estimate, error) {
+	zone := t.zone(origin, destination)
+	if zone == nil {
+		return nil, fmt.Errorf("%w: no shipping zone covers shipments to %s", domain.ErrFailedPrecondition, destination)
+	}
+
+	// parcels holds the weight and volume of one unit of each item
+	type parcel struct {
+		kg, cm3  float64
+		quantity float64
+	}
+	parcels := make([]parcel, 0, len(items))
+	var actualKg float64
+	for _, item := range items {
+		weight, err := item.Weight.In(domain.WeightUnitKilogram)
+		if err != nil {
+			return nil, err
+		}
+		p := parcel{kg: weight.Value, quantity: float64(item.Quantity)}
+		if !item.Dimensions.IsZero() {
+			dimensions, err := item.Dimensions.In(domain.LengthUnitCentimeter)
+			if err != nil {
+				return nil, err
+			}
+			p.cm3 = dimensions.Volume()
+		}
+		actualKg += p.kg * p.quantity
+		parcels = append(parcels, p)
+	}
+
+	estimate := &Estimate{Zone: zone.Name, ActualWeightKg: actualKg, Quotes: []Quote{}}
+	for i := range t.Carriers {
+		carrier := &t.Carriers[i]
+		rate := carrier.rate(zone.Name)
+		if rate == nil {
+			continue
+		}
+		quote := Quote{CarrierCode: carrier.Code, CarrierName: carrier.Name}
+		for _, p := range parcels {
+			dimensionalKg := p.cm3 / carrier.DimDivisor
+			quote.DimensionalWeightKg += dimensionalKg * p.quantity
+			quote.BillableWeightKg += math.Max(p.kg, dimensionalKg) * p.quantity
+		}
+		price, ok, err := rate.price(quote.BillableWeightKg)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		quote.Price = domain.NewMoney(t.Currency, price)
+		estimate.Quotes = append(estimate.Quotes, quote)
+	}
+	sort.SliceStable(estimate.Quotes, func(i, j int) bool {
+		return estimate.Quotes[i].Price.Amount.Cmp(estimate.Quotes[j].Price.Amount) < 0
+	})
+	return estimate, nil
+}
+
+// price returns the price of a shipment of a billable weight, and false if
+// the rate does not carry it
+func (r *Rate) price(billableKg float64) (domain.Amount, bool, error) {
+	for _, bracket := range r.Brackets {
+		if billableKg <= bracket.MaxWeightKg {
+			return bracket.Price, true, nil
+		}
+	}
+	if r.AdditionalPerKg == nil {
+		return domain.Amount{}, false, nil
+	}
+	last := r.Brackets[len(r.Brackets)-1]
+	extra, err := r.AdditionalPerKg.MulInt(int64(math.Ceil(billableKg - last.MaxWeightKg)))
+	if err != nil {
+		return domain.Amount{}, false, err
+	}
+	price, err := last.Price.Add(extra)
+	return price, err == nil, err
+}
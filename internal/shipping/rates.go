@@ -0,0 +1,153 @@
+// Package shipping estimates what carriers charge to ship physical products,
+// from rate tables that sort routes into zones and price each zone by
+// billable weight.
+package shipping
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"product-microservice/internal/domain"
+)
+
+// Zone groups the routes a carrier prices alike. Origins and destinations are
+// ISO 3166-1 alpha-2 country codes; an empty list matches every country.
+type Zone struct {
+	Name         string   `json:"name"`
+	Origins      []string `json:"origins"`
+	Destinations []string `json:"destinations"`
+}
+
+// matches reports whether the zone covers shipments from origin, which is
+// empty when not known, to destination
+func (z *Zone) matches(origin, destination string) bool {
+	return contains(z.Origins, origin) && contains(z.Destinations, destination)
+}
+
+func contains(countries []string, country string) bool {
+	if len(countries) == 0 {
+		return true
+	}
+	for _, c := range countries {
+		if c == country {
+			return true
+		}
+	}
+	return false
+}
+
+// Bracket is the price of shipments up to a billable weight
+type Bracket struct {
+	MaxWeightKg float64       `json:"max_weight_kg"`
+	Price       domain.Amount `json:"price"`
+}
+
+// Rate is what a carrier charges within one zone. The first bracket the
+// billable weight fits in applies.
+type Rate struct {
+	Zone     string    `json:"zone"`
+	Brackets []Bracket `json:"brackets"`
+	// AdditionalPerKg is added to the price of the last bracket for every
+	// started kilogram above it. Without it heavier shipments are not carried.
+	AdditionalPerKg *domain.Amount `json:"additional_per_kg,omitempty"`
+}
+
+// Carrier is a shipping service with its own rates
+type Carrier struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// DimDivisor converts the volume of a parcel in cubic centimeters into its
+	// dimensional weight in kilograms; 5000 for most carriers
+	DimDivisor float64 `json:"dim_divisor"`
+	Rates      []Rate  `json:"rates"`
+}
+
+// rate returns the rate of the carrier in a zone, or nil if it does not serve it
+func (c *Carrier) rate(zone string) *Rate {
+	for i := range c.Rates {
+		if c.Rates[i].Zone == zone {
+			return &c.Rates[i]
+		}
+	}
+	return nil
+}
+
+// RateTable holds the zones and carrier rates shipping is estimated with.
+// All prices are in Currency.
+type RateTable struct {
+	Currency string `json:"currency"`
+	// Zones in order of precedence: a shipment is in the first that matches
+	Zones    []Zone    `json:"zones"`
+	Carriers []Carrier `json:"carriers"`
+}
+
+// LoadRateTable reads and validates a rate table from a JSON file
+func LoadRateTable(path string) (*RateTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open shipping rates: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	var table RateTable
+	if err := decoder.Decode(&table); err != nil {
+		return nil, fmt.Errorf("failed to parse shipping rates %s: %w", path, err)
+	}
+	if err := table.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid shipping rates %s: %w", path, err)
+	}
+	return &table, nil
+}
+
+// Validate checks that the table is consistent: zones and carriers are named
+// uniquely, rates refer to known zones, and brackets rise in weight
+func (t *RateTable) Validate() error {
+	if !domain.IsCurrencyCode(t.Currency) {
+		return fmt.Errorf("unknown ISO 4217 currency code %q", t.Currency)
+	}
+	zones := make(map[string]bool, len(t.Zones))
+	for _, zone := range t.Zones {
+		if zone.Name == "" || zones[zone.Name] {
+			return fmt.Errorf("zone names must be set and unique, got %q", zone.Name)
+		}
+		zones[zone.Name] = true
+	}
+
+	carriers := make(map[string]bool, len(t.Carriers))
+	for _, carrier := range t.Carriers {
+		if carrier.Code == "" || carriers[carrier.Code] {
+			return fmt.Errorf("carrier codes must be set and unique, got %q", carrier.Code)
+		}
+		carriers[carrier.Code] = true
+		if !(carrier.DimDivisor > 0) {
+			return fmt.Errorf("carrier %s: dim_divisor must be positive", carrier.Code)
+		}
+		rated := make(map[string]bool, len(carrier.Rates))
+		for _, rate := range carrier.Rates {
+			if !zones[rate.Zone] || rated[rate.Zone] {
+				return fmt.Errorf("carrier %s: rates must be for known zones, one each, got %q", carrier.Code, rate.Zone)
+			}
+			rated[rate.Zone] = true
+			if len(rate.Brackets) == 0 {
+				return fmt.Errorf("carrier %s: zone %s has no weight brackets", carrier.Code, rate.Zone)
+			}
+			previous := 0.0
+			for _, bracket := range rate.Brackets {
+				if !(bracket.MaxWeightKg > previous) || math.IsInf(bracket.MaxWeightKg, 0) {
+					return fmt.Errorf("carrier %s: brackets of zone %s must have positive weights in ascending order", carrier.Code, rate.Zone)
+				}
+				previous = bracket.MaxWeightKg
+				if bracket.Price.Sign() < 0 {
+					return fmt.Errorf("carrier %s: prices cannot be negative", carrier.Code)
+				}
+			}
+			if rate.AdditionalPerKg != nil && rate.AdditionalPerKg.Sign() < 0 {
+				return fmt.Errorf("carrier %s: additional_per_kg cannot be negative", carrier.Code)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateAttributeDefinition handles the CreateAttributeDefinition gRPC method
+func (h *ProductHandler) CreateAttributeDefinition(ctx context.Context, req *pb.AttributeDefinition) (*pb.AttributeDefinition, error) {
+	definition, err := mapper.AttributeDefinitionFromProto(req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	// IDs are assigned by the server
+	definition.ID = uuid.Nil
+
+	created, err := h.ProductService.CreateAttributeDefinition(ctx, definition)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to create attribute definition: %w", err))
+	}
+	return mapper.AttributeDefinitionToProto(created), nil
+}
+
+// GetAttributeDefinition handles the GetAttributeDefinition gRPC method
+func (h *ProductHandler) GetAttributeDefinition(ctx context.Context, req *pb.GetAttributeDefinitionRequest) (*pb.AttributeDefinition, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid attribute definition ID format: %v", err)
+	}
+
+	definition, err := h.ProductService.GetAttributeDefinition(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return mapper.AttributeDefinitionToProto(definition), nil
+}
+
+// ListAttributeDefinitions handles the ListAttributeDefinitions gRPC method
+func (h *ProductHandler) ListAttributeDefinitions(ctx context.Context, req *pb.ListAttributeDefinitionsRequest) (*pb.ListAttributeDefinitionsResponse, error) {
+	categoryID, err := mapper.OptionalUUID(req.GetCategoryId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid category ID format: %v", err)
+	}
+
+	definitions, err := h.ProductService.ListAttributeDefinitions(ctx, domain.AttributeDefinitionListOptions{
+		ProductType: req.GetProductType(),
+		CategoryID:  categoryID,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.ListAttributeDefinitionsResponse{Definitions: make([]*pb.AttributeDefinition, len(definitions))}
+	for i := range definitions {
+		response.Definitions[i] = mapper.AttributeDefinitionToProto(&definitions[i])
+	}
+	return response, nil
+}
+
+// UpdateAttributeDefinition handles the UpdateAttributeDefinition gRPC method
+func (h *ProductHandler) UpdateAttributeDefinition(ctx context.Context, req *pb.UpdateAttributeDefinitionRequest) (*pb.AttributeDefinition, error) {
+	update, err := mapper.AttributeDefinitionFromProto(req.GetDefinition())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if update.ID == uuid.Nil {
+		return nil, status.Error(codes.InvalidArgument, "definition.id is required")
+	}
+	if update.Version, err = domain.ParseETag(req.GetDefinition().GetEtag()); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	updated, err := h.ProductService.UpdateAttributeDefinition(ctx, update.ID, update, req.GetUpdateMask().GetPaths())
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to update attribute definition: %w", err))
+	}
+	return mapper.AttributeDefinitionToProto(updated), nil
+}
+
+// DeleteAttributeDefinition handles the DeleteAttributeDefinition gRPC method
+func (h *ProductHandler) DeleteAttributeDefinition(ctx context.Context, req *pb.DeleteAttributeDefinitionRequest) (*emptypb.Empty, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid attribute definition ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if err := h.ProductService.DeleteAttributeDefinition(ctx, id, version); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to delete attribute definition: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
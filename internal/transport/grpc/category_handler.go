@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateCategory handles the CreateCategory gRPC method
+func (h *ProductHandler) CreateCategory(ctx context.Context, req *pb.Category) (*pb.Category, error) {
+	category, err := mapper.CategoryFromProto(req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	// IDs are assigned by the server
+	category.ID = uuid.Nil
+
+	created, err := h.ProductService.CreateCategory(ctx, category)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to create category: %w", err))
+	}
+	return mapper.CategoryToProto(created), nil
+}
+
+// GetCategory handles the GetCategory gRPC method
+func (h *ProductHandler) GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.Category, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid category ID format: %v", err)
+	}
+
+	category, err := h.ProductService.GetCategory(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return mapper.CategoryToProto(category), nil
+}
+
+// ListCategories handles the ListCategories gRPC method
+func (h *ProductHandler) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	parentID, err := mapper.OptionalUUID(req.GetParentId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parent category ID format: %v", err)
+	}
+
+	categories, err := h.ProductService.ListCategories(ctx, domain.CategoryListOptions{
+		ParentID:           parentID,
+		IncludeDescendants: req.GetIncludeDescendants(),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.ListCategoriesResponse{Categories: make([]*pb.Category, len(categories))}
+	for i := range categories {
+		response.Categories[i] = mapper.CategoryToProto(&categories[i])
+	}
+	return response, nil
+}
+
+// UpdateCategory handles the UpdateCategory gRPC method
+func (h *ProductHandler) UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.Category, error) {
+	update, err := mapper.CategoryFromProto(req.GetCategory())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if update.ID == uuid.Nil {
+		return nil, status.Error(codes.InvalidArgument, "category.id is required")
+	}
+	if update.Version, err = domain.ParseETag(req.GetCategory().GetEtag()); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	updated, err := h.ProductService.UpdateCategory(ctx, update.ID, update, req.GetUpdateMask().GetPaths())
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to update category: %w", err))
+	}
+	return mapper.CategoryToProto(updated), nil
+}
+
+// DeleteCategory handles the DeleteCategory gRPC method
+func (h *ProductHandler) DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*emptypb.Empty, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid category ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if err := h.ProductService.DeleteCategory(ctx, id, version); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to delete category: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
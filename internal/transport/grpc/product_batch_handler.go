@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	"product-microservice/internal/service"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/status"
+)
+
+// batchItems collects the items of a batch request that passed request-level
+// validation, remembering their position so results line up with the request
+type batchItems struct {
+	allOrNothing bool
+	results      []*pb.BatchProductResult
+	indexes      []int
+}
+
+func newBatchItems(n int, allOrNothing bool) *batchItems {
+	return &batchItems{allOrNothing: allOrNothing, results: make([]*pb.BatchProductResult, n)}
+}
+
+// reject records a validation failure for item i. In all-or-nothing mode the
+// whole call fails instead.
+func (b *batchItems) reject(i int, err error) error {
+	if b.allOrNothing {
+		return toStatusError(fmt.Errorf("item %d: %w", i, err))
+	}
+	b.results[i] = &pb.BatchProductResult{Status: status.Convert(toStatusError(err)).Proto()}
+	return nil
+}
+
+// accept marks item i as valid and passed on to the service
+func (b *batchItems) accept(i int) {
+	b.indexes = append(b.indexes, i)
+}
+
+// response merges the service results for the accepted items into the response
+func (b *batchItems) response(results []service.BatchResult, err error) (*pb.BatchProductsResponse, error) {
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	for j, result := range results {
+		i := b.indexes[j]
+		if result.Err != nil {
+			b.results[i] = &pb.BatchProductResult{Status: status.Convert(toStatusError(result.Err)).Proto()}
+			continue
+		}
+		b.results[i] = &pb.BatchProductResult{Product: mapper.ProductToProto(result.Product)}
+	}
+	return &pb.BatchProductsResponse{Results: b.results}, nil
+}
+
+// BatchCreateProducts handles the BatchCreateProducts gRPC method
+func (h *ProductHandler) BatchCreateProducts(ctx context.Context, req *pb.BatchCreateProductsRequest) (*pb.BatchProductsResponse, error) {
+	items := newBatchItems(len(req.GetProducts()), req.GetAllOrNothing())
+	var products []*domain.Product
+	for i, pbProduct := range req.GetProducts() {
+		product, err := mapper.ProductFromProto(pbProduct)
+		if err != nil {
+			if err := items.reject(i, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		items.accept(i)
+		products = append(products, product)
+	}
+	if len(products) == 0 && len(req.GetProducts()) > 0 {
+		return &pb.BatchProductsResponse{Results: items.results}, nil
+	}
+
+	return items.response(h.ProductService.BatchCreateProducts(ctx, products, req.GetAllOrNothing()))
+}
+
+// BatchGetProducts handles the BatchGetProducts gRPC method
+func (h *ProductHandler) BatchGetProducts(ctx context.Context, req *pb.BatchGetProductsRequest) (*pb.BatchProductsResponse, error) {
+	items := newBatchItems(len(req.GetIds()), req.GetAllOrNothing())
+	var ids []uuid.UUID
+	for i, rawID := range req.GetIds() {
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			if err := items.reject(i, fmt.Errorf("%w: invalid product ID format: %v", domain.ErrInvalidArgument, err)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		items.accept(i)
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 && len(req.GetIds()) > 0 {
+		return &pb.BatchProductsResponse{Results: items.results}, nil
+	}
+
+	results, err := h.ProductService.BatchGetProducts(ctx, ids, req.GetAllOrNothing())
+	if err == nil {
+		for j := range results {
+			product := results[j].Product
+			if product == nil || h.canSee(ctx, product) {
+				continue
+			}
+			// Hidden products are reported as missing, as by GetProduct
+			notFound := fmt.Errorf("product with ID %s %w", product.ID, domain.ErrNotFound)
+			if req.GetAllOrNothing() {
+				return nil, toStatusError(fmt.Errorf("item %d: %w", j, notFound))
+			}
+			results[j] = service.BatchResult{Err: notFound}
+		}
+	}
+	return items.response(results, err)
+}
+
+// BatchUpdateProducts handles the BatchUpdateProducts gRPC method
+func (h *ProductHandler) BatchUpdateProducts(ctx context.Context, req *pb.BatchUpdateProductsRequest) (*pb.BatchProductsResponse, error) {
+	items := newBatchItems(len(req.GetRequests()), req.GetAllOrNothing())
+	var updates []service.ProductUpdate
+	for i, update := range req.GetRequests() {
+		product, err := productUpdateFromProto(update)
+		if err == nil {
+			err = h.checkStatusUpdate(ctx, update.GetUpdateMask().GetPaths())
+		}
+		if err != nil {
+			if err := items.reject(i, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		items.accept(i)
+		updates = append(updates, service.ProductUpdate{
+			ID:         product.ID,
+			Product:    product,
+			UpdateMask: update.GetUpdateMask().GetPaths(),
+		})
+	}
+	if len(updates) == 0 && len(req.GetRequests()) > 0 {
+		return &pb.BatchProductsResponse{Results: items.results}, nil
+	}
+
+	return items.response(h.ProductService.BatchUpdateProducts(ctx, updates, req.GetAllOrNothing()))
+}
+
+// BatchDeleteProducts handles the BatchDeleteProducts gRPC method
+func (h *ProductHandler) BatchDeleteProducts(ctx context.Context, req *pb.BatchDeleteProductsRequest) (*pb.BatchProductsResponse, error) {
+	items := newBatchItems(len(req.GetRequests()), req.GetAllOrNothing())
+	var deletions []service.ProductDeletion
+	for i, deletion := range req.GetRequests() {
+		id, err := uuid.Parse(deletion.GetId())
+		if err != nil {
+			err = fmt.Errorf("%w: invalid product ID format: %v", domain.ErrInvalidArgument, err)
+		}
+		var version int64
+		if err == nil {
+			version, err = domain.ParseETag(deletion.GetEtag())
+		}
+		if err != nil {
+			if err := items.reject(i, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		items.accept(i)
+		deletions = append(deletions, service.ProductDeletion{ID: id, Version: version})
+	}
+	if len(deletions) == 0 && len(req.GetRequests()) > 0 {
+		return &pb.BatchProductsResponse{Results: items.results}, nil
+	}
+
+	return items.response(h.ProductService.BatchDeleteProducts(ctx, deletions, req.GetAllOrNothing()))
+}
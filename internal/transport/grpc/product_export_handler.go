@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	pb "product-microservice/proto/product"
+
+	"google.golang.org/grpc"
+)
+
+// ExportProducts handles the ExportProducts gRPC method. Products are sent as
+// they are read from the database cursor, so a slow client applies
+// backpressure all the way to the query instead of buffering the catalog.
+func (h *ProductHandler) ExportProducts(req *pb.ExportProductsRequest, stream grpc.ServerStreamingServer[pb.ExportedProduct]) error {
+	if err := h.checkShowDeleted(stream.Context(), req.GetShowDeleted()); err != nil {
+		return err
+	}
+	statuses, err := h.visibleStatuses(stream.Context(), req.GetStatuses())
+	if err != nil {
+		return err
+	}
+	req.Statuses = statuses
+	err = h.ProductService.ExportProducts(stream.Context(), req, stream.Send)
+	if err != nil {
+		return toStatusError(err)
+	}
+	return nil
+}
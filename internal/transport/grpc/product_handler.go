@@ -4,16 +4,20 @@ import (
 	"context"
 	"fmt"
 	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
 	"product-microservice/internal/service"
 	pb "product-microservice/proto/product"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"time"
 	"github.com/google/uuid"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ProductHandler struct {
 	ProductService service.ProductService
+	// AdminToken is the x-admin-token metadata value that lets callers see
+	// products that are not published. No caller is an admin when it is empty.
+	AdminToken string
 	pb.UnimplementedProductServiceServer
 }
 
@@ -25,142 +29,176 @@ func NewProductHandler(productService service.ProductService) *ProductHandler {
 }
 
 func (h *ProductHandler) CreateProduct(ctx context.Context, req *pb.Product) (*pb.Product, error) {
-	createdAt := time.Now()
-	updatedAt := time.Now()
-
 	// Create a product object in the domain layer
-	domainProduct := &domain.Product{
-		ID:          uuid.New(),
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       float64(req.Price),
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
-	}
-
-	// Assign the correct product type based on the request
-	if req.ProductType != nil {
-		switch pt := req.ProductType.(type) {
-		case *pb.Product_DigitalProduct:
-			domainProduct.DigitalProduct = &domain.DigitalProduct{
-				FileSize:    pt.DigitalProduct.FileSize,
-				DownloadLink: pt.DigitalProduct.DownloadLink,
-			}
-		case *pb.Product_PhysicalProduct:
-			domainProduct.PhysicalProduct = &domain.PhysicalProduct{
-				Weight:     pt.PhysicalProduct.Weight,
-				Dimensions: pt.PhysicalProduct.Dimensions,
-			}
-		case *pb.Product_SubscriptionProduct:
-			domainProduct.SubscriptionProduct = &domain.SubscriptionProduct{
-				SubscriptionPeriod: pt.SubscriptionProduct.SubscriptionPeriod,
-				RenewalPrice:      pt.SubscriptionProduct.RenewalPrice,
-			}
-		default:
-			return nil, fmt.Errorf("unsupported product type")
-		}
+	domainProduct, err := mapper.ProductFromProto(req)
+	if err != nil {
+		return nil, toStatusError(err)
 	}
 
 	// Persist the domain product to the database
-	newProduct, err := h.ProductService.CreateProduct(domainProduct)
+	newProduct, err := h.ProductService.CreateProduct(ctx, domainProduct)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	// Return the created product as a proto response
-	return &pb.Product{
-		Id:          newProduct.ID.String(),
-		Name:        newProduct.Name,
-		Description: newProduct.Description,
-		Price:       float32(newProduct.Price),
-		CreatedAt:   timestamppb.New(createdAt),
-		UpdatedAt:   timestamppb.New(updatedAt),
-		ProductType: req.ProductType, 
-	}, nil
+	return mapper.ProductToProto(newProduct), nil
 }
 
 // gRPC handler for fetching product by ID
-func (h *ProductHandler) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.ProductResponse, error) {
-    // Convert the product ID from string to uuid.UUID
-    productID, err := uuid.Parse(req.GetId())
-    if err != nil {
-        return nil, fmt.Errorf("invalid product ID format: %v", err)
-    }
-
-    // Call the service method to get the product
-    product, err := h.ProductService.GetProductByID(productID)
-    if err != nil {
-        return nil, fmt.Errorf("failed to get product: %v", err)
-    }
+func (h *ProductHandler) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
+	// Convert the product ID from string to uuid.UUID
+	productID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
 
-    // Convert the product to the gRPC response format
-    productResp := &pb.ProductResponse{
-        Id:          product.ID.String(),
-        Name:        product.Name,
-        Description: product.Description,
-        Price:       float32(product.Price),
-        CreatedAt:   timestamppb.New(product.CreatedAt),
-        UpdatedAt:   timestamppb.New(product.UpdatedAt),
-    }
+	// Call the service method to get the product
+	product, err := h.ProductService.GetProductByID(productID)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to get product: %w", err))
+	}
+	if !h.canSee(ctx, product) {
+		// Hidden products are reported as missing so their existence does not leak
+		return nil, toStatusError(fmt.Errorf("failed to get product: product with ID %s %w", productID, domain.ErrNotFound))
+	}
+	if err := h.ProductService.LocalizeProducts(ctx, requestLocale(ctx, req.GetLocale()), product); err != nil {
+		return nil, toStatusError(err)
+	}
 
-    return productResp, nil
+	// Convert the product to the gRPC response format
+	return mapper.ProductToProto(product), nil
 }
 
-func (h *ProductHandler) UpdateProduct(ctx context.Context, req *pb.Product) (*pb.Product, error) {
-    // Convert the product ID to uuid.UUID
-    productID, err := uuid.Parse(req.GetId())
-    if err != nil {
-        return nil, fmt.Errorf("invalid product ID format: %v", err)
-    }
-
-    // Convert the gRPC product to the domain product
-    domainProduct := &domain.Product{
-        ID:          productID,
-        Name:        req.GetName(),
-        Description: req.GetDescription(),
-        Price:       float64(req.GetPrice()),
-    }
+func (h *ProductHandler) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.Product, error) {
+	if err := h.checkStatusUpdate(ctx, req.GetUpdateMask().GetPaths()); err != nil {
+		return nil, err
+	}
+	// Convert the gRPC product to the domain product
+	domainProduct, err := productUpdateFromProto(req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
 
-    // Call the service method to update the product
-    updatedProduct, err := h.ProductService.UpdateProduct(productID, domainProduct)
-    if err != nil {
-        return nil, fmt.Errorf("failed to update product: %v", err)
-    }
+	// Call the service method to update the product
+	updatedProduct, err := h.ProductService.UpdateProduct(ctx, domainProduct.ID, domainProduct, req.GetUpdateMask().GetPaths())
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to update product: %w", err))
+	}
 
-    // Convert the updated product to gRPC response format
-    return &pb.Product{
-        Id:          updatedProduct.ID.String(),
-        Name:        updatedProduct.Name,
-        Description: updatedProduct.Description,
-        Price:       float32(updatedProduct.Price),
-    }, nil
+	// Convert the updated product to gRPC response format
+	return mapper.ProductToProto(updatedProduct), nil
 }
 
 func (h *ProductHandler) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*emptypb.Empty, error) {
     // Convert the product ID from string to uuid.UUID
     productID, err := uuid.Parse(req.GetId())
     if err != nil {
-        return nil, fmt.Errorf("invalid product ID format: %v", err)
+        return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+    }
+
+    version, err := domain.ParseETag(req.GetEtag())
+    if err != nil {
+        return nil, toStatusError(err)
     }
 
     // Call the service method to delete the product
-    err = h.ProductService.DeleteProduct(productID)
+    err = h.ProductService.DeleteProduct(productID, version)
     if err != nil {
-        return nil, fmt.Errorf("failed to delete product: %v", err)
+        return nil, toStatusError(fmt.Errorf("failed to delete product: %w", err))
     }
 
     // Return an empty response
     return &emptypb.Empty{}, nil
 }
 
+// productUpdateFromProto converts an update request to the domain product
+// carrying the target ID and the version the client read
+func productUpdateFromProto(req *pb.UpdateProductRequest) (*domain.Product, error) {
+	productID, err := uuid.Parse(req.GetProduct().GetId())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid product ID format: %v", domain.ErrInvalidArgument, err)
+	}
+
+	domainProduct, err := mapper.ProductFromProto(req.GetProduct())
+	if err != nil {
+		return nil, err
+	}
+	domainProduct.ID = productID
+	if domainProduct.Version, err = domain.ParseETag(req.GetProduct().GetEtag()); err != nil {
+		return nil, err
+	}
+	return domainProduct, nil
+}
+
+// RestoreProduct handles the RestoreProduct gRPC method
+func (h *ProductHandler) RestoreProduct(ctx context.Context, req *pb.RestoreProductRequest) (*pb.Product, error) {
+	if err := h.requireAdmin(ctx, "restore products"); err != nil {
+		return nil, err
+	}
+	productID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	product, err := h.ProductService.RestoreProduct(productID, version)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to restore product: %w", err))
+	}
+	return mapper.ProductToProto(product), nil
+}
+
+// PurgeProduct handles the PurgeProduct gRPC method
+func (h *ProductHandler) PurgeProduct(ctx context.Context, req *pb.PurgeProductRequest) (*emptypb.Empty, error) {
+	if err := h.requireAdmin(ctx, "purge products"); err != nil {
+		return nil, err
+	}
+	productID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+
+	if err := h.ProductService.PurgeProduct(ctx, productID); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to purge product: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
+
 // ListProducts handles the ListProducts gRPC method
 func (h *ProductHandler) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
 	// Call the service to get the list of products
-	response, err := h.ProductService.ListProducts(ctx, req)
+	req.Locale = requestLocale(ctx, req.GetLocale())
+	if err := h.checkShowDeleted(ctx, req.GetShowDeleted()); err != nil {
+		return nil, err
+	}
+	statuses, err := h.visibleStatuses(ctx, req.GetStatuses())
 	if err != nil {
 		return nil, err
 	}
+	req.Statuses = statuses
+	response, err := h.ProductService.ListProducts(ctx, req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
 
 	// Return the list of products
 	return response, nil
-}
\ No newline at end of file
+}
+// SearchProducts handles the SearchProducts gRPC method
+func (h *ProductHandler) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	req.Locale = requestLocale(ctx, req.GetLocale())
+	statuses, err := h.visibleStatuses(ctx, req.GetStatuses())
+	if err != nil {
+		return nil, err
+	}
+	req.Statuses = statuses
+	response, err := h.ProductService.SearchProducts(ctx, req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return response, nil
+}
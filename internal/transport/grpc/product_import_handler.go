@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"errors"
+	"io"
+	"product-microservice/internal/mapper"
+	"product-microservice/internal/productio"
+	pb "product-microservice/proto/product"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// importFormats maps the wire enum onto the file formats of productio
+var importFormats = map[pb.ImportFormat]productio.Format{
+	pb.ImportFormat_IMPORT_FORMAT_CSV:   productio.FormatCSV,
+	pb.ImportFormat_IMPORT_FORMAT_JSONL: productio.FormatJSONL,
+}
+
+// ImportProducts handles the ImportProducts gRPC method. The streamed chunks
+// are piped into a row reader, and every row is upserted on its own so a bad
+// row never blocks the rest of the file.
+func (h *ProductHandler) ImportProducts(stream grpc.ClientStreamingServer[pb.ImportProductsRequest, pb.ImportProductsResponse]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		if err == io.EOF {
+			return status.Error(codes.InvalidArgument, "import stream is empty")
+		}
+		return err
+	}
+	format, ok := importFormats[first.GetFormat()]
+	if !ok {
+		return status.Error(codes.InvalidArgument, "the first message must set a supported format")
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeReader.Close()
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				pipeWriter.Close()
+				return
+			}
+			if err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+			if req.GetFormat() != pb.ImportFormat_IMPORT_FORMAT_UNSPECIFIED {
+				pipeWriter.CloseWithError(status.Error(codes.InvalidArgument, "format can only be set in the first message"))
+				return
+			}
+			if _, err := pipeWriter.Write(req.GetData()); err != nil {
+				return
+			}
+		}
+	}()
+
+	reader, err := productio.NewReader(format, pipeReader)
+	if err != nil {
+		return importReadError(err)
+	}
+
+	summary := &pb.ImportProductsResponse{}
+	fail := func(line int, sku string, err error) {
+		summary.Failed++
+		summary.Rows = append(summary.Rows, &pb.ImportRowResult{
+			Line:        int32(line),
+			ExternalSku: sku,
+			Outcome:     pb.ImportRowResult_FAILED,
+			Error:       err.Error(),
+		})
+	}
+
+	for {
+		row, line, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var rowErr *productio.RowError
+			if errors.As(err, &rowErr) {
+				fail(rowErr.Line, "", rowErr.Err)
+				continue
+			}
+			return importReadError(err)
+		}
+
+		product, err := mapper.ProductFromProto(row)
+		if err != nil {
+			fail(line, row.GetExternalSku(), err)
+			continue
+		}
+		created, product, err := h.ProductService.ImportProduct(stream.Context(), product)
+		if err != nil {
+			fail(line, row.GetExternalSku(), err)
+			continue
+		}
+
+		outcome := pb.ImportRowResult_UPDATED
+		if created {
+			outcome = pb.ImportRowResult_CREATED
+			summary.Created++
+		} else {
+			summary.Updated++
+		}
+		summary.Rows = append(summary.Rows, &pb.ImportRowResult{
+			Line:        int32(line),
+			ExternalSku: row.GetExternalSku(),
+			Outcome:     outcome,
+			ProductId:   product.ID.String(),
+		})
+	}
+
+	return stream.SendAndClose(summary)
+}
+
+// importReadError converts a fatal read error. Errors from the stream itself
+// already carry a status; anything else means the file is unreadable.
+func importReadError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.InvalidArgument, "failed to read import file: %v", err)
+}
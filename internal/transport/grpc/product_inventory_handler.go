@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseStockItem parses the IDs naming an item and the warehouse holding it.
+// An empty warehouse ID is left as uuid.Nil for the service to reject or allocate.
+func parseStockItem(productID, variantID, warehouseID string) (domain.StockItem, error) {
+	var item domain.StockItem
+	var err error
+	if item.ProductID, err = uuid.Parse(productID); err != nil {
+		return item, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	if item.VariantID, err = mapper.OptionalUUID(variantID); err != nil {
+		return item, status.Errorf(codes.InvalidArgument, "invalid variant ID format: %v", err)
+	}
+	if warehouseID != "" {
+		if item.WarehouseID, err = uuid.Parse(warehouseID); err != nil {
+			return item, status.Errorf(codes.InvalidArgument, "invalid warehouse ID format: %v", err)
+		}
+	}
+	return item, nil
+}
+
+// AdjustStock handles the AdjustStock gRPC method
+func (h *ProductHandler) AdjustStock(ctx context.Context, req *pb.AdjustStockRequest) (*pb.StockLevel, error) {
+	item, err := parseStockItem(req.GetProductId(), req.GetVariantId(), req.GetWarehouseId())
+	if err != nil {
+		return nil, err
+	}
+	level, err := h.ProductService.AdjustStock(ctx, item, req.GetDelta(), req.GetReason())
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to adjust stock: %w", err))
+	}
+	return mapper.StockLevelToProto(level), nil
+}
+
+// GetStock handles the GetStock gRPC method
+func (h *ProductHandler) GetStock(ctx context.Context, req *pb.GetStockRequest) (*pb.GetStockResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	variantID, err := mapper.OptionalUUID(req.GetVariantId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid variant ID format: %v", err)
+	}
+	if err := h.checkVisible(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	levels, err := h.ProductService.GetStock(ctx, productID, variantID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.GetStockResponse{Levels: make([]*pb.StockLevel, len(levels))}
+	for i := range levels {
+		response.Levels[i] = mapper.StockLevelToProto(&levels[i])
+		response.OnHand += levels[i].OnHand
+		response.Reserved += levels[i].Reserved
+		response.Available += levels[i].Available()
+	}
+	return response, nil
+}
+
+// ReserveStock handles the ReserveStock gRPC method
+func (h *ProductHandler) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.StockReservation, error) {
+	item, err := parseStockItem(req.GetProductId(), req.GetVariantId(), req.GetWarehouseId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetTtl() != nil {
+		if err := req.GetTtl().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ttl: %v", err)
+		}
+	}
+
+	reservation := &domain.StockReservation{StockItem: item, Quantity: req.GetQuantity(), Reference: req.GetReference()}
+	reservation, err = h.ProductService.ReserveStock(ctx, reservation, req.GetTtl().AsDuration())
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to reserve stock: %w", err))
+	}
+	return mapper.StockReservationToProto(reservation), nil
+}
+
+// ReleaseReservation handles the ReleaseReservation gRPC method
+func (h *ProductHandler) ReleaseReservation(ctx context.Context, req *pb.ReleaseReservationRequest) (*pb.StockReservation, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid reservation ID format: %v", err)
+	}
+	reservation, err := h.ProductService.ReleaseReservation(ctx, id)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to release reservation: %w", err))
+	}
+	return mapper.StockReservationToProto(reservation), nil
+}
+
+// CommitReservation handles the CommitReservation gRPC method
+func (h *ProductHandler) CommitReservation(ctx context.Context, req *pb.CommitReservationRequest) (*pb.StockReservation, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid reservation ID format: %v", err)
+	}
+	reservation, err := h.ProductService.CommitReservation(ctx, id)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to commit reservation: %w", err))
+	}
+	return mapper.StockReservationToProto(reservation), nil
+}
+
+// ListStockMovements handles the ListStockMovements gRPC method
+func (h *ProductHandler) ListStockMovements(ctx context.Context, req *pb.ListStockMovementsRequest) (*pb.ListStockMovementsResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	variantID, err := mapper.OptionalUUID(req.GetVariantId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid variant ID format: %v", err)
+	}
+	warehouseID, err := mapper.OptionalUUID(req.GetWarehouseId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid warehouse ID format: %v", err)
+	}
+
+	movements, nextPageToken, err := h.ProductService.ListStockMovements(ctx, productID, variantID, warehouseID, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.ListStockMovementsResponse{
+		Movements:     make([]*pb.StockMovement, len(movements)),
+		NextPageToken: nextPageToken,
+	}
+	for i := range movements {
+		response.Movements[i] = mapper.StockMovementToProto(&movements[i])
+	}
+	return response, nil
+}
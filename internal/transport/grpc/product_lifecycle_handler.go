@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	"product-microservice/internal/service"
+	pb "product-microservice/proto/product"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// isAdmin reports whether the request carries the admin token
+func (h *ProductHandler) isAdmin(ctx context.Context) bool {
+	return hasAdminToken(ctx, h.AdminToken)
+}
+
+// hasAdminToken reports whether the request carries adminToken in its
+// x-admin-token metadata. Nobody is an admin when no token is configured.
+func hasAdminToken(ctx context.Context, adminToken string) bool {
+	if adminToken == "" {
+		return false
+	}
+	for _, token := range metadata.ValueFromIncomingContext(ctx, "x-admin-token") {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// requireAdmin rejects callers that are not admins with PermissionDenied,
+// naming the action they are not allowed to take
+func (h *ProductHandler) requireAdmin(ctx context.Context, action string) error {
+	if !h.isAdmin(ctx) {
+		return status.Errorf(codes.PermissionDenied, "only admins can %s", action)
+	}
+	return nil
+}
+
+// checkStatusUpdate rejects update masks that move a product through its
+// lifecycle unless the caller is an admin
+func (h *ProductHandler) checkStatusUpdate(ctx context.Context, paths []string) error {
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "status" {
+			return h.requireAdmin(ctx, "change the status of products")
+		}
+	}
+	return nil
+}
+
+// visibleStatuses returns the lifecycle states a listing by the caller is
+// restricted to. Admins see whatever they ask for; everyone else only sees
+// published products.
+func (h *ProductHandler) visibleStatuses(ctx context.Context, requested []pb.ProductStatus) ([]pb.ProductStatus, error) {
+	if h.isAdmin(ctx) {
+		return requested, nil
+	}
+	for _, s := range requested {
+		if s != pb.ProductStatus_PRODUCT_STATUS_PUBLISHED {
+			return nil, status.Error(codes.PermissionDenied, "only admins can list products that are not published")
+		}
+	}
+	return []pb.ProductStatus{pb.ProductStatus_PRODUCT_STATUS_PUBLISHED}, nil
+}
+
+// canSee reports whether the caller may read a product. Admins see every
+// product; everyone else only sees public ones.
+func (h *ProductHandler) canSee(ctx context.Context, product *domain.Product) bool {
+	return h.isAdmin(ctx) || isPublic(product)
+}
+
+// isPublic reports whether a product is published and not deleted
+func isPublic(product *domain.Product) bool {
+	return product.Status == domain.ProductStatusPublished && !product.DeletedAt.Valid
+}
+
+// checkVisible returns NotFound if the caller may not see a product, for
+// reads of data that belongs to it such as its variants, media or stock
+func (h *ProductHandler) checkVisible(ctx context.Context, productID uuid.UUID) error {
+	return checkProductVisible(h.isAdmin(ctx), h.ProductService, productID)
+}
+
+// checkProductVisible loads a product for a caller that is not an admin and
+// reports it as missing unless it is public, so its existence does not leak
+func checkProductVisible(admin bool, products service.ProductService, productID uuid.UUID) error {
+	if admin {
+		return nil
+	}
+	product, err := products.GetProductByID(productID)
+	if err != nil {
+		return toStatusError(err)
+	}
+	if !isPublic(product) {
+		return toStatusError(fmt.Errorf("product with ID %s %w", productID, domain.ErrNotFound))
+	}
+	return nil
+}
+
+// checkShowDeleted rejects requests for soft deleted products by callers
+// that are not admins
+func (h *ProductHandler) checkShowDeleted(ctx context.Context, showDeleted bool) error {
+	if showDeleted && !h.isAdmin(ctx) {
+		return status.Error(codes.PermissionDenied, "only admins can see deleted products")
+	}
+	return nil
+}
+
+// PublishProduct handles the PublishProduct gRPC method
+func (h *ProductHandler) PublishProduct(ctx context.Context, req *pb.PublishProductRequest) (*pb.Product, error) {
+	if err := h.requireAdmin(ctx, "publish products"); err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	var publishAt, unpublishAt *time.Time
+	if req.GetPublishAt() != nil {
+		t := req.GetPublishAt().AsTime()
+		publishAt = &t
+	}
+	if req.GetUnpublishAt() != nil {
+		t := req.GetUnpublishAt().AsTime()
+		unpublishAt = &t
+	}
+
+	product, err := h.ProductService.PublishProduct(ctx, id, version, publishAt, unpublishAt)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to publish product: %w", err))
+	}
+	return mapper.ProductToProto(product), nil
+}
+
+// ArchiveProduct handles the ArchiveProduct gRPC method
+func (h *ProductHandler) ArchiveProduct(ctx context.Context, req *pb.ArchiveProductRequest) (*pb.Product, error) {
+	if err := h.requireAdmin(ctx, "archive products"); err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	product, err := h.ProductService.ArchiveProduct(ctx, id, version)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to archive product: %w", err))
+	}
+	return mapper.ProductToProto(product), nil
+}
@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// mediaUploadReader reads the content chunks of an UploadProductMedia stream
+// as they arrive
+type mediaUploadReader struct {
+	stream grpc.ClientStreamingServer[pb.UploadProductMediaRequest, pb.ProductMedia]
+	chunk  []byte
+}
+
+// Read implements io.Reader, returning io.EOF once the client closes the stream
+func (r *mediaUploadReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetMetadata() != nil {
+			return 0, status.Error(codes.InvalidArgument, "metadata can only be set in the first message")
+		}
+		r.chunk = req.GetData()
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+// UploadProductMedia handles the UploadProductMedia gRPC method. The content
+// is streamed to the blob store without being buffered in full.
+func (h *ProductHandler) UploadProductMedia(stream grpc.ClientStreamingServer[pb.UploadProductMediaRequest, pb.ProductMedia]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		if err == io.EOF {
+			return status.Error(codes.InvalidArgument, "upload stream is empty")
+		}
+		return err
+	}
+	metadata := first.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "the first message must set the metadata")
+	}
+	productID, err := uuid.Parse(metadata.GetProductId())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+
+	media, err := h.ProductService.UploadProductMedia(stream.Context(), &domain.ProductMedia{
+		ProductID:   productID,
+		FileName:    metadata.GetFileName(),
+		ContentType: metadata.GetContentType(),
+		AltText:     metadata.GetAltText(),
+	}, &mediaUploadReader{stream: stream})
+	if err != nil {
+		return toStatusError(fmt.Errorf("failed to upload media: %w", err))
+	}
+	return stream.SendAndClose(mapper.ProductMediaToProto(media))
+}
+
+// ListProductMedia handles the ListProductMedia gRPC method
+func (h *ProductHandler) ListProductMedia(ctx context.Context, req *pb.ListProductMediaRequest) (*pb.ListProductMediaResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	if err := h.checkVisible(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	media, err := h.ProductService.ListProductMedia(ctx, productID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ListProductMediaResponse{Media: mapper.ProductMediaListToProto(media)}, nil
+}
+
+// ReorderProductMedia handles the ReorderProductMedia gRPC method
+func (h *ProductHandler) ReorderProductMedia(ctx context.Context, req *pb.ReorderProductMediaRequest) (*pb.ListProductMediaResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	mediaIDs := make([]uuid.UUID, len(req.GetMediaIds()))
+	for i, id := range req.GetMediaIds() {
+		if mediaIDs[i], err = uuid.Parse(id); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid media ID format: %v", err)
+		}
+	}
+
+	media, err := h.ProductService.ReorderProductMedia(ctx, productID, mediaIDs)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to reorder media: %w", err))
+	}
+	return &pb.ListProductMediaResponse{Media: mapper.ProductMediaListToProto(media)}, nil
+}
+
+// DeleteProductMedia handles the DeleteProductMedia gRPC method
+func (h *ProductHandler) DeleteProductMedia(ctx context.Context, req *pb.DeleteProductMediaRequest) (*emptypb.Empty, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid media ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if err := h.ProductService.DeleteProductMedia(ctx, id, version); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to delete media: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
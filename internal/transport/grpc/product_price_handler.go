@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreatePriceListEntry handles the CreatePriceListEntry gRPC method
+func (h *ProductHandler) CreatePriceListEntry(ctx context.Context, req *pb.PriceListEntry) (*pb.PriceListEntry, error) {
+	entry, err := mapper.PriceListEntryFromProto(req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	created, err := h.ProductService.CreatePriceListEntry(ctx, entry)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to create price list entry: %w", err))
+	}
+	return mapper.PriceListEntryToProto(created), nil
+}
+
+// ListPriceListEntries handles the ListPriceListEntries gRPC method
+func (h *ProductHandler) ListPriceListEntries(ctx context.Context, req *pb.ListPriceListEntriesRequest) (*pb.ListPriceListEntriesResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	planID, err := mapper.OptionalUUID(req.GetSubscriptionPlanId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid subscription plan ID format: %v", err)
+	}
+
+	entries, err := h.ProductService.ListPriceListEntries(ctx, productID, planID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.ListPriceListEntriesResponse{Entries: make([]*pb.PriceListEntry, len(entries))}
+	for i := range entries {
+		response.Entries[i] = mapper.PriceListEntryToProto(&entries[i])
+	}
+	return response, nil
+}
+
+// DeletePriceListEntry handles the DeletePriceListEntry gRPC method
+func (h *ProductHandler) DeletePriceListEntry(ctx context.Context, req *pb.DeletePriceListEntryRequest) (*emptypb.Empty, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid price list entry ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if err := h.ProductService.DeletePriceListEntry(ctx, id, version); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to delete price list entry: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// GetPrice handles the GetPrice gRPC method
+func (h *ProductHandler) GetPrice(ctx context.Context, req *pb.GetPriceRequest) (*pb.ResolvedPrice, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	planID, err := mapper.OptionalUUID(req.GetSubscriptionPlanId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid subscription plan ID format: %v", err)
+	}
+	if err := h.checkVisible(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	query := domain.PriceQuery{
+		ProductID:          productID,
+		SubscriptionPlanID: planID,
+		Region:             req.GetRegion(),
+		Currency:           req.GetCurrencyCode(),
+	}
+	if req.GetAtTime() != nil {
+		query.At = req.GetAtTime().AsTime()
+	}
+
+	resolved, err := h.ProductService.GetPrice(ctx, query)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return mapper.ResolvedPriceToProto(resolved), nil
+}
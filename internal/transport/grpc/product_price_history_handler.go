@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListPriceHistory handles the ListPriceHistory gRPC method
+func (h *ProductHandler) ListPriceHistory(ctx context.Context, req *pb.ListPriceHistoryRequest) (*pb.ListPriceHistoryResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	planID, err := mapper.OptionalUUID(req.GetSubscriptionPlanId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid subscription plan ID format: %v", err)
+	}
+	if err := h.checkVisible(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	changes, nextPageToken, err := h.ProductService.ListPriceHistory(ctx, productID, planID, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.ListPriceHistoryResponse{
+		Entries:       make([]*pb.PriceHistoryEntry, len(changes)),
+		NextPageToken: nextPageToken,
+	}
+	for i := range changes {
+		response.Entries[i] = mapper.PriceChangeToProto(&changes[i])
+	}
+	return response, nil
+}
+
+// SchedulePriceChange handles the SchedulePriceChange gRPC method
+func (h *ProductHandler) SchedulePriceChange(ctx context.Context, req *pb.ScheduledPriceChange) (*pb.ScheduledPriceChange, error) {
+	change, err := mapper.ScheduledPriceChangeFromProto(req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	scheduled, err := h.ProductService.SchedulePriceChange(ctx, change)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to schedule price change: %w", err))
+	}
+	return mapper.ScheduledPriceChangeToProto(scheduled), nil
+}
+
+// ListScheduledPriceChanges handles the ListScheduledPriceChanges gRPC method
+func (h *ProductHandler) ListScheduledPriceChanges(ctx context.Context, req *pb.ListScheduledPriceChangesRequest) (*pb.ListScheduledPriceChangesResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	planID, err := mapper.OptionalUUID(req.GetSubscriptionPlanId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid subscription plan ID format: %v", err)
+	}
+
+	changes, err := h.ProductService.ListScheduledPriceChanges(ctx, productID, planID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.ListScheduledPriceChangesResponse{Changes: make([]*pb.ScheduledPriceChange, len(changes))}
+	for i := range changes {
+		response.Changes[i] = mapper.ScheduledPriceChangeToProto(&changes[i])
+	}
+	return response, nil
+}
+
+// CancelScheduledPriceChange handles the CancelScheduledPriceChange gRPC method
+func (h *ProductHandler) CancelScheduledPriceChange(ctx context.Context, req *pb.CancelScheduledPriceChangeRequest) (*pb.ScheduledPriceChange, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid scheduled price change ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	cancelled, err := h.ProductService.CancelScheduledPriceChange(ctx, id, version)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to cancel scheduled price change: %w", err))
+	}
+	return mapper.ScheduledPriceChangeToProto(cancelled), nil
+}
@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/mapper"
+	"product-microservice/internal/service"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EstimateShipping handles the EstimateShipping gRPC method
+func (h *ProductHandler) EstimateShipping(ctx context.Context, req *pb.EstimateShippingRequest) (*pb.EstimateShippingResponse, error) {
+	lines := make([]service.ShippingLine, len(req.GetItems()))
+	for i, item := range req.GetItems() {
+		productID, err := uuid.Parse(item.GetProductId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+		}
+		variantID, err := mapper.OptionalUUID(item.GetVariantId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid variant ID format: %v", err)
+		}
+		if err := h.checkVisible(ctx, productID); err != nil {
+			return nil, err
+		}
+		lines[i] = service.ShippingLine{ProductID: productID, VariantID: variantID, Quantity: item.GetQuantity()}
+	}
+	warehouseID, err := mapper.OptionalUUID(req.GetWarehouseId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid warehouse ID format: %v", err)
+	}
+
+	estimate, err := h.ProductService.EstimateShipping(ctx, lines, req.GetDestination().GetCountryCode(), warehouseID)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to estimate shipping: %w", err))
+	}
+	return mapper.ShippingEstimateToProto(estimate), nil
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// requestLocale returns the locale a request asks for, taken from its locale
+// field or else from the accept-language metadata
+func requestLocale(ctx context.Context, locale string) string {
+	if locale != "" {
+		return locale
+	}
+	return strings.Join(metadata.ValueFromIncomingContext(ctx, "accept-language"), ",")
+}
+
+// UpsertProductTranslations handles the UpsertProductTranslations gRPC method
+func (h *ProductHandler) UpsertProductTranslations(ctx context.Context, req *pb.UpsertProductTranslationsRequest) (*pb.ListProductTranslationsResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	translations := make([]domain.ProductTranslation, len(req.GetTranslations()))
+	for i, translation := range req.GetTranslations() {
+		translations[i] = mapper.ProductTranslationFromProto(translation)
+	}
+
+	stored, err := h.ProductService.UpsertProductTranslations(ctx, productID, translations)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to upsert translations: %w", err))
+	}
+	return &pb.ListProductTranslationsResponse{Translations: mapper.ProductTranslationsToProto(stored)}, nil
+}
+
+// ListProductTranslations handles the ListProductTranslations gRPC method
+func (h *ProductHandler) ListProductTranslations(ctx context.Context, req *pb.ListProductTranslationsRequest) (*pb.ListProductTranslationsResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	if err := h.checkVisible(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	translations, err := h.ProductService.ListProductTranslations(ctx, productID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ListProductTranslationsResponse{Translations: mapper.ProductTranslationsToProto(translations)}, nil
+}
+
+// DeleteProductTranslation handles the DeleteProductTranslation gRPC method
+func (h *ProductHandler) DeleteProductTranslation(ctx context.Context, req *pb.DeleteProductTranslationRequest) (*emptypb.Empty, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+
+	if err := h.ProductService.DeleteProductTranslation(ctx, productID, req.GetLocale()); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to delete translation: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
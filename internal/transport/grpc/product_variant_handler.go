@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateProductVariant handles the CreateProductVariant gRPC method
+func (h *ProductHandler) CreateProductVariant(ctx context.Context, req *pb.ProductVariant) (*pb.ProductVariant, error) {
+	variant, err := mapper.ProductVariantFromProto(req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	created, err := h.ProductService.CreateProductVariant(ctx, variant)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to create product variant: %w", err))
+	}
+	return mapper.ProductVariantToProto(created), nil
+}
+
+// GetProductVariant handles the GetProductVariant gRPC method, looking the
+// variant up by ID or by SKU
+func (h *ProductHandler) GetProductVariant(ctx context.Context, req *pb.GetProductVariantRequest) (*pb.ProductVariant, error) {
+	var variant *domain.ProductVariant
+	var err error
+	switch key := req.GetKey().(type) {
+	case *pb.GetProductVariantRequest_Id:
+		id, parseErr := uuid.Parse(key.Id)
+		if parseErr != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid product variant ID format: %v", parseErr)
+		}
+		variant, err = h.ProductService.GetProductVariant(ctx, id)
+	case *pb.GetProductVariantRequest_Sku:
+		variant, err = h.ProductService.GetProductVariantBySKU(ctx, key.Sku)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "either id or sku is required")
+	}
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := h.checkVisible(ctx, variant.ProductID); err != nil {
+		return nil, err
+	}
+	return mapper.ProductVariantToProto(variant), nil
+}
+
+// ListProductVariants handles the ListProductVariants gRPC method
+func (h *ProductHandler) ListProductVariants(ctx context.Context, req *pb.ListProductVariantsRequest) (*pb.ListProductVariantsResponse, error) {
+	productID, err := uuid.Parse(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product ID format: %v", err)
+	}
+	if err := h.checkVisible(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	variants, err := h.ProductService.ListProductVariants(ctx, productID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.ListProductVariantsResponse{Variants: make([]*pb.ProductVariant, len(variants))}
+	for i := range variants {
+		response.Variants[i] = mapper.ProductVariantToProto(&variants[i])
+	}
+	return response, nil
+}
+
+// UpdateProductVariant handles the UpdateProductVariant gRPC method
+func (h *ProductHandler) UpdateProductVariant(ctx context.Context, req *pb.UpdateProductVariantRequest) (*pb.ProductVariant, error) {
+	id, update, err := mapper.ProductVariantUpdateFromProto(req.GetVariant())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	updated, err := h.ProductService.UpdateProductVariant(ctx, id, update, req.GetUpdateMask().GetPaths())
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to update product variant: %w", err))
+	}
+	return mapper.ProductVariantToProto(updated), nil
+}
+
+// DeleteProductVariant handles the DeleteProductVariant gRPC method
+func (h *ProductHandler) DeleteProductVariant(ctx context.Context, req *pb.DeleteProductVariantRequest) (*emptypb.Empty, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid product variant ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if err := h.ProductService.DeleteProductVariant(ctx, id, version); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to delete product variant: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"product-microservice/internal/domain"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+// WatchProducts handles the WatchProducts gRPC method. The stream stays open
+// until the client cancels it; clients that reconnect pass the resume_token
+// of the last event they processed.
+func (h *ProductHandler) WatchProducts(req *pb.WatchProductsRequest, stream grpc.ServerStreamingServer[pb.ProductEvent]) error {
+	send := stream.Send
+	if !h.isAdmin(stream.Context()) {
+		send = func(event *pb.ProductEvent) error {
+			event, err := h.publicEvent(stream.Context(), event)
+			if err != nil || event == nil {
+				return err
+			}
+			return stream.Send(event)
+		}
+	}
+	err := h.ProductService.WatchProducts(stream.Context(), req, send)
+	if err != nil {
+		return toStatusError(err)
+	}
+	return nil
+}
+
+// publicEvent returns an event as callers that are not admins see it, or nil
+// if they do not see it at all. A product that is not published, or is
+// deleted, is reported as deleted with only its id so mirrors of the public
+// catalog drop it, and changes to the plans of such products are left out.
+func (h *ProductHandler) publicEvent(ctx context.Context, event *pb.ProductEvent) (*pb.ProductEvent, error) {
+	switch resource := event.GetResource().(type) {
+	case *pb.ProductEvent_Product:
+		product := resource.Product
+		if product.GetStatus() == pb.ProductStatus_PRODUCT_STATUS_PUBLISHED && product.GetDeletedAt() == nil {
+			return event, nil
+		}
+		return &pb.ProductEvent{
+			Type:        pb.ProductEvent_DELETED,
+			ResumeToken: event.GetResumeToken(),
+			EventTime:   event.GetEventTime(),
+			ProductId:   event.GetProductId(),
+			Resource:    &pb.ProductEvent_Product{Product: &pb.Product{Id: event.GetProductId()}},
+		}, nil
+	case *pb.ProductEvent_SubscriptionPlan:
+		productID, err := uuid.Parse(event.GetProductId())
+		if err != nil {
+			return nil, nil
+		}
+		product, err := h.ProductService.GetProductByID(productID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !h.canSee(ctx, product) {
+			return nil, nil
+		}
+	}
+	return event, nil
+}
@@ -3,7 +3,8 @@ package grpc
 import (
 	"context"
 	"log"
-	"math"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
 	"product-microservice/internal/service"
 	pb "product-microservice/proto/subscription"
 
@@ -19,6 +20,9 @@ import (
 type SubscriptionHandler struct {
 	subscriptionService service.SubscriptionService
 	productService      service.ProductService
+	// AdminToken is the x-admin-token of callers that may read the plans of
+	// products that are not public, as in ProductHandler
+	AdminToken string
 	pb.UnimplementedSubscriptionServiceServer
 }
 
@@ -30,17 +34,17 @@ func NewSubscriptionHandler(subscriptionService service.SubscriptionService, pro
 	}
 }
 
-// CreateSubscription handles the gRPC request to create a subscription plan
-func (h *SubscriptionHandler) CreateSubscription(ctx context.Context, req *pb.CreateSubscriptionPlanRequest) (*pb.CreateSubscriptionPlanResponse, error) {
-	// Fetch product by name
+// CreateSubscriptionPlan handles the gRPC request to create a subscription plan
+func (h *SubscriptionHandler) CreateSubscriptionPlan(ctx context.Context, req *pb.CreateSubscriptionPlanRequest) (*pb.CreateSubscriptionPlanResponse, error) {
+	// Fetch the product the plan belongs to
 	existingProduct, err := h.productService.FindProductById(ctx, req.GetProductId())
 	if err != nil {
-		log.Printf("Failed to fetch product by name: %v", err)
+		log.Printf("Failed to fetch product by ID: %v", err)
 		return nil, status.Errorf(codes.NotFound, "Product not found: %v", err)
 	}
 
 	if existingProduct == nil {
-		log.Printf("No product found with the given name: %s", req.GetProductId())
+		log.Printf("No product found with the given ID: %s", req.GetProductId())
 		return nil, status.Errorf(codes.NotFound, "Product not found")
 	}
 
@@ -48,21 +52,15 @@ func (h *SubscriptionHandler) CreateSubscription(ctx context.Context, req *pb.Cr
 	duration := int(req.GetDurationDays())
 
 	// Create a new subscription plan via service layer
-	plan, err := h.subscriptionService.CreateSubscriptionPlan(ctx, existingProduct.ID, req.GetPlanName(), duration, float64(req.GetPrice()))
+	plan, err := h.subscriptionService.CreateSubscriptionPlan(ctx, existingProduct.ID, req.GetPlanName(), duration, mapper.MoneyFromProto(req.GetPrice()))
 	if err != nil {
 		log.Printf("Failed to create subscription plan: %v", err)
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	// Return the created plan as part of the response
 	return &pb.CreateSubscriptionPlanResponse{
-		SubscriptionPlan: &pb.SubscriptionPlan{
-			Id:           plan.ID.String(),
-			ProductId:    plan.ProductID.String(),
-			PlanName:     plan.PlanName,
-			Price:        float32(plan.Price),
-			DurationDays: int32(plan.Duration),
-		},
+		SubscriptionPlan: subscriptionPlanToProto(plan),
 	}, nil
 }
 
@@ -79,38 +77,64 @@ func (h *SubscriptionHandler) GetSubscriptionPlan(ctx context.Context, req *pb.G
 	subscriptionPlan, err := h.subscriptionService.GetSubscriptionPlanByID(ctx, subscriptionID)
 	if err != nil {
 		log.Printf("Failed to fetch subscription plan: %v", err)
-		return nil, status.Errorf(codes.NotFound, "Subscription plan not found")
+		return nil, toStatusError(err)
+	}
+	// Plans of hidden products are as missing as the products themselves
+	if err := checkProductVisible(hasAdminToken(ctx, h.AdminToken), h.productService, subscriptionPlan.ProductID); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.NotFound, "subscription plan with ID %s not found", subscriptionID)
+		}
+		return nil, err
 	}
 
 	// Return the fetched subscription plan in the response
-	return &pb.SubscriptionPlan{
-		Id:           subscriptionPlan.ID.String(),
-		ProductId:    subscriptionPlan.ProductID.String(),
-		PlanName:     subscriptionPlan.PlanName,
-		Price:        float32(subscriptionPlan.Price),
-		DurationDays: int32(subscriptionPlan.Duration),
-	}, nil
+	return subscriptionPlanToProto(subscriptionPlan), nil
 }
 
-// ListSubscriptionPlans handles the gRPC request to list all subscription plans
+// ListSubscriptionPlans handles the gRPC request to list subscription plans,
+// either all of them or only those of the product given by productId
 func (h *SubscriptionHandler) ListSubscriptionPlans(ctx context.Context, req *pb.ListSubscriptionPlansRequest) (*pb.ListSubscriptionPlansResponse, error) {
-	// Fetch all subscription plans
-	subscriptionPlans, err := h.subscriptionService.ListSubscriptionPlans(ctx)
+	var productID uuid.UUID
+	if req.GetProductId() != "" {
+		var err error
+		if productID, err = uuid.Parse(req.GetProductId()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid product ID")
+		}
+	}
+	admin := hasAdminToken(ctx, h.AdminToken)
+	visible := make(map[uuid.UUID]bool)
+	if productID != uuid.Nil {
+		if err := checkProductVisible(admin, h.productService, productID); err != nil {
+			return nil, err
+		}
+		visible[productID] = true
+	}
+
+	subscriptionPlans, err := h.subscriptionService.ListSubscriptionPlans(ctx, productID)
 	if err != nil {
 		log.Printf("Failed to list subscription plans: %v", err)
 		return nil, status.Errorf(codes.Internal, "Error fetching subscription plans")
 	}
 
-	// Map the subscription plans to the protobuf response format
+	// Map the subscription plans to the protobuf response format, leaving
+	// out the plans of products hidden from the caller
 	var pbSubscriptionPlans []*pb.SubscriptionPlan
 	for _, plan := range subscriptionPlans {
-		pbSubscriptionPlans = append(pbSubscriptionPlans, &pb.SubscriptionPlan{
-			Id:           plan.ID.String(),
-			ProductId:    plan.ProductID.String(),
-			PlanName:     plan.PlanName,
-			Price:        float32(plan.Price),
-			DurationDays: int32(plan.Duration),
-		})
+		if !admin {
+			shown, checked := visible[plan.ProductID]
+			if !checked {
+				err := checkProductVisible(false, h.productService, plan.ProductID)
+				if err != nil && status.Code(err) != codes.NotFound {
+					return nil, err
+				}
+				shown = err == nil
+				visible[plan.ProductID] = shown
+			}
+			if !shown {
+				continue
+			}
+		}
+		pbSubscriptionPlans = append(pbSubscriptionPlans, subscriptionPlanToProto(plan))
 	}
 
 	// Return the response with all subscription plans
@@ -128,34 +152,30 @@ func (h *SubscriptionHandler) UpdateSubscriptionPlan(ctx context.Context, req *p
         return nil, err
     }
 
-    // Get the price and durationDays directly from the request (no conversion)
-    price := req.GetPrice() // price as float32
-    durationDays := req.GetDurationDays() // durationDays as int32
+    version, err := domain.ParseETag(req.GetEtag())
+    if err != nil {
+        return nil, toStatusError(err)
+    }
 
-    // Round the price to 2 decimal places
-    roundedPrice := math.Round(float64(price)*100) / 100.0
+    // The price is taken as sent; amounts are exact so there is nothing to round
+    price := mapper.MoneyFromProto(req.GetPrice())
+    durationDays := req.GetDurationDays() // durationDays as int32
 
     // Update the subscription plan via service layer
-    updatedPlan, err := h.subscriptionService.UpdateSubscriptionPlan(ctx, id, req.GetPlanName(), roundedPrice, int(durationDays))
+    updatedPlan, err := h.subscriptionService.UpdateSubscriptionPlan(ctx, id, version, req.GetPlanName(), price, int(durationDays))
     if err != nil {
         log.Printf("Failed to update subscription plan: %v", err)
-        return nil, err
+        return nil, toStatusError(err)
     }
 
-    // Return the updated plan as a response (no conversion needed for price or durationDays)
-    return &pb.SubscriptionPlan{
-        Id:           updatedPlan.ID.String(),
-        ProductId:    updatedPlan.ProductID.String(),
-        PlanName:     updatedPlan.PlanName,
-        Price:        float32(updatedPlan.Price), // Return as float32
-        DurationDays: int32(updatedPlan.Duration), // Return as int32
-    }, nil
+    // Return the updated plan as a response
+    return subscriptionPlanToProto(updatedPlan), nil
 }
 
 
 
-// DeleteSubscription handles the gRPC request to delete a subscription plan
-func (h *SubscriptionHandler) DeleteSubscription(ctx context.Context, req *pb.DeleteSubscriptionPlanRequest) (*emptypb.Empty, error) {
+// DeleteSubscriptionPlan handles the gRPC request to delete a subscription plan
+func (h *SubscriptionHandler) DeleteSubscriptionPlan(ctx context.Context, req *pb.DeleteSubscriptionPlanRequest) (*emptypb.Empty, error) {
 	// Step 1: Convert the string ID from the request to a UUID
 	id, err := uuid.Parse(req.GetId())
 	if err != nil {
@@ -163,19 +183,35 @@ func (h *SubscriptionHandler) DeleteSubscription(ctx context.Context, req *pb.De
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid UUID: %v", err)
 	}
 
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
 	// Step 2: Call the service to delete the subscription
-	err = h.subscriptionService.DeleteSubscriptionPlan(ctx, id)
+	err = h.subscriptionService.DeleteSubscriptionPlan(ctx, id, version)
 	if err != nil {
 		log.Printf("Failed to delete subscription plan: %v", err)
-		return nil, status.Errorf(codes.Internal, "Failed to delete subscription plan: %v", err)
+		return nil, toStatusError(err)
 	}
 
 	// Step 3: Return an empty response after successful deletion
 	return &emptypb.Empty{}, nil
 }
 
+// subscriptionPlanToProto converts a domain subscription plan to its protobuf representation
+func subscriptionPlanToProto(plan *domain.SubscriptionPlan) *pb.SubscriptionPlan {
+	return &pb.SubscriptionPlan{
+		Id:           plan.ID.String(),
+		ProductId:    plan.ProductID.String(),
+		PlanName:     plan.PlanName,
+		Price:        mapper.MoneyToProto(plan.Price),
+		DurationDays: int32(plan.Duration),
+		Etag:         domain.FormatETag(plan.Version),
+	}
+}
 
-// RegisterHandlers registers the SubscriptionHandler with the gRPC server
+// RegisterHandler registers the SubscriptionHandler with the gRPC server
 func RegisterHandler(server *grpc.Server, subscriptionService service.SubscriptionService, productService service.ProductService) {
 	handler := NewSubscriptionHandler(subscriptionService, productService)
 	pb.RegisterSubscriptionServiceServer(server, handler)
@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
+	pb "product-microservice/proto/product"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateWarehouse handles the CreateWarehouse gRPC method
+func (h *ProductHandler) CreateWarehouse(ctx context.Context, req *pb.Warehouse) (*pb.Warehouse, error) {
+	warehouse, err := mapper.WarehouseFromProto(req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	// IDs are assigned by the server
+	warehouse.ID = uuid.Nil
+
+	created, err := h.ProductService.CreateWarehouse(ctx, warehouse)
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to create warehouse: %w", err))
+	}
+	return mapper.WarehouseToProto(created), nil
+}
+
+// GetWarehouse handles the GetWarehouse gRPC method
+func (h *ProductHandler) GetWarehouse(ctx context.Context, req *pb.GetWarehouseRequest) (*pb.Warehouse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid warehouse ID format: %v", err)
+	}
+
+	warehouse, err := h.ProductService.GetWarehouse(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return mapper.WarehouseToProto(warehouse), nil
+}
+
+// ListWarehouses handles the ListWarehouses gRPC method
+func (h *ProductHandler) ListWarehouses(ctx context.Context, req *pb.ListWarehousesRequest) (*pb.ListWarehousesResponse, error) {
+	warehouses, err := h.ProductService.ListWarehouses(ctx, domain.WarehouseListOptions{ActiveOnly: req.GetActiveOnly()})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	response := &pb.ListWarehousesResponse{Warehouses: make([]*pb.Warehouse, len(warehouses))}
+	for i := range warehouses {
+		response.Warehouses[i] = mapper.WarehouseToProto(&warehouses[i])
+	}
+	return response, nil
+}
+
+// UpdateWarehouse handles the UpdateWarehouse gRPC method
+func (h *ProductHandler) UpdateWarehouse(ctx context.Context, req *pb.UpdateWarehouseRequest) (*pb.Warehouse, error) {
+	update, err := mapper.WarehouseFromProto(req.GetWarehouse())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if update.ID == uuid.Nil {
+		return nil, status.Error(codes.InvalidArgument, "warehouse.id is required")
+	}
+	if update.Version, err = domain.ParseETag(req.GetWarehouse().GetEtag()); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	updated, err := h.ProductService.UpdateWarehouse(ctx, update.ID, update, req.GetUpdateMask().GetPaths())
+	if err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to update warehouse: %w", err))
+	}
+	return mapper.WarehouseToProto(updated), nil
+}
+
+// DeleteWarehouse handles the DeleteWarehouse gRPC method
+func (h *ProductHandler) DeleteWarehouse(ctx context.Context, req *pb.DeleteWarehouseRequest) (*emptypb.Empty, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid warehouse ID format: %v", err)
+	}
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if err := h.ProductService.DeleteWarehouse(ctx, id, version); err != nil {
+		return nil, toStatusError(fmt.Errorf("failed to delete warehouse: %w", err))
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -1,16 +1,23 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"net/http"
+	// Warehouse time zones are validated against the embedded zone database,
+	// so they work on hosts without one
+	_ "time/tzdata"
 	"product-microservice/config"
 	"product-microservice/db"
+	"product-microservice/internal/blobstore"
 	"product-microservice/internal/domain"
 	"product-microservice/internal/repository"
 	"product-microservice/internal/service"
+	"product-microservice/internal/shipping"
 	grpcTransport "product-microservice/internal/transport/grpc"
 	pb "product-microservice/proto/product"
-	// sp "product-microservice/proto/subscription"
+	sp "product-microservice/proto/subscription"
 	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
@@ -32,11 +39,40 @@ func main() {
 
 	// Initialize repositories and services
 	productRepo := repository.ProductRepositoryImpl{DB: database}  // Ensure the repo is properly initialized
-	// subscriptionRepo := repository.NewSubscriptionRepository(database) // Assuming this repo is initialized correctly
+	subscriptionRepo := repository.NewSubscriptionRepository(database)
 
 	productService := service.NewProductService(&productRepo)  // Initialize the service
-	// subscriptionService := service.NewSubscriptionService(subscriptionRepo)
-	//subscriptionService := service.NewSubscriptionService(subscriptionRepo)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo)
+
+	// Store product media on the local filesystem and serve them over HTTP
+	if cfg.MediaDir != "" {
+		mediaStore, err := blobstore.NewLocalStore(cfg.MediaDir, cfg.MediaBaseURL)
+		if err != nil {
+			log.Fatalf("Failed to set up media storage: %v", err)
+		}
+		productService.MediaStore = mediaStore
+		go serveMedia(cfg, mediaStore)
+	} else {
+		log.Println("MEDIA_DIR is not set, product media uploads are disabled")
+	}
+
+	// Price shipping estimates with the configured rate tables
+	if cfg.ShippingRatesFile != "" {
+		rates, err := shipping.LoadRateTable(cfg.ShippingRatesFile)
+		if err != nil {
+			log.Fatalf("Failed to load shipping rates: %v", err)
+		}
+		productService.ShippingRates = rates
+	} else {
+		log.Println("SHIPPING_RATES_FILE is not set, shipping estimates are disabled")
+	}
+
+	// Apply scheduled price changes in the background
+	go productService.RunPriceScheduler(context.Background())
+	// Publish and archive products at their scheduled times
+	go productService.RunPublishScheduler(context.Background())
+	// Release stock reservations that were neither committed nor released in time
+	go productService.RunReservationExpiry(context.Background())
 
 	// Start gRPC server
 	listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
@@ -45,8 +81,12 @@ func main() {
 	}
 
 	server := grpc.NewServer()
-	pb.RegisterProductServiceServer(server, grpcTransport.NewProductHandler(productService))
-	// sp.RegisterSubscriptionServiceServer(server, grpcTransport.NewSubscriptionHandler(subscriptionService))
+	productHandler := grpcTransport.NewProductHandler(productService)
+	productHandler.AdminToken = cfg.AdminToken
+	pb.RegisterProductServiceServer(server, productHandler)
+	subscriptionHandler := grpcTransport.NewSubscriptionHandler(subscriptionService, productService)
+	subscriptionHandler.AdminToken = cfg.AdminToken
+	sp.RegisterSubscriptionServiceServer(server, subscriptionHandler)
 
 	log.Printf("gRPC server running on port %s", cfg.GRPCPort)
 	if err := server.Serve(listener); err != nil {
@@ -54,12 +94,40 @@ func main() {
 	}
 }
 
-func migrateModels(db *gorm.DB) error {
+// serveMedia serves the files of the local media store below /media/
+func serveMedia(cfg *config.Config, store *blobstore.LocalStore) {
+	mux := http.NewServeMux()
+	mux.Handle("/media/", http.StripPrefix("/media/", store.Handler()))
+	log.Printf("Media server running on port %s", cfg.MediaHTTPPort)
+	if err := http.ListenAndServe(":"+cfg.MediaHTTPPort, mux); err != nil {
+		log.Fatalf("Failed to serve media: %v", err)
+	}
+}
+
+func migrateModels(database *gorm.DB) error {
 	log.Println("Starting database migration...")
-	err := db.AutoMigrate(
+	err := database.AutoMigrate(
+		&domain.Category{},
 		&domain.Product{},      
 		&domain.SubscriptionPlan{}, 
+		&domain.ProductEvent{},
+		&domain.PriceListEntry{},
+		&domain.PriceChange{},
+		&domain.ScheduledPriceChange{},
+		&domain.ProductVariant{},
+		&domain.AttributeDefinition{},
+		&domain.ProductMedia{},
+		&domain.ProductTranslation{},
+		&domain.BundleProduct{},
+		&domain.BundleComponent{},
+		&domain.Warehouse{},
+		&domain.StockLevel{},
+		&domain.StockReservation{},
+		&domain.StockMovement{},
 	)
+	if err == nil {
+		err = db.Migrate(database)
+	}
 	if err == nil {
 		log.Println("Database migrated successfully")
 	}
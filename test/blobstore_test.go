@@ -0,0 +1,40 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"product-microservice/internal/blobstore"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStoreHandlerOnlyServesBlobs(t *testing.T) {
+	store, err := blobstore.NewLocalStore(t.TempDir(), "http://localhost/media/")
+	require.NoError(t, err)
+	_, err = store.Put(context.Background(), "products/p1/m1.png", strings.NewReader("png"))
+	require.NoError(t, err)
+	// A temporary file left by an upload that is still being written
+	require.NoError(t, os.WriteFile(filepath.Join(store.Dir, "products", "p1", ".upload-123"), []byte("partial"), 0o644))
+
+	server := httptest.NewServer(http.StripPrefix("/media/", store.Handler()))
+	defer server.Close()
+
+	for path, code := range map[string]int{
+		"/media/products/p1/m1.png":      http.StatusOK,
+		"/media/":                        http.StatusNotFound,
+		"/media/products/":               http.StatusNotFound,
+		"/media/products/p1":             http.StatusNotFound,
+		"/media/products/p1/.upload-123": http.StatusNotFound,
+	} {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, code, resp.StatusCode, path)
+	}
+}
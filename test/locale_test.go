@@ -0,0 +1,27 @@
+package test
+
+import (
+	"product-microservice/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleFallbacks(t *testing.T) {
+	locales, err := domain.LocaleFallbacks("fr-CH, fr;q=0.9, de_at;q=0.8, *;q=0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fr-CH", "fr", "de-AT", "de"}, locales)
+
+	locales, err = domain.LocaleFallbacks("zh-hant-tw")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh"}, locales)
+
+	_, err = domain.LocaleFallbacks("not a locale!")
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+	locale, err := domain.CanonicalLocale(" PT_br ")
+	assert.NoError(t, err)
+	assert.Equal(t, "pt-BR", locale)
+	_, err = domain.CanonicalLocale("und")
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+}
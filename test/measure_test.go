@@ -0,0 +1,60 @@
+package test
+
+import (
+	"math"
+	"product-microservice/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertWeight(t *testing.T) {
+	pounds, err := domain.Weight{Value: 1, Unit: domain.WeightUnitKilogram}.In(domain.WeightUnitPound)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.20462, pounds.Value, 1e-5)
+	assert.Equal(t, domain.WeightUnitPound, pounds.Unit)
+
+	grams, err := domain.ConvertWeight(16, domain.WeightUnitOunce, domain.WeightUnitGram)
+	assert.NoError(t, err)
+	assert.InDelta(t, 453.59237, grams, 1e-9)
+
+	_, err = domain.ConvertWeight(1, "st", domain.WeightUnitKilogram)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+}
+
+func TestConvertDimensions(t *testing.T) {
+	inches, err := domain.Dimensions{Length: 25.4, Width: 50.8, Height: 2.54, Unit: domain.LengthUnitCentimeter}.In(domain.LengthUnitInch)
+	assert.NoError(t, err)
+	assert.InDelta(t, 10, inches.Length, 1e-9)
+	assert.InDelta(t, 20, inches.Width, 1e-9)
+	assert.InDelta(t, 1, inches.Height, 1e-9)
+	assert.InDelta(t, 200, inches.Volume(), 1e-9)
+
+	_, err = domain.Dimensions{Length: 1, Width: 1, Height: 1, Unit: "ft"}.In(domain.LengthUnitCentimeter)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+}
+
+func TestValidateMeasures(t *testing.T) {
+	// Unset measures are valid
+	assert.NoError(t, domain.Weight{}.Validate())
+	assert.NoError(t, domain.Dimensions{}.Validate())
+	assert.NoError(t, domain.Weight{Value: 0, Unit: domain.WeightUnitGram}.Validate())
+	assert.NoError(t, domain.Dimensions{Length: 1, Width: 2, Height: 3, Unit: domain.LengthUnitInch}.Validate())
+
+	for _, weight := range []domain.Weight{
+		{Value: 1},
+		{Value: 1, Unit: "st"},
+		{Value: -1, Unit: domain.WeightUnitKilogram},
+		{Value: math.NaN(), Unit: domain.WeightUnitKilogram},
+	} {
+		assert.ErrorIs(t, weight.Validate(), domain.ErrInvalidArgument, weight)
+	}
+	for _, dimensions := range []domain.Dimensions{
+		{Length: 1, Width: 2, Height: 3},
+		{Length: 1, Width: 2, Unit: domain.LengthUnitCentimeter},
+		{Length: 1, Width: -2, Height: 3, Unit: domain.LengthUnitCentimeter},
+		{Length: math.Inf(1), Width: 2, Height: 3, Unit: domain.LengthUnitCentimeter},
+	} {
+		assert.ErrorIs(t, dimensions.Validate(), domain.ErrInvalidArgument, dimensions)
+	}
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"encoding/json"
+	"product-microservice/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	for input, expected := range map[string]domain.Amount{
+		"19.99":        {Units: 19, Nanos: 990000000},
+		"-0.5":         {Nanos: -500000000},
+		".25":          {Nanos: 250000000},
+		"7":            {Units: 7},
+		"0.000000001":  {Nanos: 1},
+		"100.10000000": {Units: 100, Nanos: 100000000},
+	} {
+		amount, err := domain.ParseAmount(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, amount, input)
+	}
+
+	// Digits that do not fit are rejected instead of rounded
+	for _, input := range []string{"", "abc", "1.0000000001", "1e3", "99999999999999999999"} {
+		_, err := domain.ParseAmount(input)
+		assert.ErrorIs(t, err, domain.ErrInvalidArgument, input)
+	}
+
+	assert.Equal(t, "-0.5", domain.Amount{Nanos: -500000000}.String())
+	assert.Equal(t, "100.1", domain.MustParseAmount("100.10").String())
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	// 0.1 + 0.2 is exact, unlike with floats
+	sum, err := domain.MustParseAmount("0.1").Add(domain.MustParseAmount("0.2"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.MustParseAmount("0.3"), sum)
+
+	difference, err := domain.MustParseAmount("1.25").Sub(domain.MustParseAmount("3.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "-2.25", difference.String())
+
+	product, err := domain.MustParseAmount("19.99").MulInt(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "59.97", product.String())
+
+	_, err = domain.Amount{Units: 1 << 62}.MulInt(4)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+	// Amounts travel through JSON as strings
+	raw, err := json.Marshal(domain.MustParseAmount("12.3"))
+	assert.NoError(t, err)
+	assert.Equal(t, `"12.3"`, string(raw))
+}
+
+func TestMoneyValidate(t *testing.T) {
+	assert.NoError(t, domain.Money{Amount: domain.MustParseAmount("9.99"), Currency: "EUR"}.Validate())
+	assert.NoError(t, domain.Money{Currency: "JPY"}.Validate())
+
+	for _, money := range []domain.Money{
+		{Amount: domain.Amount{Units: 1}},
+		{Amount: domain.Amount{Units: 1}, Currency: "usd"},
+		{Amount: domain.Amount{Units: 1}, Currency: "XYZ"},
+		{Amount: domain.Amount{Units: 1, Nanos: -1}, Currency: "USD"},
+		{Amount: domain.Amount{Nanos: 1000000000}, Currency: "USD"},
+	} {
+		assert.ErrorIs(t, money.Validate(), domain.ErrInvalidArgument, money.String())
+	}
+}
+
+func TestPercentOff(t *testing.T) {
+	for _, tc := range []struct {
+		price, percent, expected string
+		currency                 string
+	}{
+		{"100", "10", "90", "USD"},
+		{"19.99", "15", "16.99", "USD"},
+		{"0.05", "50", "0.03", "EUR"},
+		{"1005", "12.5", "879", "JPY"},
+		{"9.999", "0", "9.999", "KWD"},
+		{"42", "100", "0", "USD"},
+	} {
+		discounted, err := domain.PercentOff(domain.Money{Amount: domain.MustParseAmount(tc.price), Currency: tc.currency}, domain.MustParseAmount(tc.percent))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected+" "+tc.currency, discounted.String())
+	}
+
+	assert.NoError(t, domain.ValidatePercent(domain.MustParseAmount("99.5")))
+	assert.ErrorIs(t, domain.ValidatePercent(domain.MustParseAmount("100.01")), domain.ErrInvalidArgument)
+	assert.ErrorIs(t, domain.ValidatePercent(domain.MustParseAmount("-1")), domain.ErrInvalidArgument)
+}
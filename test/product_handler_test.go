@@ -1,18 +1,38 @@
 package test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"product-microservice/internal/blobstore"
 	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
 	"product-microservice/internal/repository"
 	"product-microservice/internal/service"
 	"product-microservice/internal/transport/grpc"
+	moneypb "product-microservice/proto/money"
 	pb "product-microservice/proto/product"
 	"testing"
 	"time"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -62,7 +82,19 @@ func (m *MockProductRepository) GetByID(id uuid.UUID) (*domain.Product, error) {
 }
 
 // Mock Delete method
-func (m *MockProductRepository) Delete(id uuid.UUID) error {
+func (m *MockProductRepository) Delete(id uuid.UUID, version int64) error {
+    args := m.Called(id, version)
+    return args.Error(0)
+}
+
+// Mock Restore method
+func (m *MockProductRepository) Restore(id uuid.UUID, version int64) error {
+    args := m.Called(id, version)
+    return args.Error(0)
+}
+
+// Mock Purge method
+func (m *MockProductRepository) Purge(id uuid.UUID) error {
     args := m.Called(id)
     return args.Error(0)
 }
@@ -73,6 +105,56 @@ func (m *MockProductRepository) Update(product *domain.Product) error {
     return args.Error(0)
 }
 
+// Mock ListProducts method
+func (m *MockProductRepository) ListProducts(ctx context.Context, opts domain.ProductListOptions) ([]domain.Product, int64, error) {
+    args := m.Called(ctx, opts)
+    return args.Get(0).([]domain.Product), args.Get(1).(int64), args.Error(2)
+}
+
+// Mock SearchProducts method
+func (m *MockProductRepository) SearchProducts(ctx context.Context, opts domain.ProductSearchOptions) ([]domain.ProductSearchHit, error) {
+    args := m.Called(ctx, opts)
+    return args.Get(0).([]domain.ProductSearchHit), args.Error(1)
+}
+
+// Mock ExportProducts method: the expectation returns the products to feed to fn
+func (m *MockProductRepository) ExportProducts(ctx context.Context, filter domain.ProductFilter, fn func(*domain.ExportedProduct) error) error {
+    args := m.Called(ctx, filter)
+    products := args.Get(0).([]domain.ExportedProduct)
+    for i := range products {
+        if err := fn(&products[i]); err != nil {
+            return err
+        }
+    }
+    return args.Error(1)
+}
+
+// Mock ListProductEvents method
+func (m *MockProductRepository) ListProductEvents(ctx context.Context, after domain.ProductEventPosition, limit int) ([]domain.ProductEvent, error) {
+    args := m.Called(ctx, after, limit)
+    return args.Get(0).([]domain.ProductEvent), args.Error(1)
+}
+
+// Mock LatestProductEventPosition method
+func (m *MockProductRepository) LatestProductEventPosition(ctx context.Context) (domain.ProductEventPosition, error) {
+    args := m.Called(ctx)
+    return args.Get(0).(domain.ProductEventPosition), args.Error(1)
+}
+
+// Mock FindByExternalSKU method
+func (m *MockProductRepository) FindByExternalSKU(sku string) (*domain.Product, error) {
+    args := m.Called(sku)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.Product), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock Transaction method runs the callback against the mock itself
+func (m *MockProductRepository) Transaction(ctx context.Context, fn func(repo repository.ProductRepository) error) error {
+    return fn(m)
+}
+
 // Mock FindById method
 func (m *MockProductRepository) FindById(id string) (*domain.Product, error) {
     args := m.Called(id)
@@ -82,6 +164,326 @@ func (m *MockProductRepository) FindById(id string) (*domain.Product, error) {
     return nil, args.Error(1)
 }
 
+// Mock CreatePriceListEntry method
+func (m *MockProductRepository) CreatePriceListEntry(ctx context.Context, entry *domain.PriceListEntry) error {
+    args := m.Called(ctx, entry)
+    return args.Error(0)
+}
+
+// Mock ListPriceListEntries method
+func (m *MockProductRepository) ListPriceListEntries(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.PriceListEntry, error) {
+    args := m.Called(ctx, productID, planID)
+    return args.Get(0).([]domain.PriceListEntry), args.Error(1)
+}
+
+// Mock DeletePriceListEntry method
+func (m *MockProductRepository) DeletePriceListEntry(ctx context.Context, id uuid.UUID, version int64) error {
+    args := m.Called(ctx, id, version)
+    return args.Error(0)
+}
+
+// Mock FindEffectivePrices method
+func (m *MockProductRepository) FindEffectivePrices(ctx context.Context, query domain.PriceQuery) ([]domain.PriceListEntry, error) {
+    args := m.Called(ctx, query)
+    return args.Get(0).([]domain.PriceListEntry), args.Error(1)
+}
+
+// Mock GetSubscriptionPlan method
+func (m *MockProductRepository) GetSubscriptionPlan(ctx context.Context, productID, planID uuid.UUID) (*domain.SubscriptionPlan, error) {
+    args := m.Called(ctx, productID, planID)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.SubscriptionPlan), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock ListPriceHistory method
+func (m *MockProductRepository) ListPriceHistory(ctx context.Context, opts domain.PriceHistoryOptions) ([]domain.PriceChange, error) {
+    args := m.Called(ctx, opts)
+    return args.Get(0).([]domain.PriceChange), args.Error(1)
+}
+
+// Mock SchedulePriceChange method
+func (m *MockProductRepository) SchedulePriceChange(ctx context.Context, change *domain.ScheduledPriceChange) error {
+    args := m.Called(ctx, change)
+    return args.Error(0)
+}
+
+// Mock ListScheduledPriceChanges method
+func (m *MockProductRepository) ListScheduledPriceChanges(ctx context.Context, productID uuid.UUID, planID *uuid.UUID) ([]domain.ScheduledPriceChange, error) {
+    args := m.Called(ctx, productID, planID)
+    return args.Get(0).([]domain.ScheduledPriceChange), args.Error(1)
+}
+
+// Mock CancelScheduledPriceChange method
+func (m *MockProductRepository) CancelScheduledPriceChange(ctx context.Context, id uuid.UUID, version int64) (*domain.ScheduledPriceChange, error) {
+    args := m.Called(ctx, id, version)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.ScheduledPriceChange), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock ApplyDuePriceChanges method
+func (m *MockProductRepository) ApplyDuePriceChanges(ctx context.Context, now time.Time, limit int) (int, error) {
+    args := m.Called(ctx, now, limit)
+    return args.Int(0), args.Error(1)
+}
+
+// Mock CreateVariant method
+func (m *MockProductRepository) CreateVariant(ctx context.Context, variant *domain.ProductVariant) error {
+    args := m.Called(ctx, variant)
+    return args.Error(0)
+}
+
+// Mock GetVariant method
+func (m *MockProductRepository) GetVariant(ctx context.Context, id uuid.UUID) (*domain.ProductVariant, error) {
+    args := m.Called(ctx, id)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.ProductVariant), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock FindVariantBySKU method
+func (m *MockProductRepository) FindVariantBySKU(ctx context.Context, sku string) (*domain.ProductVariant, error) {
+    args := m.Called(ctx, sku)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.ProductVariant), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock ListVariants method
+func (m *MockProductRepository) ListVariants(ctx context.Context, productID uuid.UUID) ([]domain.ProductVariant, error) {
+    args := m.Called(ctx, productID)
+    return args.Get(0).([]domain.ProductVariant), args.Error(1)
+}
+
+// Mock UpdateVariant method
+func (m *MockProductRepository) UpdateVariant(ctx context.Context, variant *domain.ProductVariant) error {
+    args := m.Called(ctx, variant)
+    return args.Error(0)
+}
+
+// Mock DeleteVariant method
+func (m *MockProductRepository) DeleteVariant(ctx context.Context, id uuid.UUID, version int64) error {
+    args := m.Called(ctx, id, version)
+    return args.Error(0)
+}
+
+// Mock CreateCategory method
+func (m *MockProductRepository) CreateCategory(ctx context.Context, category *domain.Category) error {
+    args := m.Called(ctx, category)
+    return args.Error(0)
+}
+
+// Mock GetCategory method
+func (m *MockProductRepository) GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+    args := m.Called(ctx, id)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.Category), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock ListCategories method
+func (m *MockProductRepository) ListCategories(ctx context.Context, opts domain.CategoryListOptions) ([]domain.Category, error) {
+    args := m.Called(ctx, opts)
+    return args.Get(0).([]domain.Category), args.Error(1)
+}
+
+// Mock UpdateCategory method
+func (m *MockProductRepository) UpdateCategory(ctx context.Context, category *domain.Category) error {
+    args := m.Called(ctx, category)
+    return args.Error(0)
+}
+
+// Mock DeleteCategory method
+func (m *MockProductRepository) DeleteCategory(ctx context.Context, id uuid.UUID, version int64) error {
+    args := m.Called(ctx, id, version)
+    return args.Error(0)
+}
+
+// Mock CreateAttributeDefinition method
+func (m *MockProductRepository) CreateAttributeDefinition(ctx context.Context, definition *domain.AttributeDefinition) error {
+    args := m.Called(ctx, definition)
+    return args.Error(0)
+}
+
+// Mock GetAttributeDefinition method
+func (m *MockProductRepository) GetAttributeDefinition(ctx context.Context, id uuid.UUID) (*domain.AttributeDefinition, error) {
+    args := m.Called(ctx, id)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.AttributeDefinition), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock ListAttributeDefinitions method
+func (m *MockProductRepository) ListAttributeDefinitions(ctx context.Context, opts domain.AttributeDefinitionListOptions) ([]domain.AttributeDefinition, error) {
+    args := m.Called(ctx, opts)
+    return args.Get(0).([]domain.AttributeDefinition), args.Error(1)
+}
+
+// Mock ApplicableAttributeDefinitions method
+func (m *MockProductRepository) ApplicableAttributeDefinitions(ctx context.Context, productType string, categoryIDs []uuid.UUID) ([]domain.AttributeDefinition, error) {
+    args := m.Called(ctx, productType, categoryIDs)
+    return args.Get(0).([]domain.AttributeDefinition), args.Error(1)
+}
+
+// Mock UpdateAttributeDefinition method
+func (m *MockProductRepository) UpdateAttributeDefinition(ctx context.Context, definition *domain.AttributeDefinition) error {
+    args := m.Called(ctx, definition)
+    return args.Error(0)
+}
+
+// Mock DeleteAttributeDefinition method
+func (m *MockProductRepository) DeleteAttributeDefinition(ctx context.Context, id uuid.UUID, version int64) error {
+    args := m.Called(ctx, id, version)
+    return args.Error(0)
+}
+
+// Mock CreateMedia method
+func (m *MockProductRepository) CreateMedia(ctx context.Context, media *domain.ProductMedia) error {
+    args := m.Called(ctx, media)
+    return args.Error(0)
+}
+
+// Mock GetMedia method
+func (m *MockProductRepository) GetMedia(ctx context.Context, id uuid.UUID) (*domain.ProductMedia, error) {
+    args := m.Called(ctx, id)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.ProductMedia), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock ListMedia method
+func (m *MockProductRepository) ListMedia(ctx context.Context, productID uuid.UUID) ([]domain.ProductMedia, error) {
+    args := m.Called(ctx, productID)
+    return args.Get(0).([]domain.ProductMedia), args.Error(1)
+}
+
+// Mock ReorderMedia method
+func (m *MockProductRepository) ReorderMedia(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) ([]domain.ProductMedia, error) {
+    args := m.Called(ctx, productID, ids)
+    if args.Get(0) != nil {
+        return args.Get(0).([]domain.ProductMedia), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock DeleteMedia method
+func (m *MockProductRepository) DeleteMedia(ctx context.Context, id uuid.UUID, version int64) error {
+    args := m.Called(ctx, id, version)
+    return args.Error(0)
+}
+
+// Mock UpsertTranslations method
+func (m *MockProductRepository) UpsertTranslations(ctx context.Context, productID uuid.UUID, translations []domain.ProductTranslation) error {
+    args := m.Called(ctx, productID, translations)
+    return args.Error(0)
+}
+
+// Mock ListTranslations method
+func (m *MockProductRepository) ListTranslations(ctx context.Context, productID uuid.UUID) ([]domain.ProductTranslation, error) {
+    args := m.Called(ctx, productID)
+    return args.Get(0).([]domain.ProductTranslation), args.Error(1)
+}
+
+// Mock FindTranslations method
+func (m *MockProductRepository) FindTranslations(ctx context.Context, productIDs []uuid.UUID, locales []string) ([]domain.ProductTranslation, error) {
+    args := m.Called(ctx, productIDs, locales)
+    return args.Get(0).([]domain.ProductTranslation), args.Error(1)
+}
+
+// Mock DeleteTranslation method
+func (m *MockProductRepository) DeleteTranslation(ctx context.Context, productID uuid.UUID, locale string) error {
+    args := m.Called(ctx, productID, locale)
+    return args.Error(0)
+}
+
+func (m *MockProductRepository) ApplyDuePublications(ctx context.Context, now time.Time) (int64, error) {
+    args := m.Called(ctx, now)
+    return args.Get(0).(int64), args.Error(1)
+}
+
+// Mock AdjustStock method
+func (m *MockProductRepository) AdjustStock(ctx context.Context, item domain.StockItem, delta int64, reason string) (*domain.StockLevel, error) {
+    args := m.Called(ctx, item, delta, reason)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.StockLevel), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock GetStock method
+func (m *MockProductRepository) GetStock(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID) ([]domain.StockLevel, error) {
+    args := m.Called(ctx, productID, variantID)
+    return args.Get(0).([]domain.StockLevel), args.Error(1)
+}
+
+// Mock ReserveStock method
+func (m *MockProductRepository) ReserveStock(ctx context.Context, reservation *domain.StockReservation) error {
+    args := m.Called(ctx, reservation)
+    return args.Error(0)
+}
+
+// Mock SettleReservation method
+func (m *MockProductRepository) SettleReservation(ctx context.Context, id uuid.UUID, status domain.StockReservationStatus, now time.Time) (*domain.StockReservation, error) {
+    args := m.Called(ctx, id, status, now)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.StockReservation), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock ExpireReservations method
+func (m *MockProductRepository) ExpireReservations(ctx context.Context, now time.Time, limit int) (int, error) {
+    args := m.Called(ctx, now, limit)
+    return args.Int(0), args.Error(1)
+}
+
+// Mock ListStockMovements method
+func (m *MockProductRepository) ListStockMovements(ctx context.Context, opts domain.StockMovementOptions) ([]domain.StockMovement, error) {
+    args := m.Called(ctx, opts)
+    return args.Get(0).([]domain.StockMovement), args.Error(1)
+}
+
+// Mock CreateWarehouse method
+func (m *MockProductRepository) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error {
+    args := m.Called(ctx, warehouse)
+    return args.Error(0)
+}
+
+// Mock GetWarehouse method
+func (m *MockProductRepository) GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+    args := m.Called(ctx, id)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.Warehouse), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+// Mock ListWarehouses method
+func (m *MockProductRepository) ListWarehouses(ctx context.Context, opts domain.WarehouseListOptions) ([]domain.Warehouse, error) {
+    args := m.Called(ctx, opts)
+    return args.Get(0).([]domain.Warehouse), args.Error(1)
+}
+
+// Mock UpdateWarehouse method
+func (m *MockProductRepository) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error {
+    args := m.Called(ctx, warehouse)
+    return args.Error(0)
+}
+
+// Mock DeleteWarehouse method
+func (m *MockProductRepository) DeleteWarehouse(ctx context.Context, id uuid.UUID, version int64) error {
+    args := m.Called(ctx, id, version)
+    return args.Error(0)
+}
+
 // setupTestDatabase sets up a PostgreSQL database connection for testing using the existing db and config setup
 func setupTestDatabase(t *testing.T) *gorm.DB {
 	// Database connection details (from your config)
@@ -112,11 +514,16 @@ func setupTestDatabase(t *testing.T) *gorm.DB {
 	return db
 }
 
+// adminContext carries the admin token of the integration test handlers, so
+// products can be read back before they are published
+var adminContext = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "integration"))
+
 // setupServiceAndHandler sets up the service and handler for testing
 func setupServiceAndHandler(_ *testing.T, db *gorm.DB) (service.ProductService, *grpc.ProductHandler) {
 	repo := repository.NewProductRepository(db)
 	service := service.NewProductService(repo)
 	handler := grpc.NewProductHandler(service)
+	handler.AdminToken = "integration"
 	return service, handler
 }
 
@@ -129,17 +536,17 @@ func TestCreateProductIntegration(t *testing.T) {
 	// Define multiple products with additional fields for related products
 	products := []struct {
 		name                string
-		price               float32
+		price               *moneypb.Money
 		productType         string
 		digitalProduct      *pb.DigitalProduct
 		physicalProduct     *pb.PhysicalProduct
 		subscriptionProduct *pb.SubscriptionProduct
 	}{
-		{"Product A", 19.99, "digital", &pb.DigitalProduct{FileSize: 100, DownloadLink: "http://example.com/a"}, nil, nil},
-		{"Product B", 29.99, "physical", nil, &pb.PhysicalProduct{Weight: 2.5, Dimensions: "10x10x5"}, nil},
-		{"Product C", 39.99, "subscription", nil, nil, &pb.SubscriptionProduct{SubscriptionPeriod: "1 year", RenewalPrice: 10.0}},
-		{"Product D", 49.99, "digital", &pb.DigitalProduct{FileSize: 150, DownloadLink: "http://example.com/d"}, nil, nil},
-		{"Product E", 59.99, "physical", nil, &pb.PhysicalProduct{Weight: 5.0, Dimensions: "20x20x10"}, nil},
+		{"Product A", usd(19, 990000000), "digital", &pb.DigitalProduct{FileSize: 100, DownloadLink: "http://example.com/a"}, nil, nil},
+		{"Product B", usd(29, 990000000), "physical", nil, &pb.PhysicalProduct{Weight: &pb.Weight{Value: 2.5, Unit: pb.Weight_KG}, Dimensions: &pb.Dimensions{Length: 10, Width: 10, Height: 5, Unit: pb.Dimensions_CM}}, nil},
+		{"Product C", usd(39, 990000000), "subscription", nil, nil, &pb.SubscriptionProduct{SubscriptionPeriod: "1 year", RenewalPrice: usd(10, 0)}},
+		{"Product D", usd(49, 990000000), "digital", &pb.DigitalProduct{FileSize: 150, DownloadLink: "http://example.com/d"}, nil, nil},
+		{"Product E", usd(59, 990000000), "physical", nil, &pb.PhysicalProduct{Weight: &pb.Weight{Value: 5.0, Unit: pb.Weight_KG}, Dimensions: &pb.Dimensions{Length: 20, Width: 20, Height: 10, Unit: pb.Dimensions_CM}}, nil},
 	}
 
 	// Loop over the products and test the CreateProduct function
@@ -158,7 +565,7 @@ func TestCreateProductIntegration(t *testing.T) {
 			assert.NoError(t, err)
 
 			// Log success message
-			t.Logf("Successfully created product: %s with price: %.2f", resp.GetName(), resp.GetPrice())
+			t.Logf("Successfully created product: %s with price: %s", resp.GetName(), mapper.MoneyFromProto(resp.GetPrice()))
 
 			// Ensure the related product data is populated based on type
 			switch p.productType {
@@ -176,7 +583,7 @@ func TestCreateProductIntegration(t *testing.T) {
 // Helper function to dynamically create the Product request
 func createProductRequest(p struct {
 	name                string
-	price               float32
+	price               *moneypb.Money
 	productType         string
 	digitalProduct      *pb.DigitalProduct
 	physicalProduct     *pb.PhysicalProduct
@@ -221,18 +628,18 @@ func TestGetProductIntegration(t *testing.T) {
 
     t.Run("Test get product by ID", func(t *testing.T) {
         // Call the handler to get the product by ID
-        getResp, err := handler.GetProduct(context.Background(), &pb.GetProductRequest{Id: productID})
+        getResp, err := handler.GetProduct(adminContext, &pb.GetProductRequest{Id: productID})
         if err != nil {
             t.Fatalf("Failed to fetch product: %v", err)
         }
 
         // Log the fetched product data
-        t.Logf("Fetched Product: ID=%s, Name=%s, Price=%.2f", getResp.GetId(), getResp.GetName(), getResp.GetPrice())
+        t.Logf("Fetched Product: ID=%s, Name=%s, Price=%s", getResp.GetId(), getResp.GetName(), mapper.MoneyFromProto(getResp.GetPrice()))
 
         // Add assertions to check that the product was correctly fetched
         assert.Equal(t, productID, getResp.GetId())
         assert.NotEmpty(t, getResp.GetName())
-        assert.NotZero(t, getResp.GetPrice())
+        assert.NotZero(t, getResp.GetPrice().GetUnits())
     })
 }
 
@@ -249,27 +656,34 @@ func TestUpdateProductIntegration(t *testing.T) {
         Id:          productID,
         Name:        "Updated Product Name",
         Description: "Updated Product Description",
-        Price:       99.99, // New price
+        Price:       usd(99, 990000000), // New price
     }
 
     t.Run("Test update product by ID", func(t *testing.T) {
+        // Read the product first to obtain its current etag
+        current, err := handler.GetProduct(adminContext, &pb.GetProductRequest{Id: productID})
+        if err != nil {
+            t.Fatalf("Failed to fetch product: %v", err)
+        }
+        updatedProduct.Etag = current.GetEtag()
+
         // Call the handler to update the product by ID
-        updateResp, err := handler.UpdateProduct(context.Background(), updatedProduct)
+        updateResp, err := handler.UpdateProduct(context.Background(), &pb.UpdateProductRequest{Product: updatedProduct})
         if err != nil {
             t.Fatalf("Failed to update product: %v", err)
         }
 
         // Log the updated product data
-        t.Logf("Updated Product: ID=%s, Name=%s, Price=%.2f", updateResp.GetId(), updateResp.GetName(), updateResp.GetPrice())
+        t.Logf("Updated Product: ID=%s, Name=%s, Price=%s", updateResp.GetId(), updateResp.GetName(), mapper.MoneyFromProto(updateResp.GetPrice()))
 
         // Add assertions to check that the product was correctly updated
         assert.Equal(t, productID, updateResp.GetId())
         assert.Equal(t, "Updated Product Name", updateResp.GetName())
         assert.Equal(t, "Updated Product Description", updateResp.GetDescription())
-        assert.Equal(t, float32(99.99), updateResp.GetPrice()) // Ensure both are float32
+        assert.True(t, proto.Equal(usd(99, 990000000), updateResp.GetPrice()))
 
         // Fetch the product again to verify changes were applied
-        getResp, err := handler.GetProduct(context.Background(), &pb.GetProductRequest{Id: productID})
+        getResp, err := handler.GetProduct(adminContext, &pb.GetProductRequest{Id: productID})
         if err != nil {
             t.Fatalf("Failed to fetch updated product: %v", err)
         }
@@ -277,7 +691,7 @@ func TestUpdateProductIntegration(t *testing.T) {
         // Assert that the updated values are present
         assert.Equal(t, updatedProduct.GetName(), getResp.GetName())
         assert.Equal(t, updatedProduct.GetDescription(), getResp.GetDescription())
-        assert.Equal(t, float32(updatedProduct.GetPrice()), getResp.GetPrice()) // Ensure both are float32
+        assert.True(t, proto.Equal(updatedProduct.GetPrice(), getResp.GetPrice()))
     })
 }
 
@@ -290,14 +704,20 @@ func TestDeleteProductIntegration(t *testing.T) {
     productID := "729998b3-c7cf-4c62-97b6-7f39129f2664"
 
     t.Run("Test delete product by ID", func(t *testing.T) {
+        // Read the product first to obtain its current etag
+        current, err := handler.GetProduct(adminContext, &pb.GetProductRequest{Id: productID})
+        if err != nil {
+            t.Fatalf("Failed to fetch product: %v", err)
+        }
+
         // Call the handler to delete the product by ID
-        _, err := handler.DeleteProduct(context.Background(), &pb.DeleteProductRequest{Id: productID})
+        _, err = handler.DeleteProduct(context.Background(), &pb.DeleteProductRequest{Id: productID, Etag: current.GetEtag()})
         if err != nil {
             t.Fatalf("Failed to delete product: %v", err)
         }
 
         // Try to fetch the product again to ensure it was deleted
-        _, err = handler.GetProduct(context.Background(), &pb.GetProductRequest{Id: productID})
+        _, err = handler.GetProduct(adminContext, &pb.GetProductRequest{Id: productID})
         assert.Error(t, err, "Expected error when fetching deleted product")
     })
 }
@@ -312,7 +732,7 @@ func TestListProductsByType(t *testing.T) {
             ID:          uuid.New(),
             Name:        "Digital Product 1",
             Description: "Digital Description 1",
-            Price:       100.0,
+            Price:       domain.Money{Amount: domain.Amount{Units: 100}, Currency: "USD"},
             CreatedAt:   time.Now(),
             UpdatedAt:   time.Now(),
             DigitalProduct: &domain.DigitalProduct{
@@ -332,7 +752,7 @@ func TestListProductsByType(t *testing.T) {
                 Id:          products[0].ID.String(),
                 Name:        "Digital Product 1",
                 Description: "Digital Description 1",
-                Price:       100.0,
+                Price:       usd(100, 0),
                 CreatedAt:   timestamppb.New(products[0].CreatedAt),
                 UpdatedAt:   timestamppb.New(products[0].UpdatedAt),
                 ProductType: &pb.Product_DigitalProduct{
@@ -349,10 +769,13 @@ func TestListProductsByType(t *testing.T) {
     t.Logf("Expected response: %+v", expectedResponse)
 
     // Set up mock expectations for "digital" products
-    mockRepo.On("GetDigitalProducts", mock.Anything).Return(products, nil)
+    mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(opts domain.ProductListOptions) bool {
+        return opts.Filter.Type == "digital"
+    })).Return(products, int64(len(products)), nil)
     
     // Set up mock expectation for Create (even though it's not needed for this test)
     mockRepo.On("Create", mock.Anything).Return(nil) 
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, mock.Anything, mock.Anything).Return([]domain.AttributeDefinition(nil), nil)
 
     // Create the service instance using the constructor
     productService := service.NewProductService(mockRepo)
@@ -377,9 +800,1789 @@ func TestListProductsByType(t *testing.T) {
     assert.Equal(t, expectedResponse.GetProducts()[0].GetId(), resp.GetProducts()[0].GetId())
     assert.Equal(t, expectedResponse.GetProducts()[0].GetName(), resp.GetProducts()[0].GetName())
     assert.Equal(t, expectedResponse.GetProducts()[0].GetDescription(), resp.GetProducts()[0].GetDescription())
-    assert.Equal(t, expectedResponse.GetProducts()[0].GetPrice(), resp.GetProducts()[0].GetPrice())
+    assert.True(t, proto.Equal(expectedResponse.GetProducts()[0].GetPrice(), resp.GetProducts()[0].GetPrice()))
     assert.Equal(t, expectedResponse.GetProducts()[0].GetCreatedAt().AsTime(), resp.GetProducts()[0].GetCreatedAt().AsTime())
     assert.Equal(t, expectedResponse.GetProducts()[0].GetUpdatedAt().AsTime(), resp.GetProducts()[0].GetUpdatedAt().AsTime())
+
+    // A misspelt type is rejected rather than ignored
+    _, err = productService.ListProducts(context.Background(), &pb.ListProductsRequest{Type: "physcial"})
+    assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+    mockRepo.AssertNumberOfCalls(t, "ListProducts", 1)
+}
+
+func TestListProductsPagination(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+
+    // Three rows come back for a page size of two, so a next page exists
+    page := []domain.Product{
+        {ID: uuid.New(), Name: "Alpha", Price: domain.Money{Amount: domain.MustParseAmount("10"), Currency: "USD"}},
+        {ID: uuid.New(), Name: "Beta", Price: domain.Money{Amount: domain.MustParseAmount("20.5"), Currency: "USD"}},
+        {ID: uuid.New(), Name: "Gamma", Price: domain.Money{Amount: domain.MustParseAmount("30"), Currency: "USD"}},
+    }
+    mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(opts domain.ProductListOptions) bool {
+        return opts.After == nil
+    })).Return(page, int64(5), nil).Once()
+
+    req := &pb.ListProductsRequest{PageSize: 2, OrderBy: "price desc", MinPrice: usd(5, 0), NamePrefix: "a"}
+    resp, err := productService.ListProducts(context.Background(), req)
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetProducts(), 2)
+    assert.Equal(t, int32(5), resp.GetTotalSize())
+    assert.NotEmpty(t, resp.GetNextPageToken())
+
+    opts := mockRepo.Calls[0].Arguments.Get(1).(domain.ProductListOptions)
+    assert.Equal(t, domain.ProductSortByPrice, opts.OrderBy)
+    assert.True(t, opts.Descending)
+    assert.Equal(t, 3, opts.Limit)
+    assert.Equal(t, domain.Money{Amount: domain.Amount{Units: 5}, Currency: "USD"}, *opts.Filter.MinPrice)
+    assert.Equal(t, "a", opts.Filter.NamePrefix)
+
+    // The token resumes after the last returned product
+    mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(opts domain.ProductListOptions) bool {
+        return opts.After != nil && opts.After.ID == page[1].ID && opts.After.Price == page[1].Price.Amount
+    })).Return(page[2:], int64(5), nil).Once()
+
+    req.PageToken = resp.GetNextPageToken()
+    resp, err = productService.ListProducts(context.Background(), req)
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetProducts(), 1)
+    assert.Empty(t, resp.GetNextPageToken())
+
+    // A token cannot be reused with a different ordering
+    req.OrderBy = "name"
+    _, err = productService.ListProducts(context.Background(), req)
+    assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+    _, err = productService.ListProducts(context.Background(), &pb.ListProductsRequest{OrderBy: "weight"})
+    assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+    // Price bounds must name a known currency, and the same one
+    _, err = productService.ListProducts(context.Background(), &pb.ListProductsRequest{MinPrice: &moneypb.Money{CurrencyCode: "XYZ", Units: 1}})
+    assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+    _, err = productService.ListProducts(context.Background(), &pb.ListProductsRequest{
+        MinPrice: usd(1, 0),
+        MaxPrice: &moneypb.Money{CurrencyCode: "EUR", Units: 2},
+    })
+    assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+}
+
+func TestSearchProducts(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    hits := []domain.ProductSearchHit{
+        {
+            Product:            domain.Product{ID: uuid.New(), Name: "Wireless Headphones", PhysicalProduct: &domain.PhysicalProduct{Weight: domain.Weight{Value: 0.3, Unit: domain.WeightUnitKilogram}}},
+            Rank:               0.8,
+            NameHighlight:      "Wireless <b>Headphones</b>",
+            DescriptionSnippet: "Noise cancelling <b>headphones</b>",
+        },
+    }
+    mockRepo.On("SearchProducts", mock.Anything, domain.ProductSearchOptions{
+        Query:    "headphnes",
+        Types:    []string{"physical"},
+        Statuses: []domain.ProductStatus{domain.ProductStatusPublished},
+        Limit:    51,
+    }).Return(hits, nil)
+
+    resp, err := handler.SearchProducts(context.Background(), &pb.SearchProductsRequest{Query: " headphnes ", Types: []string{"physical"}})
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetResults(), 1)
+    assert.Equal(t, hits[0].Product.ID.String(), resp.GetResults()[0].GetProduct().GetId())
+    assert.Equal(t, "Wireless <b>Headphones</b>", resp.GetResults()[0].GetNameHighlight())
+    assert.Empty(t, resp.GetNextPageToken())
+
+    // An empty query is rejected before reaching the database
+    _, err = handler.SearchProducts(context.Background(), &pb.SearchProductsRequest{Query: "  "})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetProductReturnsTypeDetails(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    physical := &domain.Product{
+        ID:              uuid.New(),
+        Name:            "Desk Lamp",
+        Status:          domain.ProductStatusPublished,
+        PhysicalProduct: &domain.PhysicalProduct{Weight: domain.Weight{Value: 1.2, Unit: domain.WeightUnitKilogram}, Dimensions: domain.Dimensions{Length: 10, Width: 10, Height: 40, Unit: domain.LengthUnitCentimeter}},
+    }
+    subscription := &domain.Product{
+        ID:                  uuid.New(),
+        Name:                "Streaming",
+        Status:              domain.ProductStatusPublished,
+        SubscriptionProduct: &domain.SubscriptionProduct{SubscriptionPeriod: "monthly", RenewalPrice: domain.Money{Amount: domain.MustParseAmount("9.99"), Currency: "USD"}},
+    }
+    mockRepo.On("GetByID", physical.ID).Return(physical, nil)
+    mockRepo.On("GetByID", subscription.ID).Return(subscription, nil)
+
+    resp, err := handler.GetProduct(context.Background(), &pb.GetProductRequest{Id: physical.ID.String()})
+    assert.NoError(t, err)
+    assert.True(t, proto.Equal(&pb.Dimensions{Length: 10, Width: 10, Height: 40, Unit: pb.Dimensions_CM}, resp.GetPhysicalProduct().GetDimensions()))
+    assert.True(t, proto.Equal(&pb.Weight{Value: 1.2, Unit: pb.Weight_KG}, resp.GetPhysicalProduct().GetWeight()))
+
+    resp, err = handler.GetProduct(context.Background(), &pb.GetProductRequest{Id: subscription.ID.String()})
+    assert.NoError(t, err)
+    assert.Equal(t, "monthly", resp.GetSubscriptionProduct().GetSubscriptionPeriod())
+    assert.True(t, proto.Equal(usd(9, 990000000), resp.GetSubscriptionProduct().GetRenewalPrice()))
+    assert.Nil(t, resp.GetDigitalProduct())
+}
+
+func TestGetProductErrorCodes(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    missing := uuid.New()
+    mockRepo.On("GetByID", missing).Return(nil, fmt.Errorf("product %w", domain.ErrNotFound))
+
+    _, err := handler.GetProduct(context.Background(), &pb.GetProductRequest{Id: "not-a-uuid"})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    _, err = handler.GetProduct(context.Background(), &pb.GetProductRequest{Id: missing.String()})
+    assert.Equal(t, codes.NotFound, status.Code(err))
+    _, err = handler.DeleteProduct(context.Background(), &pb.DeleteProductRequest{Id: "not-a-uuid", Etag: "1"})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUpdateProductWithFieldMask(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    stored := &domain.Product{
+        ID:              uuid.New(),
+        Name:            "Desk Lamp",
+        Description:     "Adjustable LED lamp",
+        Price:           domain.Money{Amount: domain.MustParseAmount("49.99"), Currency: "USD"},
+        Version:         1,
+        PhysicalProduct: &domain.PhysicalProduct{Weight: domain.Weight{Value: 1.2, Unit: domain.WeightUnitKilogram}, Dimensions: domain.Dimensions{Length: 10, Width: 10, Height: 40, Unit: domain.LengthUnitCentimeter}},
+    }
+    mockRepo.On("GetByID", stored.ID).Return(stored, nil)
+    mockRepo.On("Update", mock.Anything).Return(nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, mock.Anything, mock.Anything).Return([]domain.AttributeDefinition(nil), nil)
+
+    resp, err := handler.UpdateProduct(context.Background(), &pb.UpdateProductRequest{
+        Product: &pb.Product{
+            Id:    stored.ID.String(),
+            Etag:  domain.FormatETag(1),
+            Price: usd(39, 990000000),
+            ProductType: &pb.Product_PhysicalProduct{
+                PhysicalProduct: &pb.PhysicalProduct{Weight: &pb.Weight{Value: 1.5, Unit: pb.Weight_KG}},
+            },
+        },
+        UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"price", "physical_product.weight"}},
+    })
+    assert.NoError(t, err)
+
+    // Fields outside the mask keep their stored values
+    assert.Equal(t, "Adjustable LED lamp", resp.GetDescription())
+    assert.True(t, proto.Equal(usd(39, 990000000), resp.GetPrice()))
+    assert.Equal(t, 1.5, resp.GetPhysicalProduct().GetWeight().GetValue())
+    assert.Equal(t, 40.0, resp.GetPhysicalProduct().GetDimensions().GetHeight())
+
+    // Unknown paths and paths for another product kind are rejected
+    for _, path := range []string{"id", "colour", "digital_product.file_size"} {
+        _, err = handler.UpdateProduct(context.Background(), &pb.UpdateProductRequest{
+            Product:    &pb.Product{Id: stored.ID.String(), Etag: domain.FormatETag(1)},
+            UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{path}},
+        })
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), path)
+    }
+    mockRepo.AssertNumberOfCalls(t, "Update", 1)
 }
 
+func TestUpdateProductRejectsStaleEtag(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    stored := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Version: 3}
+    mockRepo.On("GetByID", stored.ID).Return(stored, nil)
+
+    // Missing etag
+    _, err := handler.UpdateProduct(context.Background(), &pb.UpdateProductRequest{
+        Product: &pb.Product{Id: stored.ID.String(), Name: "Floor Lamp"},
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+    // Etag from an older read
+    _, err = handler.UpdateProduct(context.Background(), &pb.UpdateProductRequest{
+        Product: &pb.Product{Id: stored.ID.String(), Name: "Floor Lamp", Etag: domain.FormatETag(2)},
+    })
+    assert.Equal(t, codes.Aborted, status.Code(err))
+    mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+    // A concurrent writer detected by the conditional delete
+    mockRepo.On("Delete", stored.ID, int64(3)).Return(fmt.Errorf("product was modified concurrently: %w", domain.ErrVersionConflict))
+    _, err = handler.DeleteProduct(context.Background(), &pb.DeleteProductRequest{Id: stored.ID.String(), Etag: domain.FormatETag(3)})
+    assert.Equal(t, codes.Aborted, status.Code(err))
+}
+
+func TestRestoreProduct(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    handler.AdminToken = "secret"
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "secret"))
+
+    restored := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Version: 3}
+    mockRepo.On("Restore", restored.ID, int64(2)).Return(nil)
+    mockRepo.On("GetByID", restored.ID).Return(restored, nil)
+
+    // Only admins can bring back a product
+    _, err := handler.RestoreProduct(context.Background(), &pb.RestoreProductRequest{Id: restored.ID.String(), Etag: domain.FormatETag(2)})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+    mockRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+
+    resp, err := handler.RestoreProduct(ctx, &pb.RestoreProductRequest{Id: restored.ID.String(), Etag: domain.FormatETag(2)})
+    assert.NoError(t, err)
+    assert.Equal(t, domain.FormatETag(3), resp.GetEtag())
+    assert.Nil(t, resp.GetDeletedAt())
+
+    // Restoring a product that is not deleted
+    live := uuid.New()
+    mockRepo.On("Restore", live, int64(1)).Return(fmt.Errorf("product is not deleted: %w", domain.ErrFailedPrecondition))
+    _, err = handler.RestoreProduct(ctx, &pb.RestoreProductRequest{Id: live.String(), Etag: domain.FormatETag(1)})
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
 
+func TestPurgeProductIsAdminOnly(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    handler.AdminToken = "secret"
+
+    id := uuid.New()
+    mockRepo.On("Purge", id).Return(nil)
+
+    _, err := handler.PurgeProduct(context.Background(), &pb.PurgeProductRequest{Id: id.String()})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+    wrongToken := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "guess"))
+    _, err = handler.PurgeProduct(wrongToken, &pb.PurgeProductRequest{Id: id.String()})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+    mockRepo.AssertNotCalled(t, "Purge", mock.Anything)
+
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "secret"))
+    _, err = handler.PurgeProduct(ctx, &pb.PurgeProductRequest{Id: id.String()})
+    assert.NoError(t, err)
+    mockRepo.AssertCalled(t, "Purge", id)
+}
+
+func TestBatchDeleteProducts(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    deleted, stale := uuid.New(), uuid.New()
+    mockRepo.On("Delete", deleted, int64(1)).Return(nil)
+    mockRepo.On("Delete", stale, int64(1)).Return(fmt.Errorf("modified concurrently: %w", domain.ErrVersionConflict))
+
+    requests := []*pb.DeleteProductRequest{
+        {Id: deleted.String(), Etag: domain.FormatETag(1)},
+        {Id: "not-a-uuid", Etag: domain.FormatETag(1)},
+        {Id: stale.String(), Etag: domain.FormatETag(1)},
+    }
+
+    // Per-item results line up with the request
+    resp, err := handler.BatchDeleteProducts(context.Background(), &pb.BatchDeleteProductsRequest{Requests: requests})
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetResults(), 3)
+    assert.Nil(t, resp.GetResults()[0].GetStatus())
+    assert.Equal(t, int32(codes.InvalidArgument), resp.GetResults()[1].GetStatus().GetCode())
+    assert.Equal(t, int32(codes.Aborted), resp.GetResults()[2].GetStatus().GetCode())
+
+    // All-or-nothing fails the whole call on the first bad item
+    _, err = handler.BatchDeleteProducts(context.Background(), &pb.BatchDeleteProductsRequest{Requests: requests, AllOrNothing: true})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+    _, err = handler.BatchDeleteProducts(context.Background(), &pb.BatchDeleteProductsRequest{Requests: []*pb.DeleteProductRequest{requests[0], requests[2]}, AllOrNothing: true})
+    assert.Equal(t, codes.Aborted, status.Code(err))
+    assert.Contains(t, status.Convert(err).Message(), "item 1")
+}
+
+// usd builds a US dollar amount for requests
+func usd(units int64, nanos int32) *moneypb.Money {
+    return &moneypb.Money{CurrencyCode: "USD", Units: units, Nanos: nanos}
+}
+
+// startProductServer serves the handler over an in-memory connection so
+// streaming RPCs can be exercised end to end
+func startProductServer(t *testing.T, handler *grpc.ProductHandler) pb.ProductServiceClient {
+    listener := bufconn.Listen(1024 * 1024)
+    server := grpclib.NewServer()
+    pb.RegisterProductServiceServer(server, handler)
+    go server.Serve(listener)
+    t.Cleanup(server.Stop)
+
+    conn, err := grpclib.NewClient("passthrough:///bufnet",
+        grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+        grpclib.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatalf("Failed to dial test server: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return pb.NewProductServiceClient(conn)
+}
+
+func TestImportProductsCSV(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    client := startProductServer(t, grpc.NewProductHandler(service.NewProductService(mockRepo)))
+
+    existing := &domain.Product{ID: uuid.New(), Name: "Old Lamp", Price: domain.Money{Amount: domain.MustParseAmount("29.99"), Currency: "USD"}, Version: 2, PhysicalProduct: &domain.PhysicalProduct{Weight: domain.Weight{Value: 1, Unit: domain.WeightUnitKilogram}}}
+    mockRepo.On("FindByExternalSKU", "LAMP-1").Return(existing, nil)
+    mockRepo.On("FindByExternalSKU", "EBOOK-1").Return(nil, fmt.Errorf("product %w", domain.ErrNotFound))
+    mockRepo.On("FindByExternalSKU", "NONAME-1").Return(nil, fmt.Errorf("product %w", domain.ErrNotFound))
+    mockRepo.On("GetByID", existing.ID).Return(existing, nil)
+    mockRepo.On("Update", mock.Anything).Return(nil)
+    mockRepo.On("Create", mock.Anything).Return(nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, mock.Anything, mock.Anything).Return([]domain.AttributeDefinition(nil), nil)
+
+    file := "external_sku,type,name,price,currency,weight,weight_unit,file_size,download_link\n" +
+        "LAMP-1,physical,Desk Lamp,39.99,usd,1.4,kg,,\n" +
+        "EBOOK-1,digital,\"Go, in practice\",9.99,EUR,,,2048,http://example.com/go.pdf\n" +
+        "BAD-1,physical,Broken,not-a-price,USD,,,,\n" +
+        "NONAME-1,digital,,5,USD,,,1,\n"
+
+    stream, err := client.ImportProducts(context.Background())
+    assert.NoError(t, err)
+    assert.NoError(t, stream.Send(&pb.ImportProductsRequest{Payload: &pb.ImportProductsRequest_Format{Format: pb.ImportFormat_IMPORT_FORMAT_CSV}}))
+    // Split the file mid-row to check chunks are reassembled
+    for _, chunk := range []string{file[:50], file[50:]} {
+        assert.NoError(t, stream.Send(&pb.ImportProductsRequest{Payload: &pb.ImportProductsRequest_Data{Data: []byte(chunk)}}))
+    }
+    summary, err := stream.CloseAndRecv()
+    assert.NoError(t, err)
+
+    assert.Equal(t, int32(1), summary.GetCreated())
+    assert.Equal(t, int32(1), summary.GetUpdated())
+    assert.Equal(t, int32(2), summary.GetFailed())
+    assert.Len(t, summary.GetRows(), 4)
+    assert.Equal(t, pb.ImportRowResult_UPDATED, summary.GetRows()[0].GetOutcome())
+    assert.Equal(t, existing.ID.String(), summary.GetRows()[0].GetProductId())
+    assert.Equal(t, pb.ImportRowResult_CREATED, summary.GetRows()[1].GetOutcome())
+    assert.Equal(t, int32(4), summary.GetRows()[2].GetLine())
+    assert.Equal(t, int32(5), summary.GetRows()[3].GetLine())
+    assert.Contains(t, summary.GetRows()[3].GetError(), "name cannot be empty")
+    assert.Equal(t, "Desk Lamp", existing.Name)
+    assert.Equal(t, domain.Money{Amount: domain.MustParseAmount("39.99"), Currency: "USD"}, existing.Price)
+}
+
+func TestImportUpdatesTagsAndCategoriesOfExistingSKU(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    client := startProductServer(t, grpc.NewProductHandler(service.NewProductService(mockRepo)))
+
+    categoryID := uuid.New()
+    existing := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Price: domain.Money{Amount: domain.MustParseAmount("40"), Currency: "USD"}, Tags: domain.Tags{"new"}, Version: 4, PhysicalProduct: &domain.PhysicalProduct{}}
+    mockRepo.On("FindByExternalSKU", "LAMP-1").Return(existing, nil)
+    mockRepo.On("GetByID", existing.ID).Return(existing, nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, mock.Anything, mock.Anything).Return([]domain.AttributeDefinition(nil), nil)
+    mockRepo.On("Update", mock.Anything).Return(nil)
+
+    // The row an export of the lamp would hold, with its tags and categories changed
+    row := fmt.Sprintf(`{"externalSku": "LAMP-1", "name": "Desk Lamp", "price": {"currencyCode": "USD", "units": "40"}, `+
+        `"physicalProduct": {}, "tags": ["Sale", "lighting"], "categoryIds": [%q]}`, categoryID.String())
+    stream, err := client.ImportProducts(context.Background())
+    require.NoError(t, err)
+    require.NoError(t, stream.Send(&pb.ImportProductsRequest{Payload: &pb.ImportProductsRequest_Format{Format: pb.ImportFormat_IMPORT_FORMAT_JSONL}}))
+    require.NoError(t, stream.Send(&pb.ImportProductsRequest{Payload: &pb.ImportProductsRequest_Data{Data: []byte(row + "\n")}}))
+    summary, err := stream.CloseAndRecv()
+    require.NoError(t, err)
+
+    assert.Equal(t, int32(1), summary.GetUpdated(), summary.GetRows())
+    assert.Equal(t, domain.Tags{"sale", "lighting"}, existing.Tags)
+    require.Len(t, existing.Categories, 1)
+    assert.Equal(t, categoryID, existing.Categories[0].ID)
+    mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestImportBundleLooksUpComponentsOnce(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+
+    lamp := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Status: domain.ProductStatusPublished, Price: domain.Money{Amount: domain.MustParseAmount("40"), Currency: "USD"}, PhysicalProduct: &domain.PhysicalProduct{}}
+    mockRepo.On("GetByID", lamp.ID).Return(lamp, nil)
+    mockRepo.On("FindByExternalSKU", "KIT-1").Return(nil, fmt.Errorf("product %w", domain.ErrNotFound))
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, "bundle", mock.Anything).Return([]domain.AttributeDefinition{}, nil)
+    mockRepo.On("Create", mock.Anything).Return(nil)
+
+    sku := "KIT-1"
+    created, product, err := productService.ImportProduct(context.Background(), &domain.Product{
+        ExternalSKU: &sku,
+        Name:        "Lamp Kit",
+        BundleProduct: &domain.BundleProduct{
+            Pricing:         domain.BundlePricingPercentOff,
+            DiscountPercent: domain.MustParseAmount("10"),
+            Components:      []domain.BundleComponent{{ProductID: lamp.ID, Quantity: 2}},
+        },
+    })
+    assert.NoError(t, err)
+    assert.True(t, created)
+    assert.Equal(t, domain.MustParseAmount("72"), product.Price.Amount)
+    mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
+
+func TestExportProducts(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    handler.AdminToken = "secret"
+    client := startProductServer(t, handler)
+
+    productID := uuid.New()
+    exported := []domain.ExportedProduct{
+        {Product: domain.Product{ID: uuid.New(), Name: "E-book", Version: 1, DigitalProduct: &domain.DigitalProduct{FileSize: 2048}}},
+        {
+            Product:           domain.Product{ID: productID, Name: "Streaming", Version: 3, SubscriptionProduct: &domain.SubscriptionProduct{SubscriptionPeriod: "monthly"}},
+            SubscriptionPlans: []domain.SubscriptionPlan{{ID: uuid.New(), ProductID: productID, PlanName: "Basic", Duration: 30, Price: domain.Money{Amount: domain.MustParseAmount("9.99"), Currency: "USD"}, Version: 1}},
+        },
+    }
+    mockRepo.On("ExportProducts", mock.Anything, domain.ProductFilter{ShowDeleted: true, Statuses: []domain.ProductStatus{}}).Return(exported, nil)
+
+    admin := metadata.AppendToOutgoingContext(context.Background(), "x-admin-token", "secret")
+    stream, err := client.ExportProducts(admin, &pb.ExportProductsRequest{ShowDeleted: true})
+    assert.NoError(t, err)
+    var received []*pb.ExportedProduct
+    for {
+        product, err := stream.Recv()
+        if err == io.EOF {
+            break
+        }
+        require.NoError(t, err)
+        received = append(received, product)
+    }
+
+    assert.Len(t, received, 2)
+    assert.Equal(t, int32(2048), received[0].GetProduct().GetDigitalProduct().GetFileSize())
+    assert.Empty(t, received[0].GetSubscriptionPlans())
+    assert.Equal(t, `W/"3"`, received[1].GetProduct().GetEtag())
+    assert.Len(t, received[1].GetSubscriptionPlans(), 1)
+    assert.Equal(t, "Basic", received[1].GetSubscriptionPlans()[0].GetPlanName())
+
+    stream, err = client.ExportProducts(admin, &pb.ExportProductsRequest{Type: "furniture"})
+    assert.NoError(t, err)
+    _, err = stream.Recv()
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+    // Other callers only export published products that are not deleted
+    mockRepo.On("ExportProducts", mock.Anything, domain.ProductFilter{
+        Statuses: []domain.ProductStatus{domain.ProductStatusPublished},
+    }).Return(exported[:1], nil)
+    stream, err = client.ExportProducts(context.Background(), &pb.ExportProductsRequest{})
+    assert.NoError(t, err)
+    _, err = stream.Recv()
+    assert.NoError(t, err)
+    stream, err = client.ExportProducts(context.Background(), &pb.ExportProductsRequest{ShowDeleted: true})
+    assert.NoError(t, err)
+    _, err = stream.Recv()
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestWatchProducts(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    client := startProductServer(t, grpc.NewProductHandler(service.NewProductService(mockRepo)))
+
+    product := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Status: domain.ProductStatusPublished, Version: 2}
+    planID := uuid.New()
+    events := []domain.ProductEvent{
+        {Sequence: 6, TransactionID: 10, Resource: domain.ProductEventResourceProduct, ResourceID: product.ID, ProductID: product.ID, Type: domain.ProductEventUpdated, Product: product},
+        {Sequence: 8, TransactionID: 11, Resource: domain.ProductEventResourceSubscriptionPlan, ResourceID: planID, ProductID: product.ID, Type: domain.ProductEventDeleted},
+    }
+    mockRepo.On("LatestProductEventPosition", mock.Anything).Return(domain.ProductEventPosition{TransactionID: 9, Sequence: 5}, nil)
+    // Plan events are only sent to callers that can see the product
+    mockRepo.On("GetByID", product.ID).Return(product, nil)
+    mockRepo.On("ListProductEvents", mock.Anything, domain.ProductEventPosition{TransactionID: 9, Sequence: 5}, 500).Return(events, nil)
+    mockRepo.On("ListProductEvents", mock.Anything, domain.ProductEventPosition{TransactionID: 10, Sequence: 6}, 500).Return(events[1:], nil)
+    mockRepo.On("ListProductEvents", mock.Anything, domain.ProductEventPosition{TransactionID: 11, Sequence: 8}, 500).Return([]domain.ProductEvent{}, nil)
+
+    ctx, cancel := context.WithCancel(context.Background())
+    stream, err := client.WatchProducts(ctx, &pb.WatchProductsRequest{})
+    assert.NoError(t, err)
+    first, err := stream.Recv()
+    assert.NoError(t, err)
+    second, err := stream.Recv()
+    assert.NoError(t, err)
+    cancel()
+
+    assert.Equal(t, pb.ProductEvent_UPDATED, first.GetType())
+    assert.Equal(t, "Desk Lamp", first.GetProduct().GetName())
+    assert.Equal(t, pb.ProductEvent_DELETED, second.GetType())
+    assert.Equal(t, planID.String(), second.GetSubscriptionPlan().GetId())
+    assert.Equal(t, product.ID.String(), second.GetSubscriptionPlan().GetProductId())
+
+    // Resuming after the first event replays the second one
+    ctx, cancel = context.WithCancel(context.Background())
+    defer cancel()
+    stream, err = client.WatchProducts(ctx, &pb.WatchProductsRequest{ResumeToken: first.GetResumeToken()})
+    assert.NoError(t, err)
+    replayed, err := stream.Recv()
+    assert.NoError(t, err)
+    assert.Equal(t, second.GetResumeToken(), replayed.GetResumeToken())
+
+    stream, err = client.WatchProducts(context.Background(), &pb.WatchProductsRequest{ResumeToken: "not-a-token"})
+    assert.NoError(t, err)
+    _, err = stream.Recv()
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetPrice(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    product := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Status: domain.ProductStatusPublished, Price: domain.Money{Amount: domain.MustParseAmount("49.99"), Currency: "USD"}}
+    plan := &domain.SubscriptionPlan{ID: uuid.New(), ProductID: product.ID, PlanName: "Care", Price: domain.Money{Amount: domain.MustParseAmount("4.99"), Currency: "USD"}}
+    mockRepo.On("GetByID", product.ID).Return(product, nil)
+    mockRepo.On("GetSubscriptionPlan", mock.Anything, product.ID, plan.ID).Return(plan, nil)
+
+    at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+    until := at.AddDate(0, 1, 0)
+    defaultEUR := domain.PriceListEntry{ID: uuid.New(), ProductID: product.ID, Price: domain.Money{Amount: domain.MustParseAmount("45"), Currency: "EUR"}, Version: 1}
+    germany := domain.PriceListEntry{ID: uuid.New(), ProductID: product.ID, Region: "DE", Price: domain.Money{Amount: domain.MustParseAmount("42.5"), Currency: "EUR"}, EffectiveTo: &until, Version: 1}
+    mockRepo.On("FindEffectivePrices", mock.Anything, mock.MatchedBy(func(query domain.PriceQuery) bool {
+        return query.Currency == "EUR" && query.SubscriptionPlanID == nil
+    })).Return([]domain.PriceListEntry{defaultEUR, germany}, nil)
+    mockRepo.On("FindEffectivePrices", mock.Anything, mock.Anything).Return([]domain.PriceListEntry{}, nil)
+
+    // The region's own entry wins over the default region
+    resp, err := handler.GetPrice(context.Background(), &pb.GetPriceRequest{
+        ProductId: product.ID.String(), Region: "de", CurrencyCode: "EUR", AtTime: timestamppb.New(at),
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, pb.ResolvedPrice_REGION, resp.GetSource())
+    assert.Equal(t, germany.ID.String(), resp.GetEntry().GetId())
+    assert.True(t, proto.Equal(&moneypb.Money{CurrencyCode: "EUR", Units: 42, Nanos: 500000000}, resp.GetPrice()))
+
+    // After the German entry ends, and in other regions, the default applies
+    for _, req := range []*pb.GetPriceRequest{
+        {ProductId: product.ID.String(), Region: "DE", CurrencyCode: "EUR", AtTime: timestamppb.New(until)},
+        {ProductId: product.ID.String(), Region: "FR", CurrencyCode: "EUR", AtTime: timestamppb.New(at)},
+    } {
+        resp, err = handler.GetPrice(context.Background(), req)
+        assert.NoError(t, err)
+        assert.Equal(t, pb.ResolvedPrice_DEFAULT_REGION, resp.GetSource(), req.GetRegion())
+        assert.Equal(t, defaultEUR.ID.String(), resp.GetEntry().GetId())
+    }
+
+    // Without a price list entry the base price of the product or plan is used,
+    // in its own currency
+    resp, err = handler.GetPrice(context.Background(), &pb.GetPriceRequest{ProductId: product.ID.String(), Region: "US"})
+    assert.NoError(t, err)
+    assert.Equal(t, pb.ResolvedPrice_BASE_PRICE, resp.GetSource())
+    assert.True(t, proto.Equal(usd(49, 990000000), resp.GetPrice()))
+
+    resp, err = handler.GetPrice(context.Background(), &pb.GetPriceRequest{ProductId: product.ID.String(), SubscriptionPlanId: plan.ID.String()})
+    assert.NoError(t, err)
+    assert.True(t, proto.Equal(usd(4, 990000000), resp.GetPrice()))
+
+    // A currency nothing is priced in is not found
+    _, err = handler.GetPrice(context.Background(), &pb.GetPriceRequest{ProductId: product.ID.String(), CurrencyCode: "GBP"})
+    assert.Equal(t, codes.NotFound, status.Code(err))
+    _, err = handler.GetPrice(context.Background(), &pb.GetPriceRequest{ProductId: product.ID.String(), Region: "E U"})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreatePriceListEntry(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    productID := uuid.New()
+    mockRepo.On("CreatePriceListEntry", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+        entry := args.Get(1).(*domain.PriceListEntry)
+        entry.ID, entry.Version = uuid.New(), 1
+    })
+
+    from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+    created, err := handler.CreatePriceListEntry(context.Background(), &pb.PriceListEntry{
+        ProductId:     productID.String(),
+        Region:        " eu ",
+        Price:         &moneypb.Money{CurrencyCode: "EUR", Units: 19},
+        EffectiveFrom: timestamppb.New(from),
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, "EU", created.GetRegion())
+    assert.Equal(t, `W/"1"`, created.GetEtag())
+    assert.Nil(t, created.GetEffectiveTo())
+
+    for _, entry := range []*pb.PriceListEntry{
+        {ProductId: productID.String(), Price: &moneypb.Money{CurrencyCode: "EUR", Units: -1}},
+        {ProductId: productID.String(), Price: &moneypb.Money{Units: 1}},
+        {ProductId: productID.String(), Price: usd(1, 0), EffectiveFrom: timestamppb.New(from), EffectiveTo: timestamppb.New(from)},
+        {ProductId: productID.String(), Price: usd(1, 0), SubscriptionPlanId: "plan"},
+    } {
+        _, err = handler.CreatePriceListEntry(context.Background(), entry)
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), entry.String())
+    }
+    mockRepo.AssertNumberOfCalls(t, "CreatePriceListEntry", 1)
+
+    // Overlapping entries are reported by the repository
+    mockRepo.ExpectedCalls = nil
+    mockRepo.On("CreatePriceListEntry", mock.Anything, mock.Anything).Return(fmt.Errorf("%w: overlap", domain.ErrAlreadyExists))
+    _, err = handler.CreatePriceListEntry(context.Background(), &pb.PriceListEntry{ProductId: productID.String(), Price: usd(1, 0)})
+    assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestListPriceHistory(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    productID, changeID := uuid.New(), uuid.New()
+    mockRepo.On("GetByID", productID).Return(&domain.Product{ID: productID, Name: "Desk Lamp", Status: domain.ProductStatusPublished}, nil)
+
+    history := []domain.PriceChange{
+        {ID: 9, ProductID: productID, Price: domain.Money{Amount: domain.MustParseAmount("12"), Currency: "USD"}, ScheduledPriceChangeID: &changeID, ChangedAt: time.Now()},
+        {ID: 7, ProductID: productID, Price: domain.Money{Amount: domain.MustParseAmount("11"), Currency: "USD"}, ChangedAt: time.Now()},
+        {ID: 3, ProductID: productID, Price: domain.Money{Amount: domain.MustParseAmount("10"), Currency: "USD"}, ChangedAt: time.Now()},
+    }
+    // The first page asks for one row more than it returns to detect a next page
+    mockRepo.On("ListPriceHistory", mock.Anything, domain.PriceHistoryOptions{ProductID: productID, Limit: 3}).Return(history, nil)
+    mockRepo.On("ListPriceHistory", mock.Anything, domain.PriceHistoryOptions{ProductID: productID, Limit: 3, BeforeID: 7}).Return(history[2:], nil)
+
+    resp, err := handler.ListPriceHistory(context.Background(), &pb.ListPriceHistoryRequest{ProductId: productID.String(), PageSize: 2})
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetEntries(), 2)
+    assert.Equal(t, changeID.String(), resp.GetEntries()[0].GetScheduledPriceChangeId())
+    assert.True(t, proto.Equal(usd(12, 0), resp.GetEntries()[0].GetPrice()))
+    assert.NotEmpty(t, resp.GetNextPageToken())
+
+    resp, err = handler.ListPriceHistory(context.Background(), &pb.ListPriceHistoryRequest{ProductId: productID.String(), PageSize: 2, PageToken: resp.GetNextPageToken()})
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetEntries(), 1)
+    assert.Empty(t, resp.GetNextPageToken())
+
+    _, err = handler.ListPriceHistory(context.Background(), &pb.ListPriceHistoryRequest{ProductId: productID.String(), PageToken: "bogus"})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSchedulePriceChange(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    productID := uuid.New()
+    mockRepo.On("SchedulePriceChange", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+        change := args.Get(1).(*domain.ScheduledPriceChange)
+        change.ID, change.Status, change.Version = uuid.New(), domain.ScheduledPriceChangePending, 1
+    })
+
+    effectiveAt := time.Now().Add(time.Hour)
+    scheduled, err := handler.SchedulePriceChange(context.Background(), &pb.ScheduledPriceChange{
+        ProductId:   productID.String(),
+        Price:       usd(24, 0),
+        EffectiveAt: timestamppb.New(effectiveAt),
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, pb.ScheduledPriceChange_PENDING, scheduled.GetStatus())
+    assert.Equal(t, `W/"1"`, scheduled.GetEtag())
+
+    planID := uuid.New().String()
+    for _, change := range []*pb.ScheduledPriceChange{
+        {ProductId: productID.String(), Price: usd(24, 0)},
+        {ProductId: productID.String(), Price: usd(24, 0), EffectiveAt: timestamppb.New(time.Now().Add(-time.Minute))},
+        {ProductId: productID.String(), Price: usd(-1, 0), EffectiveAt: timestamppb.New(effectiveAt)},
+        {ProductId: productID.String(), SubscriptionPlanId: planID, Price: usd(0, 0), EffectiveAt: timestamppb.New(effectiveAt)},
+        {ProductId: "product", Price: usd(24, 0), EffectiveAt: timestamppb.New(effectiveAt)},
+    } {
+        _, err = handler.SchedulePriceChange(context.Background(), change)
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), change.String())
+    }
+    mockRepo.AssertNumberOfCalls(t, "SchedulePriceChange", 1)
+}
+
+func TestCancelScheduledPriceChange(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    pending, applied := uuid.New(), uuid.New()
+
+    cancelled := &domain.ScheduledPriceChange{ID: pending, ProductID: uuid.New(), Status: domain.ScheduledPriceChangeCancelled, EffectiveAt: time.Now(), Version: 2}
+    mockRepo.On("CancelScheduledPriceChange", mock.Anything, pending, int64(1)).Return(cancelled, nil)
+    mockRepo.On("CancelScheduledPriceChange", mock.Anything, applied, int64(2)).Return(nil, fmt.Errorf("scheduled price change is applied: %w", domain.ErrFailedPrecondition))
+
+    resp, err := handler.CancelScheduledPriceChange(context.Background(), &pb.CancelScheduledPriceChangeRequest{Id: pending.String(), Etag: `W/"1"`})
+    assert.NoError(t, err)
+    assert.Equal(t, pb.ScheduledPriceChange_CANCELLED, resp.GetStatus())
+    assert.Equal(t, `W/"2"`, resp.GetEtag())
+
+    _, err = handler.CancelScheduledPriceChange(context.Background(), &pb.CancelScheduledPriceChangeRequest{Id: applied.String(), Etag: `W/"2"`})
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestApplyDuePriceChanges(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+    now := time.Now()
+
+    // Full batches are followed by another until the queue is drained
+    mockRepo.On("ApplyDuePriceChanges", mock.Anything, now, 100).Return(100, nil).Once()
+    mockRepo.On("ApplyDuePriceChanges", mock.Anything, now, 100).Return(42, nil).Once()
+
+    n, err := productService.ApplyDuePriceChanges(context.Background(), now)
+    assert.NoError(t, err)
+    assert.Equal(t, 142, n)
+    mockRepo.AssertNumberOfCalls(t, "ApplyDuePriceChanges", 2)
+}
+
+
+func TestCreateProductVariant(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    shirt := &domain.Product{
+        ID:              uuid.New(),
+        Name:            "T-Shirt",
+        Price:           domain.Money{Amount: domain.MustParseAmount("20"), Currency: "USD"},
+        OptionNames:     domain.OptionNames{"size", "colour"},
+        PhysicalProduct: &domain.PhysicalProduct{Weight: domain.Weight{Value: 0.2, Unit: domain.WeightUnitKilogram}},
+    }
+    mockRepo.On("GetByID", shirt.ID).Return(shirt, nil)
+    mockRepo.On("CreateVariant", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+        variant := args.Get(1).(*domain.ProductVariant)
+        variant.ID, variant.Version = uuid.New(), 1
+    })
+
+    created, err := handler.CreateProductVariant(context.Background(), &pb.ProductVariant{
+        ProductId:      shirt.ID.String(),
+        Sku:            " TS-M-RED ",
+        Barcode:        "4006381333931",
+        Options:        map[string]string{"size": "M", "colour": " red"},
+        PriceOverride:  usd(22, 500000000),
+        WeightOverride: &pb.Weight{Value: 9, Unit: pb.Weight_OZ},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, "TS-M-RED", created.GetSku())
+    assert.Equal(t, map[string]string{"size": "M", "colour": "red"}, created.GetOptions())
+    assert.True(t, proto.Equal(usd(22, 500000000), created.GetPriceOverride()))
+    assert.True(t, proto.Equal(&pb.Weight{Value: 9, Unit: pb.Weight_OZ}, created.GetWeightOverride()))
+    assert.Equal(t, `W/"1"`, created.GetEtag())
+
+    for _, variant := range []*pb.ProductVariant{
+        {ProductId: shirt.ID.String(), Options: map[string]string{"size": "M", "colour": "red"}},
+        {ProductId: shirt.ID.String(), Sku: "TS-1", Options: map[string]string{"size": "M"}},
+        {ProductId: shirt.ID.String(), Sku: "TS-1", Options: map[string]string{"size": "M", "colour": "red", "fit": "slim"}},
+        {ProductId: shirt.ID.String(), Sku: "TS-1", Options: map[string]string{"size": "M", "colour": ""}},
+        {ProductId: shirt.ID.String(), Sku: "TS-1", Options: map[string]string{"size": "M", "colour": "red"}, Barcode: "4006381333932"},
+        {ProductId: shirt.ID.String(), Sku: "TS-1", Options: map[string]string{"size": "M", "colour": "red"}, PriceOverride: &moneypb.Money{CurrencyCode: "EUR", Units: 20}},
+        {ProductId: shirt.ID.String(), Sku: "TS-1", Options: map[string]string{"size": "M", "colour": "red"}, WeightOverride: &pb.Weight{Value: 0.25}},
+        {ProductId: shirt.ID.String(), Sku: "TS-1", Options: map[string]string{"size": "M", "colour": "red"}, WeightOverride: &pb.Weight{Value: -1, Unit: pb.Weight_KG}},
+        {Sku: "TS-1", Options: map[string]string{"size": "M", "colour": "red"}},
+    } {
+        _, err = handler.CreateProductVariant(context.Background(), variant)
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), variant.String())
+    }
+    mockRepo.AssertNumberOfCalls(t, "CreateVariant", 1)
+
+    // Duplicate SKUs are reported by the unique index
+    mockRepo.ExpectedCalls = nil
+    mockRepo.On("GetByID", shirt.ID).Return(shirt, nil)
+    mockRepo.On("CreateVariant", mock.Anything, mock.Anything).Return(fmt.Errorf("%w: another variant has this SKU", domain.ErrAlreadyExists))
+    _, err = handler.CreateProductVariant(context.Background(), &pb.ProductVariant{
+        ProductId: shirt.ID.String(), Sku: "TS-M-RED", Options: map[string]string{"size": "M", "colour": "red"},
+    })
+    assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestUpdateProductVariant(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    ebook := &domain.Product{ID: uuid.New(), Name: "Handbook", Price: domain.Money{Amount: domain.MustParseAmount("15"), Currency: "USD"}, OptionNames: domain.OptionNames{"format"}, DigitalProduct: &domain.DigitalProduct{}}
+    stored := &domain.ProductVariant{ID: uuid.New(), ProductID: ebook.ID, SKU: "HB-PDF", Options: domain.VariantOptions{"format": "pdf"}, Version: 2}
+    mockRepo.On("GetByID", ebook.ID).Return(ebook, nil)
+    mockRepo.On("GetVariant", mock.Anything, stored.ID).Return(stored, nil)
+    mockRepo.On("UpdateVariant", mock.Anything, mock.Anything).Return(nil)
+
+    resp, err := handler.UpdateProductVariant(context.Background(), &pb.UpdateProductVariantRequest{
+        Variant:    &pb.ProductVariant{Id: stored.ID.String(), Etag: `W/"2"`, PriceOverride: usd(12, 0)},
+        UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"price_override"}},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, "HB-PDF", resp.GetSku())
+    assert.True(t, proto.Equal(usd(12, 0), resp.GetPriceOverride()))
+
+    // Digital products have no weight to override
+    _, err = handler.UpdateProductVariant(context.Background(), &pb.UpdateProductVariantRequest{
+        Variant:    &pb.ProductVariant{Id: stored.ID.String(), Etag: `W/"2"`, WeightOverride: &pb.Weight{Value: 1, Unit: pb.Weight_KG}},
+        UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"weight_override"}},
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+    _, err = handler.UpdateProductVariant(context.Background(), &pb.UpdateProductVariantRequest{
+        Variant: &pb.ProductVariant{Id: stored.ID.String(), Etag: `W/"1"`, Sku: "HB-EPUB"},
+    })
+    assert.Equal(t, codes.Aborted, status.Code(err))
+    mockRepo.AssertNumberOfCalls(t, "UpdateVariant", 1)
+}
+
+func TestUpdateProductOptionNamesKeepsVariantsValid(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    stored := &domain.Product{ID: uuid.New(), Name: "T-Shirt", Price: domain.Money{Amount: domain.MustParseAmount("20"), Currency: "USD"}, OptionNames: domain.OptionNames{"size", "colour"}, Version: 1}
+    mockRepo.On("GetByID", stored.ID).Return(stored, nil)
+    // The variants are listed with the context of the request
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "options"))
+    mockRepo.On("ListVariants", mock.MatchedBy(func(c context.Context) bool {
+        md, _ := metadata.FromIncomingContext(c)
+        return len(md.Get("x-request-id")) == 1
+    }), stored.ID).Return([]domain.ProductVariant{
+        {ID: uuid.New(), ProductID: stored.ID, SKU: "TS-M-RED", Options: domain.VariantOptions{"size": "M", "colour": "red"}},
+    }, nil)
+    mockRepo.On("Update", mock.Anything).Return(nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, mock.Anything, mock.Anything).Return([]domain.AttributeDefinition(nil), nil)
+
+    // Dropping an axis the variants use is refused
+    _, err := handler.UpdateProduct(ctx, &pb.UpdateProductRequest{
+        Product:    &pb.Product{Id: stored.ID.String(), Etag: `W/"1"`, OptionNames: []string{"size"}},
+        UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"option_names"}},
+    })
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+    // Reordering the axes is fine
+    resp, err := handler.UpdateProduct(ctx, &pb.UpdateProductRequest{
+        Product:    &pb.Product{Id: stored.ID.String(), Etag: `W/"1"`, OptionNames: []string{"colour", " size"}},
+        UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"option_names"}},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, []string{"colour", "size"}, resp.GetOptionNames())
+    mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+
+func TestCreateCategory(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    parentID := uuid.New()
+    mockRepo.On("CreateCategory", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+        category := args.Get(1).(*domain.Category)
+        category.ID, category.Version = uuid.New(), 1
+        category.Path = domain.CategoryPath("/"+parentID.String()+"/", category.ID)
+    })
+
+    created, err := handler.CreateCategory(context.Background(), &pb.Category{ParentId: parentID.String(), Name: " Lamps "})
+    assert.NoError(t, err)
+    assert.Equal(t, "Lamps", created.GetName())
+    assert.Equal(t, parentID.String(), created.GetParentId())
+    assert.Equal(t, "/"+parentID.String()+"/"+created.GetId()+"/", created.GetPath())
+
+    for _, category := range []*pb.Category{{Name: " "}, {Name: "Lamps", ParentId: "lighting"}} {
+        _, err = handler.CreateCategory(context.Background(), category)
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), category.String())
+    }
+
+    mockRepo.ExpectedCalls = nil
+    mockRepo.On("CreateCategory", mock.Anything, mock.Anything).Return(fmt.Errorf("%w: the parent already has a category named \"Lamps\"", domain.ErrAlreadyExists))
+    _, err = handler.CreateCategory(context.Background(), &pb.Category{ParentId: parentID.String(), Name: "lamps"})
+    assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestUpdateCategory(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    stored := &domain.Category{ID: uuid.New(), Name: "Lamps", Description: "Desk and floor lamps", Version: 3}
+    stored.Path = domain.CategoryPath("", stored.ID)
+    newParent := uuid.New()
+    mockRepo.On("GetCategory", mock.Anything, stored.ID).Return(stored, nil)
+    mockRepo.On("UpdateCategory", mock.Anything, mock.MatchedBy(func(category *domain.Category) bool {
+        return category.ParentID != nil && *category.ParentID == newParent
+    })).Return(nil)
+
+    // Moving keeps the fields outside the mask
+    resp, err := handler.UpdateCategory(context.Background(), &pb.UpdateCategoryRequest{
+        Category:   &pb.Category{Id: stored.ID.String(), Etag: `W/"3"`, ParentId: newParent.String()},
+        UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"parent_id"}},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, "Desk and floor lamps", resp.GetDescription())
+
+    // A category cannot become its own parent
+    _, err = handler.UpdateCategory(context.Background(), &pb.UpdateCategoryRequest{
+        Category:   &pb.Category{Id: stored.ID.String(), Etag: `W/"3"`, ParentId: stored.ID.String()},
+        UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"parent_id"}},
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+    _, err = handler.UpdateCategory(context.Background(), &pb.UpdateCategoryRequest{
+        Category: &pb.Category{Id: stored.ID.String(), Etag: `W/"2"`, Name: "Lighting"},
+    })
+    assert.Equal(t, codes.Aborted, status.Code(err))
+    mockRepo.AssertNumberOfCalls(t, "UpdateCategory", 1)
+}
+
+func TestCategoryPath(t *testing.T) {
+    root := &domain.Category{ID: uuid.New()}
+    root.Path = domain.CategoryPath("", root.ID)
+    child := &domain.Category{ID: uuid.New(), ParentID: &root.ID}
+    child.Path = domain.CategoryPath(root.Path, child.ID)
+
+    assert.Equal(t, "/"+root.ID.String()+"/"+child.ID.String()+"/", child.Path)
+    assert.True(t, child.IsDescendantOf(root.Path))
+    assert.True(t, root.IsDescendantOf(root.Path))
+    assert.False(t, root.IsDescendantOf(child.Path))
+}
+
+func TestListProductsByCategoryAndTag(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+    categoryID := uuid.New()
+
+    lamp := domain.Product{ID: uuid.New(), Name: "Desk Lamp", Tags: domain.Tags{"sale"}, Categories: []domain.Category{{ID: categoryID}}}
+    mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(opts domain.ProductListOptions) bool {
+        return opts.Filter.CategoryID != nil && *opts.Filter.CategoryID == categoryID &&
+            opts.Filter.IncludeDescendants && opts.Filter.Tag == "sale"
+    })).Return([]domain.Product{lamp}, int64(1), nil)
+
+    resp, err := productService.ListProducts(context.Background(), &pb.ListProductsRequest{
+        CategoryId: categoryID.String(), IncludeDescendants: true, Tag: " Sale ",
+    })
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetProducts(), 1)
+    assert.Equal(t, []string{categoryID.String()}, resp.GetProducts()[0].GetCategoryIds())
+    assert.Equal(t, []string{"sale"}, resp.GetProducts()[0].GetTags())
+
+    _, err = productService.ListProducts(context.Background(), &pb.ListProductsRequest{CategoryId: "lamps"})
+    assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+}
+
+func TestCreateProductNormalizesTagsAndCategories(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    mockRepo.On("Create", mock.Anything).Return(nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, mock.Anything, mock.Anything).Return([]domain.AttributeDefinition(nil), nil)
+
+    categoryID := uuid.New().String()
+    resp, err := handler.CreateProduct(context.Background(), &pb.Product{
+        Name:        "Desk Lamp",
+        Price:       usd(49, 0),
+        Tags:        []string{" LED", "led", "Sale"},
+        CategoryIds: []string{categoryID, categoryID},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, []string{"led", "sale"}, resp.GetTags())
+    assert.Equal(t, []string{categoryID}, resp.GetCategoryIds())
+
+    _, err = handler.CreateProduct(context.Background(), &pb.Product{Name: "Desk Lamp", Price: usd(49, 0), Tags: []string{" "}})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    _, err = handler.CreateProduct(context.Background(), &pb.Product{Name: "Desk Lamp", Price: usd(49, 0), CategoryIds: []string{"lamps"}})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreateAttributeDefinition(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    mockRepo.On("CreateAttributeDefinition", mock.Anything, mock.Anything).Return(nil)
+
+    created, err := handler.CreateAttributeDefinition(context.Background(), &pb.AttributeDefinition{
+        Name:       "finish",
+        Type:       pb.AttributeDefinition_ENUM,
+        EnumValues: []string{" matte", "gloss "},
+        Scope:      &pb.AttributeDefinition_ProductType{ProductType: "physical"},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, []string{"matte", "gloss"}, created.GetEnumValues())
+    assert.Equal(t, "physical", created.GetProductType())
+
+    categoryID := uuid.New().String()
+    for _, definition := range []*pb.AttributeDefinition{
+        // Names are identifiers
+        {Name: "Screen Size", Type: pb.AttributeDefinition_NUMBER, Scope: &pb.AttributeDefinition_CategoryId{CategoryId: categoryID}},
+        // A scope is required
+        {Name: "screen_size", Type: pb.AttributeDefinition_NUMBER},
+        {Name: "screen_size", Type: pb.AttributeDefinition_NUMBER, Scope: &pb.AttributeDefinition_ProductType{ProductType: "service"}},
+        {Name: "screen_size", Scope: &pb.AttributeDefinition_CategoryId{CategoryId: categoryID}},
+        // Units are for numbers and enum values for enums
+        {Name: "colour", Type: pb.AttributeDefinition_STRING, Unit: "cm", Scope: &pb.AttributeDefinition_CategoryId{CategoryId: categoryID}},
+        {Name: "colour", Type: pb.AttributeDefinition_STRING, EnumValues: []string{"red"}, Scope: &pb.AttributeDefinition_CategoryId{CategoryId: categoryID}},
+        {Name: "finish", Type: pb.AttributeDefinition_ENUM, EnumValues: []string{"matte", "matte"}, Scope: &pb.AttributeDefinition_CategoryId{CategoryId: categoryID}},
+    } {
+        _, err = handler.CreateAttributeDefinition(context.Background(), definition)
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), definition.String())
+    }
+    mockRepo.AssertNumberOfCalls(t, "CreateAttributeDefinition", 1)
+}
+
+func TestCreateProductValidatesAttributes(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    physical := "physical"
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, "physical", mock.Anything).Return([]domain.AttributeDefinition{
+        {Name: "finish", Type: domain.AttributeTypeEnum, EnumValues: domain.EnumValues{"matte", "gloss"}, ProductType: &physical},
+        {Name: "height", Type: domain.AttributeTypeNumber, Unit: "cm", Required: true, ProductType: &physical},
+    }, nil)
+    mockRepo.On("Create", mock.Anything).Return(nil)
+
+    lamp := func(attributes map[string]*pb.AttributeValue) *pb.Product {
+        return &pb.Product{
+            Name:        "Desk Lamp",
+            Price:       usd(49, 0),
+            ProductType: &pb.Product_PhysicalProduct{PhysicalProduct: &pb.PhysicalProduct{Weight: &pb.Weight{Value: 1.5, Unit: pb.Weight_KG}}},
+            Attributes:  attributes,
+        }
+    }
+    number := func(v float64) *pb.AttributeValue { return &pb.AttributeValue{Value: &pb.AttributeValue_NumberValue{NumberValue: v}} }
+    text := func(v string) *pb.AttributeValue { return &pb.AttributeValue{Value: &pb.AttributeValue_StringValue{StringValue: v}} }
+
+    resp, err := handler.CreateProduct(context.Background(), lamp(map[string]*pb.AttributeValue{"height": number(45), "finish": text("matte")}))
+    assert.NoError(t, err)
+    assert.Equal(t, 45.0, resp.GetAttributes()["height"].GetNumberValue())
+    assert.Equal(t, "matte", resp.GetAttributes()["finish"].GetStringValue())
+
+    for _, attributes := range []map[string]*pb.AttributeValue{
+        // height is required
+        {"finish": text("gloss")},
+        {"height": text("tall")},
+        {"height": number(45), "finish": text("satin")},
+        {"height": number(45), "wattage": number(40)},
+    } {
+        _, err = handler.CreateProduct(context.Background(), lamp(attributes))
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), fmt.Sprint(attributes))
+    }
+    mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestUpdateProductAttributePath(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+    category := uuid.New()
+    stored := &domain.Product{
+        ID: uuid.New(), Name: "Desk Lamp", Price: domain.Money{Amount: domain.MustParseAmount("49"), Currency: "USD"}, Version: 4,
+        Categories: []domain.Category{{ID: category}},
+        Attributes: domain.AttributeValues{"dimmable": true, "finish": "matte"},
+    }
+    mockRepo.On("GetByID", stored.ID).Return(stored, nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, "", []uuid.UUID{category}).Return([]domain.AttributeDefinition{
+        {Name: "dimmable", Type: domain.AttributeTypeBool, CategoryID: &category},
+        {Name: "finish", Type: domain.AttributeTypeString, CategoryID: &category},
+    }, nil)
+    mockRepo.On("Update", mock.Anything).Return(nil)
+
+    // Paths into attributes set or clear one attribute and keep the others
+    updated, err := productService.UpdateProduct(context.Background(), stored.ID, &domain.Product{Version: 4, Attributes: domain.AttributeValues{"dimmable": false}},
+        []string{"attributes.dimmable", "attributes.finish"})
+    assert.NoError(t, err)
+    assert.Equal(t, domain.AttributeValues{"dimmable": false}, updated.Attributes)
+}
+
+func TestRequiredAttributesDoNotBlockUnrelatedUpdates(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+    stored := &domain.Product{
+        ID: uuid.New(), Name: "Desk Lamp", Price: domain.Money{Amount: domain.MustParseAmount("49"), Currency: "USD"}, Version: 4,
+        Status: domain.ProductStatusInReview, Attributes: domain.AttributeValues{"finish": "matte"},
+    }
+    mockRepo.On("GetByID", stored.ID).Return(stored, nil)
+    // height became required after the product was written
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, "", mock.Anything).Return([]domain.AttributeDefinition{
+        {Name: "finish", Type: domain.AttributeTypeString},
+        {Name: "height", Type: domain.AttributeTypeNumber, Required: true},
+    }, nil)
+    mockRepo.On("Update", mock.Anything).Return(nil)
+
+    updated, err := productService.UpdateProduct(context.Background(), stored.ID, &domain.Product{
+        Version: 4, Price: domain.Money{Amount: domain.MustParseAmount("39"), Currency: "USD"},
+    }, []string{"price"})
+    assert.NoError(t, err)
+    assert.Equal(t, domain.MustParseAmount("39"), updated.Price.Amount)
+
+    // Writing attributes or publishing needs the required ones
+    _, err = productService.UpdateProduct(context.Background(), stored.ID, &domain.Product{Version: 4, Attributes: domain.AttributeValues{"finish": "gloss"}},
+        []string{"attributes.finish"})
+    assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+    _, err = productService.PublishProduct(context.Background(), stored.ID, 4, nil, nil)
+    assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+    mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestListProductsByAttribute(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+    physical := "physical"
+    mockRepo.On("ListAttributeDefinitions", mock.Anything, mock.Anything).Return([]domain.AttributeDefinition{
+        {Name: "finish", Type: domain.AttributeTypeEnum, EnumValues: domain.EnumValues{"matte", "gloss"}, ProductType: &physical},
+        {Name: "height", Type: domain.AttributeTypeNumber, ProductType: &physical},
+    }, nil)
+    minHeight := 30.0
+    mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(opts domain.ProductListOptions) bool {
+        return assert.ObjectsAreEqual([]domain.AttributeFilter{
+            {Name: "finish", Equals: "matte"},
+            {Name: "height", Min: &minHeight},
+        }, opts.Filter.Attributes)
+    })).Return([]domain.Product{}, int64(0), nil)
+
+    _, err := productService.ListProducts(context.Background(), &pb.ListProductsRequest{AttributeFilters: []*pb.AttributeFilter{
+        {Name: "finish", Equals: &pb.AttributeValue{Value: &pb.AttributeValue_StringValue{StringValue: "matte"}}},
+        {Name: "height", Min: proto.Float64(30)},
+    }})
+    assert.NoError(t, err)
+
+    for _, filter := range []*pb.AttributeFilter{
+        {Name: "wattage", Min: proto.Float64(30)},
+        {Name: "finish", Min: proto.Float64(1)},
+        {Name: "finish", Equals: &pb.AttributeValue{Value: &pb.AttributeValue_StringValue{StringValue: "satin"}}},
+        {Name: "height"},
+    } {
+        _, err = productService.ListProducts(context.Background(), &pb.ListProductsRequest{AttributeFilters: []*pb.AttributeFilter{filter}})
+        assert.ErrorIs(t, err, domain.ErrInvalidArgument, filter.String())
+    }
+}
+
+func TestUploadProductMedia(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+    dir := t.TempDir()
+    store, err := blobstore.NewLocalStore(dir, "http://media.test/media")
+    assert.NoError(t, err)
+    productService.MediaStore = store
+    client := startProductServer(t, grpc.NewProductHandler(productService))
+
+    lamp := &domain.Product{ID: uuid.New(), Name: "Desk Lamp"}
+    mockRepo.On("GetByID", lamp.ID).Return(lamp, nil)
+    mockRepo.On("CreateMedia", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+        media := args.Get(1).(*domain.ProductMedia)
+        media.Position, media.Version = 2, 1
+    })
+
+    var picture bytes.Buffer
+    assert.NoError(t, png.Encode(&picture, image.NewRGBA(image.Rect(0, 0, 64, 48))))
+    content := picture.Bytes()
+
+    stream, err := client.UploadProductMedia(context.Background())
+    assert.NoError(t, err)
+    assert.NoError(t, stream.Send(&pb.UploadProductMediaRequest{Payload: &pb.UploadProductMediaRequest_Metadata{Metadata: &pb.MediaMetadata{
+        ProductId: lamp.ID.String(), FileName: "../lamp.PNG", AltText: "Lamp from the front",
+    }}}))
+    for _, chunk := range [][]byte{content[:20], content[20:]} {
+        assert.NoError(t, stream.Send(&pb.UploadProductMediaRequest{Payload: &pb.UploadProductMediaRequest_Data{Data: chunk}}))
+    }
+    media, err := stream.CloseAndRecv()
+    assert.NoError(t, err)
+
+    assert.Equal(t, "image/png", media.GetContentType())
+    assert.Equal(t, "lamp.PNG", media.GetFileName())
+    assert.Equal(t, int64(len(content)), media.GetSizeBytes())
+    assert.Equal(t, int32(64), media.GetWidth())
+    assert.Equal(t, int32(48), media.GetHeight())
+    assert.Len(t, media.GetChecksumSha256(), 64)
+    assert.Equal(t, int32(2), media.GetPosition())
+    key := fmt.Sprintf("products/%s/%s.png", lamp.ID, media.GetId())
+    assert.Equal(t, "http://media.test/media/"+key, media.GetUrl())
+    stored, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(key)))
+    assert.NoError(t, err)
+    assert.Equal(t, content, stored)
+
+    // Content declared as an image must decode, and nothing is kept if it does not
+    stream, err = client.UploadProductMedia(context.Background())
+    assert.NoError(t, err)
+    assert.NoError(t, stream.Send(&pb.UploadProductMediaRequest{Payload: &pb.UploadProductMediaRequest_Metadata{Metadata: &pb.MediaMetadata{
+        ProductId: lamp.ID.String(), FileName: "broken.png", ContentType: "image/png",
+    }}}))
+    assert.NoError(t, stream.Send(&pb.UploadProductMediaRequest{Payload: &pb.UploadProductMediaRequest_Data{Data: []byte("not a picture")}}))
+    _, err = stream.CloseAndRecv()
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    mockRepo.AssertNumberOfCalls(t, "CreateMedia", 1)
+    files, err := os.ReadDir(filepath.Join(dir, "products", lamp.ID.String()))
+    assert.NoError(t, err)
+    assert.Len(t, files, 1)
+
+    // The metadata has to come first
+    stream, err = client.UploadProductMedia(context.Background())
+    assert.NoError(t, err)
+    assert.NoError(t, stream.Send(&pb.UploadProductMediaRequest{Payload: &pb.UploadProductMediaRequest_Data{Data: content}}))
+    _, err = stream.CloseAndRecv()
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestReorderAndDeleteProductMedia(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+    dir := t.TempDir()
+    store, err := blobstore.NewLocalStore(dir, "http://media.test/media/")
+    assert.NoError(t, err)
+    productService.MediaStore = store
+    handler := grpc.NewProductHandler(productService)
+
+    productID := uuid.New()
+    front, back := uuid.New(), uuid.New()
+    mockRepo.On("ReorderMedia", mock.Anything, productID, []uuid.UUID{back, front}).Return([]domain.ProductMedia{
+        {ID: back, ProductID: productID, Position: 0, Version: 1},
+        {ID: front, ProductID: productID, Position: 1, Version: 1},
+    }, nil)
+    resp, err := handler.ReorderProductMedia(context.Background(), &pb.ReorderProductMediaRequest{
+        ProductId: productID.String(), MediaIds: []string{back.String(), front.String()},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, back.String(), resp.GetMedia()[0].GetId())
+    assert.Equal(t, int32(1), resp.GetMedia()[1].GetPosition())
+
+    _, err = handler.ReorderProductMedia(context.Background(), &pb.ReorderProductMediaRequest{ProductId: productID.String(), MediaIds: []string{"front"}})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+    // Deleting media removes its content too
+    key := fmt.Sprintf("products/%s/%s.jpg", productID, back)
+    _, err = store.Put(context.Background(), key, bytes.NewReader([]byte("jpeg")))
+    assert.NoError(t, err)
+    mockRepo.On("GetMedia", mock.Anything, back).Return(&domain.ProductMedia{ID: back, ProductID: productID, StorageKey: key, Version: 3}, nil)
+    mockRepo.On("DeleteMedia", mock.Anything, back, int64(3)).Return(nil)
+    _, err = handler.DeleteProductMedia(context.Background(), &pb.DeleteProductMediaRequest{Id: back.String(), Etag: `W/"3"`})
+    assert.NoError(t, err)
+    _, err = os.Stat(filepath.Join(dir, filepath.FromSlash(key)))
+    assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetProductLocalized(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    product := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Description: "A lamp for your desk", Status: domain.ProductStatusPublished}
+    mockRepo.On("GetByID", product.ID).Return(product, nil)
+    mockRepo.On("FindTranslations", mock.Anything, []uuid.UUID{product.ID}, []string{"de-CH", "de", "en"}).Return([]domain.ProductTranslation{
+        {ProductID: product.ID, Locale: "de", Name: "Schreibtischlampe", Description: "Eine Lampe für den Schreibtisch"},
+        {ProductID: product.ID, Locale: "de-CH", Name: "Pultleuchte"},
+    }, nil)
+
+    // The Swiss name is preferred and the description falls back to German
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "de-CH, en;q=0.5"))
+    resp, err := handler.GetProduct(ctx, &pb.GetProductRequest{Id: product.ID.String()})
+    assert.NoError(t, err)
+    assert.Equal(t, "Pultleuchte", resp.GetName())
+    assert.Equal(t, "Eine Lampe für den Schreibtisch", resp.GetDescription())
+    assert.Equal(t, "de-CH", resp.GetLocale())
+
+    // Without a translation the product keeps its own text
+    mockRepo.On("FindTranslations", mock.Anything, []uuid.UUID{product.ID}, []string{"ja"}).Return([]domain.ProductTranslation{}, nil)
+    product.Name, product.Description, product.Locale = "Desk Lamp", "A lamp for your desk", ""
+    resp, err = handler.GetProduct(ctx, &pb.GetProductRequest{Id: product.ID.String(), Locale: "ja"})
+    assert.NoError(t, err)
+    assert.Equal(t, "Desk Lamp", resp.GetName())
+    assert.Empty(t, resp.GetLocale())
+}
+
+func TestUpsertProductTranslations(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    productID := uuid.New()
+    mockRepo.On("UpsertTranslations", mock.Anything, productID, []domain.ProductTranslation{
+        {Locale: "pt-BR", Name: "Luminária de mesa"},
+    }).Return(nil)
+    mockRepo.On("ListTranslations", mock.Anything, productID).Return([]domain.ProductTranslation{
+        {ProductID: productID, Locale: "de", Name: "Schreibtischlampe"},
+        {ProductID: productID, Locale: "pt-BR", Name: "Luminária de mesa"},
+    }, nil)
+
+    resp, err := handler.UpsertProductTranslations(context.Background(), &pb.UpsertProductTranslationsRequest{
+        ProductId:    productID.String(),
+        Translations: []*pb.ProductTranslation{{Locale: "pt_br", Name: " Luminária de mesa "}},
+    })
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetTranslations(), 2)
+
+    for _, translations := range [][]*pb.ProductTranslation{
+        nil,
+        {{Locale: "de", Name: ""}},
+        {{Locale: "", Name: "Lampe"}},
+        {{Locale: "de", Name: "Lampe"}, {Locale: "DE", Name: "Leuchte"}},
+    } {
+        _, err = handler.UpsertProductTranslations(context.Background(), &pb.UpsertProductTranslationsRequest{
+            ProductId: productID.String(), Translations: translations,
+        })
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), fmt.Sprint(translations))
+    }
+    mockRepo.AssertNumberOfCalls(t, "UpsertTranslations", 1)
+}
+
+func TestPublishProductWorkflow(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    handler.AdminToken = "secret"
+    admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "secret"))
+
+    draft := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Status: domain.ProductStatusDraft, Version: 1}
+    mockRepo.On("GetByID", draft.ID).Return(draft, nil)
+    mockRepo.On("Update", mock.Anything).Return(nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, mock.Anything, mock.Anything).Return([]domain.AttributeDefinition(nil), nil)
+
+    // A draft has to go through review before it is published
+    _, err := handler.PublishProduct(admin, &pb.PublishProductRequest{Id: draft.ID.String(), Etag: `W/"1"`})
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+    draft.Status = domain.ProductStatusInReview
+    publishAt := time.Now().Add(time.Hour)
+    resp, err := handler.PublishProduct(admin, &pb.PublishProductRequest{
+        Id: draft.ID.String(), Etag: `W/"1"`, PublishAt: timestamppb.New(publishAt),
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, pb.ProductStatus_PRODUCT_STATUS_IN_REVIEW, resp.GetStatus())
+    assert.True(t, resp.GetPublishAt().AsTime().Equal(publishAt))
+
+    // Publishing now clears the schedule; unpublish_at must come later
+    _, err = handler.PublishProduct(admin, &pb.PublishProductRequest{
+        Id: draft.ID.String(), Etag: `W/"1"`, UnpublishAt: timestamppb.New(time.Now().Add(-time.Hour)),
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    resp, err = handler.PublishProduct(admin, &pb.PublishProductRequest{Id: draft.ID.String(), Etag: `W/"1"`})
+    assert.NoError(t, err)
+    assert.Equal(t, pb.ProductStatus_PRODUCT_STATUS_PUBLISHED, resp.GetStatus())
+    assert.Nil(t, resp.GetPublishAt())
+    assert.NotNil(t, resp.GetPublishedAt())
+
+    _, err = handler.ArchiveProduct(admin, &pb.ArchiveProductRequest{Id: draft.ID.String(), Etag: `W/"2"`})
+    assert.Equal(t, codes.Aborted, status.Code(err))
+    resp, err = handler.ArchiveProduct(admin, &pb.ArchiveProductRequest{Id: draft.ID.String(), Etag: `W/"1"`})
+    assert.NoError(t, err)
+    assert.Equal(t, pb.ProductStatus_PRODUCT_STATUS_ARCHIVED, resp.GetStatus())
+
+    // An archived product can only go back to draft
+    _, err = handler.PublishProduct(admin, &pb.PublishProductRequest{Id: draft.ID.String(), Etag: `W/"1"`})
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestLifecycleChangesAreAdminOnly(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    handler.AdminToken = "secret"
+    admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "secret"))
+
+    product := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Status: domain.ProductStatusInReview, Version: 1,
+        Price: domain.Money{Amount: domain.MustParseAmount("49.99"), Currency: "USD"}}
+    mockRepo.On("GetByID", product.ID).Return(product, nil)
+    mockRepo.On("Update", mock.Anything).Return(nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, mock.Anything, mock.Anything).Return([]domain.AttributeDefinition(nil), nil)
+    id := product.ID.String()
+
+    _, err := handler.PublishProduct(context.Background(), &pb.PublishProductRequest{Id: id, Etag: `W/"1"`})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+    _, err = handler.ArchiveProduct(context.Background(), &pb.ArchiveProductRequest{Id: id, Etag: `W/"1"`})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+    // Moving a product between draft and review through its update mask too
+    toDraft := &pb.Product{Id: id, Etag: `W/"1"`, Name: "Desk Lamp", Status: pb.ProductStatus_PRODUCT_STATUS_DRAFT}
+    _, err = handler.UpdateProduct(context.Background(), &pb.UpdateProductRequest{
+        Product: toDraft, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", " status"}},
+    })
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+    batch, err := handler.BatchUpdateProducts(context.Background(), &pb.BatchUpdateProductsRequest{Requests: []*pb.UpdateProductRequest{
+        {Product: toDraft, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}}},
+    }})
+    assert.NoError(t, err)
+    assert.Equal(t, int32(codes.PermissionDenied), batch.GetResults()[0].GetStatus().GetCode())
+    _, err = handler.BatchUpdateProducts(context.Background(), &pb.BatchUpdateProductsRequest{Requests: []*pb.UpdateProductRequest{
+        {Product: toDraft, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}}},
+    }, AllOrNothing: true})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+    mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+    resp, err := handler.UpdateProduct(admin, &pb.UpdateProductRequest{
+        Product: toDraft, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, pb.ProductStatus_PRODUCT_STATUS_DRAFT, resp.GetStatus())
+}
+
+func TestNonAdminsCannotSeeDrafts(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    handler.AdminToken = "secret"
+    admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "secret"))
+
+    draft := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Status: domain.ProductStatusDraft, Version: 1}
+    published := &domain.Product{ID: uuid.New(), Name: "Floor Lamp", Status: domain.ProductStatusPublished, Version: 1}
+    mockRepo.On("GetByID", draft.ID).Return(draft, nil)
+    mockRepo.On("GetByID", published.ID).Return(published, nil)
+
+    // Drafts are reported as missing to everyone but admins
+    _, err := handler.GetProduct(context.Background(), &pb.GetProductRequest{Id: draft.ID.String()})
+    assert.Equal(t, codes.NotFound, status.Code(err))
+    resp, err := handler.GetProduct(admin, &pb.GetProductRequest{Id: draft.ID.String()})
+    assert.NoError(t, err)
+    assert.Equal(t, "Desk Lamp", resp.GetName())
+
+    batch, err := handler.BatchGetProducts(context.Background(), &pb.BatchGetProductsRequest{Ids: []string{published.ID.String(), draft.ID.String()}})
+    assert.NoError(t, err)
+    assert.Equal(t, "Floor Lamp", batch.GetResults()[0].GetProduct().GetName())
+    assert.Nil(t, batch.GetResults()[1].GetProduct())
+    assert.Equal(t, int32(codes.NotFound), batch.GetResults()[1].GetStatus().GetCode())
+    _, err = handler.BatchGetProducts(context.Background(), &pb.BatchGetProductsRequest{Ids: []string{published.ID.String(), draft.ID.String()}, AllOrNothing: true})
+    assert.Equal(t, codes.NotFound, status.Code(err))
+
+    _, err = handler.ListProducts(context.Background(), &pb.ListProductsRequest{ShowDeleted: true})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+    // So are its variants, media, translations, prices, stock and shipping estimates
+    variant := &domain.ProductVariant{ID: uuid.New(), ProductID: draft.ID, SKU: "LAMP-RED"}
+    mockRepo.On("GetVariant", mock.Anything, variant.ID).Return(variant, nil)
+    id := draft.ID.String()
+    for name, call := range map[string]func() error{
+        "GetProductVariant": func() error {
+            _, err := handler.GetProductVariant(context.Background(), &pb.GetProductVariantRequest{Key: &pb.GetProductVariantRequest_Id{Id: variant.ID.String()}})
+            return err
+        },
+        "ListProductVariants": func() error {
+            _, err := handler.ListProductVariants(context.Background(), &pb.ListProductVariantsRequest{ProductId: id})
+            return err
+        },
+        "ListProductMedia": func() error {
+            _, err := handler.ListProductMedia(context.Background(), &pb.ListProductMediaRequest{ProductId: id})
+            return err
+        },
+        "ListProductTranslations": func() error {
+            _, err := handler.ListProductTranslations(context.Background(), &pb.ListProductTranslationsRequest{ProductId: id})
+            return err
+        },
+        "GetPrice": func() error {
+            _, err := handler.GetPrice(context.Background(), &pb.GetPriceRequest{ProductId: id})
+            return err
+        },
+        "ListPriceHistory": func() error {
+            _, err := handler.ListPriceHistory(context.Background(), &pb.ListPriceHistoryRequest{ProductId: id})
+            return err
+        },
+        "GetStock": func() error {
+            _, err := handler.GetStock(context.Background(), &pb.GetStockRequest{ProductId: id})
+            return err
+        },
+        "EstimateShipping": func() error {
+            _, err := handler.EstimateShipping(context.Background(), &pb.EstimateShippingRequest{Items: []*pb.ShippingItem{{ProductId: id, Quantity: 1}}})
+            return err
+        },
+    } {
+        assert.Equal(t, codes.NotFound, status.Code(call()), name)
+    }
+    variantResp, err := handler.GetProductVariant(admin, &pb.GetProductVariantRequest{Key: &pb.GetProductVariantRequest_Id{Id: variant.ID.String()}})
+    assert.NoError(t, err)
+    assert.Equal(t, "LAMP-RED", variantResp.GetSku())
+}
+
+func TestListProductsPublishedOnlyForNonAdmins(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    handler.AdminToken = "secret"
+
+    published := []domain.ProductStatus{domain.ProductStatusPublished}
+    drafts := []domain.ProductStatus{domain.ProductStatusDraft}
+    mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(opts domain.ProductListOptions) bool {
+        return assert.ObjectsAreEqual(published, opts.Filter.Statuses)
+    })).Return([]domain.Product{}, int64(0), nil).Once()
+    mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(opts domain.ProductListOptions) bool {
+        return assert.ObjectsAreEqual(drafts, opts.Filter.Statuses)
+    })).Return([]domain.Product{}, int64(0), nil).Once()
+
+    _, err := handler.ListProducts(context.Background(), &pb.ListProductsRequest{})
+    assert.NoError(t, err)
+
+    draftsOnly := []pb.ProductStatus{pb.ProductStatus_PRODUCT_STATUS_DRAFT}
+    _, err = handler.ListProducts(context.Background(), &pb.ListProductsRequest{Statuses: draftsOnly})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "wrong"))
+    _, err = handler.ListProducts(ctx, &pb.ListProductsRequest{Statuses: draftsOnly})
+    assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+    ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-admin-token", "secret"))
+    _, err = handler.ListProducts(ctx, &pb.ListProductsRequest{Statuses: draftsOnly})
+    assert.NoError(t, err)
+    mockRepo.AssertExpectations(t)
+}
+
+func TestCreateBundleProduct(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    device := &domain.Product{ID: uuid.New(), Name: "Speaker", Status: domain.ProductStatusPublished, Price: domain.Money{Amount: domain.MustParseAmount("100"), Currency: "USD"}, PhysicalProduct: &domain.PhysicalProduct{}}
+    plan := &domain.Product{ID: uuid.New(), Name: "Music", Status: domain.ProductStatusPublished, Price: domain.Money{Amount: domain.MustParseAmount("9.99"), Currency: "USD"}, SubscriptionProduct: &domain.SubscriptionProduct{}}
+    manual := &domain.Product{ID: uuid.New(), Name: "Manual", Status: domain.ProductStatusDraft, Price: domain.Money{Currency: "USD"}, DigitalProduct: &domain.DigitalProduct{}}
+    red := &domain.ProductVariant{ID: uuid.New(), ProductID: device.ID}
+    red.SetPriceOverride(&domain.Money{Amount: domain.MustParseAmount("110"), Currency: "USD"})
+    mockRepo.On("GetByID", device.ID).Return(device, nil)
+    mockRepo.On("GetByID", plan.ID).Return(plan, nil)
+    mockRepo.On("GetByID", manual.ID).Return(manual, nil)
+    // Components are looked up with the context of the request
+    ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "bundle"))
+    mockRepo.On("GetVariant", mock.MatchedBy(func(c context.Context) bool {
+        md, _ := metadata.FromIncomingContext(c)
+        return len(md.Get("x-request-id")) == 1
+    }), red.ID).Return(red, nil)
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, "bundle", mock.Anything).Return([]domain.AttributeDefinition{}, nil)
+    mockRepo.On("Create", mock.Anything).Return(nil)
+
+    bundle := func(pricing pb.BundleProduct_Pricing, percent string, components ...*pb.BundleComponent) *pb.Product {
+        return &pb.Product{
+            Name:  "Speaker Kit",
+            Price: usd(120, 0),
+            ProductType: &pb.Product_BundleProduct{BundleProduct: &pb.BundleProduct{
+                Pricing: pricing, DiscountPercent: percent, Components: components,
+            }},
+        }
+    }
+    speaker := &pb.BundleComponent{ProductId: device.ID.String(), VariantId: red.ID.String(), Quantity: 1}
+    music := &pb.BundleComponent{ProductId: plan.ID.String(), Quantity: 12}
+
+    // 110 + 12 * 9.99 = 229.88, less 10% is 206.892, rounded to cents
+    resp, err := handler.CreateProduct(ctx, bundle(pb.BundleProduct_PERCENT_OFF, "10", speaker, music))
+    assert.NoError(t, err)
+    assert.True(t, proto.Equal(usd(206, 890000000), resp.GetPrice()))
+    assert.Len(t, resp.GetBundleProduct().GetComponents(), 2)
+    assert.Equal(t, red.ID.String(), resp.GetBundleProduct().GetComponents()[0].GetVariantId())
+
+    resp, err = handler.CreateProduct(ctx, bundle(pb.BundleProduct_PRICING_UNSPECIFIED, "", speaker))
+    assert.NoError(t, err)
+    assert.Equal(t, pb.BundleProduct_FIXED, resp.GetBundleProduct().GetPricing())
+    assert.True(t, proto.Equal(usd(120, 0), resp.GetPrice()))
+
+    // Components must exist and be published, and are listed once each
+    _, err = handler.CreateProduct(ctx, bundle(pb.BundleProduct_FIXED, "", speaker, &pb.BundleComponent{ProductId: manual.ID.String(), Quantity: 1}))
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+    missing := uuid.New()
+    mockRepo.On("GetByID", missing).Return(nil, fmt.Errorf("product with ID %s %w", missing, domain.ErrNotFound))
+    _, err = handler.CreateProduct(ctx, bundle(pb.BundleProduct_FIXED, "", &pb.BundleComponent{ProductId: missing.String(), Quantity: 1}))
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    _, err = handler.CreateProduct(ctx, bundle(pb.BundleProduct_FIXED, "", music, music))
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    _, err = handler.CreateProduct(ctx, bundle(pb.BundleProduct_FIXED, "5", music))
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    _, err = handler.CreateProduct(ctx, bundle(pb.BundleProduct_PERCENT_OFF, "150", music))
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    _, err = handler.CreateProduct(ctx, bundle(pb.BundleProduct_FIXED, ""))
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    mockRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestAdjustAndReserveStock(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    lamp := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", PhysicalProduct: &domain.PhysicalProduct{}}
+    ebook := &domain.Product{ID: uuid.New(), Name: "E-book", DigitalProduct: &domain.DigitalProduct{}}
+    warehouseID := uuid.New()
+    item := domain.StockItem{ProductID: lamp.ID, WarehouseID: warehouseID}
+    mockRepo.On("GetWarehouse", mock.Anything, warehouseID).Return(&domain.Warehouse{ID: warehouseID, Active: true}, nil)
+    mockRepo.On("GetByID", lamp.ID).Return(lamp, nil)
+    mockRepo.On("GetByID", ebook.ID).Return(ebook, nil)
+    mockRepo.On("AdjustStock", mock.Anything, item, int64(10), "received").
+        Return(&domain.StockLevel{StockItem: item, OnHand: 10}, nil)
+
+    level, err := handler.AdjustStock(context.Background(), &pb.AdjustStockRequest{
+        ProductId: lamp.ID.String(), WarehouseId: warehouseID.String(), Delta: 10, Reason: " received ",
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, int64(10), level.GetAvailable())
+
+    // Stock is only kept for physical products, and a zero delta changes nothing
+    _, err = handler.AdjustStock(context.Background(), &pb.AdjustStockRequest{
+        ProductId: ebook.ID.String(), WarehouseId: warehouseID.String(), Delta: 10,
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    _, err = handler.AdjustStock(context.Background(), &pb.AdjustStockRequest{
+        ProductId: lamp.ID.String(), WarehouseId: warehouseID.String(),
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+    mockRepo.On("ReserveStock", mock.Anything, mock.MatchedBy(func(r *domain.StockReservation) bool {
+        return r.StockItem == item && r.Quantity == 3 && r.Reference == "order-1"
+    })).Return(nil).Once()
+    mockRepo.On("ReserveStock", mock.Anything, mock.Anything).
+        Return(fmt.Errorf("%w: only 7 units are available", domain.ErrFailedPrecondition)).Once()
+
+    before := time.Now()
+    reservation, err := handler.ReserveStock(context.Background(), &pb.ReserveStockRequest{
+        ProductId: lamp.ID.String(), WarehouseId: warehouseID.String(), Quantity: 3, Reference: "order-1",
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, pb.StockReservation_PENDING, reservation.GetStatus())
+    assert.WithinDuration(t, before.Add(15*time.Minute), reservation.GetExpiresAt().AsTime(), time.Minute)
+
+    _, err = handler.ReserveStock(context.Background(), &pb.ReserveStockRequest{
+        ProductId: lamp.ID.String(), WarehouseId: warehouseID.String(), Quantity: 8,
+    })
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+    _, err = handler.ReserveStock(context.Background(), &pb.ReserveStockRequest{
+        ProductId: lamp.ID.String(), WarehouseId: warehouseID.String(), Quantity: 1, Ttl: durationpb.New(48 * time.Hour),
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    mockRepo.AssertNumberOfCalls(t, "ReserveStock", 2)
+}
+
+func TestListStockMovementsPaging(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    productID := uuid.New()
+    movements := []domain.StockMovement{{ID: 9}, {ID: 8}, {ID: 7}}
+    mockRepo.On("ListStockMovements", mock.Anything, domain.StockMovementOptions{ProductID: productID, Limit: 3}).
+        Return(movements, nil).Once()
+
+    resp, err := handler.ListStockMovements(context.Background(), &pb.ListStockMovementsRequest{ProductId: productID.String(), PageSize: 2})
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetMovements(), 2)
+    assert.NotEmpty(t, resp.GetNextPageToken())
+
+    mockRepo.On("ListStockMovements", mock.Anything, domain.StockMovementOptions{ProductID: productID, Limit: 3, BeforeID: 8}).
+        Return(movements[2:], nil).Once()
+    resp, err = handler.ListStockMovements(context.Background(), &pb.ListStockMovementsRequest{
+        ProductId: productID.String(), PageSize: 2, PageToken: resp.GetNextPageToken(),
+    })
+    assert.NoError(t, err)
+    assert.Len(t, resp.GetMovements(), 1)
+    assert.Empty(t, resp.GetNextPageToken())
+    mockRepo.AssertExpectations(t)
+}
+
+func TestCreateWarehouse(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    mockRepo.On("CreateWarehouse", mock.Anything, mock.Anything).Return(nil)
+
+    warehouse := func() *pb.Warehouse {
+        return &pb.Warehouse{
+            Name:     " Berlin ",
+            Address:  &pb.Address{Line1: "Hauptstr. 1", City: "Berlin", PostalCode: "10115", CountryCode: "de"},
+            TimeZone: "Europe/Berlin",
+            Priority: 1,
+        }
+    }
+    resp, err := handler.CreateWarehouse(context.Background(), warehouse())
+    assert.NoError(t, err)
+    assert.Equal(t, "Berlin", resp.GetName())
+    assert.Equal(t, "DE", resp.GetAddress().GetCountryCode())
+    assert.True(t, resp.GetActive())
+
+    inactive := warehouse()
+    inactive.Active = proto.Bool(false)
+    resp, err = handler.CreateWarehouse(context.Background(), inactive)
+    assert.NoError(t, err)
+    assert.False(t, resp.GetActive())
+
+    for _, invalid := range []func(w *pb.Warehouse){
+        func(w *pb.Warehouse) { w.Name = " " },
+        func(w *pb.Warehouse) { w.TimeZone = "Mars/Olympus" },
+        func(w *pb.Warehouse) { w.TimeZone = "Local" },
+        func(w *pb.Warehouse) { w.Address.CountryCode = "XX" },
+        func(w *pb.Warehouse) { w.Address.CountryCode = "276" },
+        func(w *pb.Warehouse) { w.Address.City = "" },
+    } {
+        w := warehouse()
+        invalid(w)
+        _, err := handler.CreateWarehouse(context.Background(), w)
+        assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    }
+    mockRepo.AssertNumberOfCalls(t, "CreateWarehouse", 2)
+}
+
+func TestReserveStockAllocatesWarehouse(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+
+    lamp := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", PhysicalProduct: &domain.PhysicalProduct{}}
+    closed := &domain.Warehouse{ID: uuid.New(), Name: "Closed"}
+    allocated := uuid.New()
+    mockRepo.On("GetByID", lamp.ID).Return(lamp, nil)
+    mockRepo.On("GetWarehouse", mock.Anything, closed.ID).Return(closed, nil)
+    // Without a warehouse the repository allocates one
+    mockRepo.On("ReserveStock", mock.Anything, mock.MatchedBy(func(r *domain.StockReservation) bool {
+        return r.WarehouseID == uuid.Nil
+    })).Run(func(args mock.Arguments) {
+        args.Get(1).(*domain.StockReservation).WarehouseID = allocated
+    }).Return(nil).Once()
+
+    reservation, err := handler.ReserveStock(context.Background(), &pb.ReserveStockRequest{ProductId: lamp.ID.String(), Quantity: 2})
+    assert.NoError(t, err)
+    assert.Equal(t, allocated.String(), reservation.GetWarehouseId())
+
+    _, err = handler.ReserveStock(context.Background(), &pb.ReserveStockRequest{
+        ProductId: lamp.ID.String(), WarehouseId: closed.ID.String(), Quantity: 2,
+    })
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+    // Stock can still be adjusted in an inactive warehouse, but needs one
+    _, err = handler.AdjustStock(context.Background(), &pb.AdjustStockRequest{ProductId: lamp.ID.String(), Delta: 5})
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    mockRepo.AssertExpectations(t)
+}
+
+func TestCreatePhysicalProductValidatesMeasures(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    handler := grpc.NewProductHandler(service.NewProductService(mockRepo))
+    mockRepo.On("ApplicableAttributeDefinitions", mock.Anything, "physical", mock.Anything).Return([]domain.AttributeDefinition{}, nil)
+    mockRepo.On("Create", mock.MatchedBy(func(product *domain.Product) bool {
+        return product.PhysicalProduct.Weight == domain.Weight{Value: 12, Unit: domain.WeightUnitOunce} &&
+            product.PhysicalProduct.Dimensions.Unit == domain.LengthUnitInch
+    })).Return(nil).Once()
+
+    physical := func(weight *pb.Weight, dimensions *pb.Dimensions) *pb.Product {
+        return &pb.Product{
+            Name:        "Desk Lamp",
+            Price:       usd(49, 0),
+            ProductType: &pb.Product_PhysicalProduct{PhysicalProduct: &pb.PhysicalProduct{Weight: weight, Dimensions: dimensions}},
+        }
+    }
+    resp, err := handler.CreateProduct(context.Background(), physical(
+        &pb.Weight{Value: 12, Unit: pb.Weight_OZ},
+        &pb.Dimensions{Length: 6, Width: 6, Height: 18, Unit: pb.Dimensions_IN},
+    ))
+    assert.NoError(t, err)
+    assert.Equal(t, pb.Weight_OZ, resp.GetPhysicalProduct().GetWeight().GetUnit())
+    assert.Equal(t, 18.0, resp.GetPhysicalProduct().GetDimensions().GetHeight())
+
+    for _, product := range []*pb.Product{
+        physical(&pb.Weight{Value: 1}, nil),
+        physical(&pb.Weight{Value: -1, Unit: pb.Weight_KG}, nil),
+        physical(nil, &pb.Dimensions{Length: 6, Width: 6, Unit: pb.Dimensions_CM}),
+        physical(nil, &pb.Dimensions{Length: 6, Width: 6, Height: 6}),
+    } {
+        _, err := handler.CreateProduct(context.Background(), product)
+        assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    }
+    mockRepo.AssertExpectations(t)
+}
+
+func TestEstimateShipping(t *testing.T) {
+    mockRepo := new(MockProductRepository)
+    productService := service.NewProductService(mockRepo)
+    handler := grpc.NewProductHandler(productService)
+
+    lamp := &domain.Product{ID: uuid.New(), Name: "Desk Lamp", Status: domain.ProductStatusPublished, PhysicalProduct: &domain.PhysicalProduct{
+        Weight: domain.Weight{Value: 400, Unit: domain.WeightUnitGram},
+    }}
+    kit := &domain.Product{ID: uuid.New(), Name: "Reading Kit", Status: domain.ProductStatusPublished, BundleProduct: &domain.BundleProduct{
+        Components: []domain.BundleComponent{{ProductID: lamp.ID, Quantity: 2}},
+    }}
+    ebook := &domain.Product{ID: uuid.New(), Name: "Guide", Status: domain.ProductStatusPublished, DigitalProduct: &domain.DigitalProduct{}}
+    mockRepo.On("GetByID", lamp.ID).Return(lamp, nil)
+    mockRepo.On("GetByID", kit.ID).Return(kit, nil)
+    mockRepo.On("GetByID", ebook.ID).Return(ebook, nil)
+    mockRepo.On("ListWarehouses", mock.Anything, domain.WarehouseListOptions{ActiveOnly: true}).Return([]domain.Warehouse{
+        {ID: uuid.New(), Name: "Main", Address: domain.Address{CountryCode: "US"}, Active: true},
+    }, nil)
+
+    req := &pb.EstimateShippingRequest{
+        Items: []*pb.ShippingItem{
+            {ProductId: kit.ID.String(), Quantity: 1},
+            {ProductId: ebook.ID.String(), Quantity: 1},
+        },
+        Destination: &pb.Address{CountryCode: "ca"},
+    }
+    _, err := handler.EstimateShipping(context.Background(), req)
+    assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+    productService.ShippingRates = loadExampleRates(t)
+    // The bundle ships as its two lamps and the e-book does not ship at all;
+    // only ground has a rate to Canada
+    estimate, err := handler.EstimateShipping(context.Background(), req)
+    assert.NoError(t, err)
+    assert.Equal(t, "north_america", estimate.GetZone())
+    assert.InDelta(t, 0.8, estimate.GetActualWeight().GetValue(), 1e-9)
+    assert.Equal(t, pb.Weight_KG, estimate.GetActualWeight().GetUnit())
+    assert.Len(t, estimate.GetQuotes(), 1)
+    assert.Equal(t, "ground", estimate.GetQuotes()[0].GetCarrierCode())
+    assert.Equal(t, int64(19), estimate.GetQuotes()[0].GetPrice().GetUnits())
+    assert.Equal(t, "USD", estimate.GetQuotes()[0].GetPrice().GetCurrencyCode())
+
+    // A variant's weight override carries its own unit
+    heavy := &domain.ProductVariant{ID: uuid.New(), ProductID: lamp.ID}
+    heavy.SetWeightOverride(&domain.Weight{Value: 2, Unit: domain.WeightUnitPound})
+    mockRepo.On("GetVariant", mock.Anything, heavy.ID).Return(heavy, nil)
+    estimate, err = handler.EstimateShipping(context.Background(), &pb.EstimateShippingRequest{
+        Items:       []*pb.ShippingItem{{ProductId: lamp.ID.String(), VariantId: heavy.ID.String(), Quantity: 1}},
+        Destination: &pb.Address{CountryCode: "CA"},
+    })
+    assert.NoError(t, err)
+    assert.InDelta(t, 0.90718474, estimate.GetActualWeight().GetValue(), 1e-9)
+
+    _, err = handler.EstimateShipping(context.Background(), &pb.EstimateShippingRequest{
+        Items:       []*pb.ShippingItem{{ProductId: ebook.ID.String(), Quantity: 1}},
+        Destination: &pb.Address{CountryCode: "CA"},
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    _, err = handler.EstimateShipping(context.Background(), &pb.EstimateShippingRequest{
+        Items:       []*pb.ShippingItem{{ProductId: lamp.ID.String()}},
+        Destination: &pb.Address{CountryCode: "CA"},
+    })
+    assert.Equal(t, codes.InvalidArgument, status.Code(err))
+    mockRepo.AssertExpectations(t)
+}
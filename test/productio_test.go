@@ -0,0 +1,302 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"io"
+	"math"
+	"product-microservice/internal/productio"
+	pb "product-microservice/proto/product"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// exportFixture returns one product of every importable type as the
+// ExportProducts stream delivers them
+func exportFixture() []*pb.ExportedProduct {
+	created := timestamppb.New(time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.UTC))
+	product := func(sku, name string, price int64) *pb.Product {
+		return &pb.Product{
+			Id:          uuid.NewString(),
+			ExternalSku: sku,
+			Name:        name,
+			Price:       usd(price, 990000000),
+			Etag:        "3",
+			CreatedAt:   created,
+			UpdatedAt:   created,
+		}
+	}
+
+	ebook := product("EBOOK-1", "Guide", 9)
+	ebook.Description = "Covers \"quotes\", commas\nand line breaks"
+	ebook.ProductType = &pb.Product_DigitalProduct{DigitalProduct: &pb.DigitalProduct{FileSize: 2048, DownloadLink: "https://cdn.example.com/guide.pdf"}}
+	lamp := product("LAMP-1", "Desk Lamp", 29)
+	lamp.ProductType = &pb.Product_PhysicalProduct{PhysicalProduct: &pb.PhysicalProduct{
+		Weight:     &pb.Weight{Value: 1.25, Unit: pb.Weight_KG},
+		Dimensions: &pb.Dimensions{Length: 10, Width: 20, Height: 30.5, Unit: pb.Dimensions_CM},
+	}}
+	streaming := product("STREAM-1", "Streaming", 4)
+	streaming.ProductType = &pb.Product_SubscriptionProduct{SubscriptionProduct: &pb.SubscriptionProduct{
+		SubscriptionPeriod: "monthly",
+		RenewalPrice:       usd(4, 500000000),
+	}}
+
+	return []*pb.ExportedProduct{
+		{Product: ebook},
+		{Product: lamp},
+		{Product: streaming, SubscriptionPlans: []*pb.SubscriptionPlan{
+			{Id: uuid.NewString(), ProductId: streaming.GetId(), PlanName: "Annual", Duration: 365, Price: usd(45, 0), Etag: "1"},
+		}},
+	}
+}
+
+func exportTo(t *testing.T, format productio.Format, products []*pb.ExportedProduct) []byte {
+	var out bytes.Buffer
+	writer, err := productio.NewWriter(format, &out)
+	require.NoError(t, err)
+	for _, exported := range products {
+		require.NoError(t, writer.Write(exported))
+	}
+	require.NoError(t, writer.Close())
+	return out.Bytes()
+}
+
+func importFrom(t *testing.T, format productio.Format, data []byte) []*pb.Product {
+	reader, err := productio.NewReader(format, bytes.NewReader(data))
+	require.NoError(t, err)
+	var products []*pb.Product
+	for {
+		product, _, err := reader.Read()
+		if err == io.EOF {
+			return products
+		}
+		require.NoError(t, err)
+		products = append(products, product)
+	}
+}
+
+func TestExportedCSVCanBeImported(t *testing.T) {
+	exported := exportFixture()
+	imported := importFrom(t, productio.FormatCSV, exportTo(t, productio.FormatCSV, exported))
+	require.Len(t, imported, len(exported))
+	for i := range exported {
+		// Only the import columns are read back; the metadata is ignored
+		expected := proto.Clone(exported[i].GetProduct()).(*pb.Product)
+		expected.Id, expected.Etag, expected.CreatedAt, expected.UpdatedAt = "", "", nil, nil
+		assert.True(t, proto.Equal(expected, imported[i]), "row %d: got %v", i, imported[i])
+	}
+}
+
+func TestExportedJSONLCanBeImported(t *testing.T) {
+	exported := exportFixture()
+	imported := importFrom(t, productio.FormatJSONL, exportTo(t, productio.FormatJSONL, exported))
+	require.Len(t, imported, len(exported))
+	for i := range exported {
+		assert.True(t, proto.Equal(exported[i].GetProduct(), imported[i]), "row %d: got %v", i, imported[i])
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	exported := exportFixture()
+	data := exportTo(t, productio.FormatParquet, exported)
+	header, err := csv.NewReader(bytes.NewReader(exportTo(t, productio.FormatCSV, nil))).Read()
+	require.NoError(t, err)
+
+	// File layout: magic, column chunks, footer, footer length, magic
+	require.Greater(t, len(data), 12)
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footerStart := len(data) - 8 - footerLength
+	require.GreaterOrEqual(t, footerStart, 4)
+	footer := &compactDecoder{buf: data[footerStart : len(data)-8]}
+	metadata := footer.structValue()
+	require.NoError(t, footer.err)
+	assert.Equal(t, footerLength, footer.pos, "footer length")
+
+	// FileMetaData: version, schema, num_rows and row_groups
+	assert.Equal(t, int64(1), metadata[1])
+	schema := metadata[2].([]interface{})
+	require.Len(t, schema, len(header)+1)
+	assert.Equal(t, "schema", string(schema[0].(thriftStruct)[4].([]byte)))
+	assert.Equal(t, int64(len(header)), schema[0].(thriftStruct)[5])
+	for i, name := range header {
+		element := schema[i+1].(thriftStruct)
+		assert.Equal(t, name, string(element[4].([]byte)))
+		assert.Equal(t, int64(1), element[3], "%s is optional", name)
+	}
+	assert.Equal(t, int64(len(exported)), metadata[3])
+	rowGroups := metadata[4].([]interface{})
+	require.Len(t, rowGroups, 1)
+	rowGroup := rowGroups[0].(thriftStruct)
+	assert.Equal(t, int64(len(exported)), rowGroup[3])
+
+	columns := make(map[string][]byte, len(header))
+	for i, chunk := range rowGroup[1].([]interface{}) {
+		column := chunk.(thriftStruct)[3].(thriftStruct)
+		assert.Equal(t, header[i], string(column[3].([]interface{})[0].([]byte)))
+		assert.Equal(t, int64(len(exported)), column[5])
+
+		// Each chunk is one uncompressed data page starting at data_page_offset
+		offset, size := int(column[9].(int64)), int(column[7].(int64))
+		require.LessOrEqual(t, offset+size, footerStart)
+		page := &compactDecoder{buf: data[offset : offset+size]}
+		pageHeader := page.structValue()
+		require.NoError(t, page.err)
+		assert.Equal(t, int64(0), pageHeader[1], "data page")
+		assert.Equal(t, int64(len(exported)), pageHeader[5].(thriftStruct)[1])
+		assert.Equal(t, size-page.pos, int(pageHeader[3].(int64)), "%s page size", header[i])
+		columns[header[i]] = data[offset+page.pos : offset+size]
+	}
+
+	// Definition levels mark missing values, which take no space
+	levels, values := splitPage(t, columns["name"])
+	assert.Equal(t, []bool{true, true, true}, levels)
+	for _, product := range exported {
+		length := int(binary.LittleEndian.Uint32(values))
+		assert.Equal(t, product.GetProduct().GetName(), string(values[4:4+length]))
+		values = values[4+length:]
+	}
+	assert.Empty(t, values)
+
+	levels, values = splitPage(t, columns["weight"])
+	assert.Equal(t, []bool{false, true, false}, levels)
+	require.Len(t, values, 8)
+	assert.Equal(t, 1.25, math.Float64frombits(binary.LittleEndian.Uint64(values)))
+
+	// DECIMAL(28, 9) stores the unscaled amount as a 12 byte big-endian integer
+	_, values = splitPage(t, columns["price"])
+	require.Len(t, values, 3*12)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 2, 0x53, 0x73, 0x4d, 0x80}, values[:12], "9.99")
+}
+
+// splitPage decodes the RLE definition levels of a data page and returns
+// them along with the PLAIN encoded values that follow
+func splitPage(t *testing.T, page []byte) ([]bool, []byte) {
+	require.GreaterOrEqual(t, len(page), 4)
+	length := int(binary.LittleEndian.Uint32(page))
+	encoded := page[4 : 4+length]
+	var levels []bool
+	for len(encoded) > 0 {
+		header, n := binary.Uvarint(encoded)
+		require.Greater(t, n, 0)
+		require.Zero(t, header&1, "only RLE runs are written")
+		require.Greater(t, len(encoded), n)
+		for i := uint64(0); i < header>>1; i++ {
+			levels = append(levels, encoded[n] == 1)
+		}
+		encoded = encoded[n+1:]
+	}
+	return levels, page[4+length:]
+}
+
+// thriftStruct holds the fields of a decoded Thrift struct by field id
+type thriftStruct map[int16]interface{}
+
+// compactDecoder is a minimal Thrift compact protocol decoder, written from
+// the protocol specification independently of the writer, for inspecting
+// Parquet metadata. Integers decode to int64, binary to []byte, lists to
+// []interface{} and structs to thriftStruct.
+type compactDecoder struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (d *compactDecoder) fail() {
+	if d.err == nil {
+		d.err = io.ErrUnexpectedEOF
+	}
+	d.pos = len(d.buf)
+}
+
+func (d *compactDecoder) byte() byte {
+	if d.pos >= len(d.buf) {
+		d.fail()
+		return 0
+	}
+	d.pos++
+	return d.buf[d.pos-1]
+}
+
+func (d *compactDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+// varint decodes a zigzag encoded integer
+func (d *compactDecoder) varint() int64 {
+	v := d.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (d *compactDecoder) value(fieldType byte) interface{} {
+	switch fieldType {
+	case 1, 2:
+		return fieldType == 1
+	case 3:
+		return int64(int8(d.byte()))
+	case 4, 5, 6:
+		return d.varint()
+	case 7:
+		if len(d.buf)-d.pos < 8 {
+			d.fail()
+			return 0.0
+		}
+		d.pos += 8
+		return math.Float64frombits(binary.LittleEndian.Uint64(d.buf[d.pos-8:]))
+	case 8:
+		length := int(d.uvarint())
+		if length > len(d.buf)-d.pos {
+			d.fail()
+			return []byte(nil)
+		}
+		d.pos += length
+		return d.buf[d.pos-length : d.pos]
+	case 9, 10:
+		header := d.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(d.uvarint())
+		}
+		list := []interface{}{}
+		for i := 0; i < size && d.err == nil; i++ {
+			list = append(list, d.value(header&0x0f))
+		}
+		return list
+	case 12:
+		return d.structValue()
+	}
+	d.err = io.ErrUnexpectedEOF
+	return nil
+}
+
+func (d *compactDecoder) structValue() thriftStruct {
+	fields := thriftStruct{}
+	var last int16
+	for d.err == nil {
+		header := d.byte()
+		if header == 0 {
+			break
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(d.varint())
+		}
+		fields[id] = d.value(header & 0x0f)
+		last = id
+	}
+	return fields
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"product-microservice/internal/domain"
+	"product-microservice/internal/shipping"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadExampleRates(t *testing.T) *shipping.RateTable {
+	rates, err := shipping.LoadRateTable("../config/shipping_rates.example.json")
+	require.NoError(t, err)
+	return rates
+}
+
+func TestRateTableEstimate(t *testing.T) {
+	rates := loadExampleRates(t)
+	kg := func(value float64) domain.Weight { return domain.Weight{Value: value, Unit: domain.WeightUnitKilogram} }
+
+	// 2 x 0.4 kg is within the second ground bracket; the express price of
+	// 14.99 is higher, so ground is quoted first
+	estimate, err := rates.Estimate("US", "US", []shipping.Item{{Weight: kg(0.4), Quantity: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "domestic", estimate.Zone)
+	assert.InDelta(t, 0.8, estimate.ActualWeightKg, 1e-9)
+	require.Len(t, estimate.Quotes, 2)
+	assert.Equal(t, "ground", estimate.Quotes[0].CarrierCode)
+	assert.Equal(t, domain.Money{Amount: domain.MustParseAmount("8.99"), Currency: "USD"}, estimate.Quotes[0].Price)
+	assert.Equal(t, "express", estimate.Quotes[1].CarrierCode)
+
+	// A light but bulky parcel is billed by its dimensional weight:
+	// 40 x 40 x 40 cm is 12.8 kg at a divisor of 5000, 16 kg at 4000
+	bulky := shipping.Item{
+		Weight:     domain.Weight{Value: 16, Unit: domain.WeightUnitOunce},
+		Dimensions: domain.Dimensions{Length: 40, Width: 40, Height: 40, Unit: domain.LengthUnitCentimeter},
+		Quantity:   1,
+	}
+	estimate, err = rates.Estimate("US", "US", []shipping.Item{bulky})
+	require.NoError(t, err)
+	require.Len(t, estimate.Quotes, 2)
+	assert.InDelta(t, 0.4536, estimate.ActualWeightKg, 1e-4)
+	ground, express := estimate.Quotes[0], estimate.Quotes[1]
+	assert.InDelta(t, 12.8, ground.BillableWeightKg, 1e-9)
+	// 14.99 plus 3 started kilograms above 10 kg at 1.10
+	assert.Equal(t, domain.MustParseAmount("18.29"), ground.Price.Amount)
+	assert.InDelta(t, 16, express.DimensionalWeightKg, 1e-9)
+	// 24.99 plus 11 kilograms above 5 kg at 3.50
+	assert.Equal(t, domain.MustParseAmount("63.49"), express.Price.Amount)
+
+	// Each parcel is billed by its own greater weight: a heavy small parcel
+	// does not make up for a light bulky one
+	dense := shipping.Item{
+		Weight:     kg(10),
+		Dimensions: domain.Dimensions{Length: 10, Width: 10, Height: 10, Unit: domain.LengthUnitCentimeter},
+		Quantity:   1,
+	}
+	estimate, err = rates.Estimate("US", "US", []shipping.Item{dense, bulky})
+	require.NoError(t, err)
+	require.Len(t, estimate.Quotes, 2)
+	assert.InDelta(t, 10.4536, estimate.ActualWeightKg, 1e-4)
+	ground, express = estimate.Quotes[0], estimate.Quotes[1]
+	// 10 kg and 12.8 kg at ground's divisor of 5000
+	assert.InDelta(t, 13, ground.DimensionalWeightKg, 1e-9)
+	assert.InDelta(t, 22.8, ground.BillableWeightKg, 1e-9)
+	// 14.99 plus 13 started kilograms above 10 kg at 1.10
+	assert.Equal(t, domain.MustParseAmount("29.29"), ground.Price.Amount)
+	// 10 kg and 16 kg at express's divisor of 4000
+	assert.InDelta(t, 26, express.BillableWeightKg, 1e-9)
+	// 24.99 plus 21 kilograms above 5 kg at 3.50
+	assert.Equal(t, domain.MustParseAmount("98.49"), express.Price.Amount)
+
+	// Ground does not carry more than 10 kg to Canada and has no rate for Japan
+	estimate, err = rates.Estimate("US", "CA", []shipping.Item{{Weight: kg(11), Quantity: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, "north_america", estimate.Zone)
+	assert.Empty(t, estimate.Quotes)
+	estimate, err = rates.Estimate("", "JP", []shipping.Item{{Weight: kg(1), Quantity: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, "international", estimate.Zone)
+	require.Len(t, estimate.Quotes, 1)
+	assert.Equal(t, "express", estimate.Quotes[0].CarrierCode)
+
+	_, err = rates.Estimate("US", "US", []shipping.Item{{Weight: domain.Weight{Value: 1}, Quantity: 1}})
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+}
+
+func TestLoadRateTableRejectsInconsistentRates(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown currency": `{"currency": "XXY", "zones": [], "carriers": []}`,
+		"unknown zone":     `{"currency": "USD", "zones": [{"name": "a"}], "carriers": [{"code": "c", "dim_divisor": 5000, "rates": [{"zone": "b", "brackets": [{"max_weight_kg": 1, "price": "1"}]}]}]}`,
+		"no divisor":       `{"currency": "USD", "zones": [{"name": "a"}], "carriers": [{"code": "c", "rates": []}]}`,
+		"unsorted":         `{"currency": "USD", "zones": [{"name": "a"}], "carriers": [{"code": "c", "dim_divisor": 5000, "rates": [{"zone": "a", "brackets": [{"max_weight_kg": 2, "price": "1"}, {"max_weight_kg": 2, "price": "2"}]}]}]}`,
+		"unknown field":    `{"currency": "USD", "zone": []}`,
+	} {
+		path := filepath.Join(t.TempDir(), "rates.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := shipping.LoadRateTable(path)
+		assert.Error(t, err, name)
+	}
+}
@@ -3,19 +3,27 @@ package test
 import (
 	"context"
 	"fmt"
+	"net"
 	"product-microservice/internal/domain"
+	"product-microservice/internal/mapper"
 	"product-microservice/internal/repository"
 	"product-microservice/internal/service"
 	"product-microservice/internal/transport/grpc"
+	moneypb "product-microservice/proto/money"
 	pb "product-microservice/proto/subscription"
 
 	// "strconv"
 	"testing"
 
-	// "github.com/google/uuid"
+	"github.com/google/uuid"
 	// "github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -60,6 +68,63 @@ func (m *MockSubscriptionService) DeleteSubscriptionPlan(ctx context.Context, id
     return args.Error(0)
 }
 
+// MockSubscriptionRepository mocks the repository.SubscriptionRepository interface
+type MockSubscriptionRepository struct {
+    mock.Mock
+}
+
+func (m *MockSubscriptionRepository) Save(ctx context.Context, plan *domain.SubscriptionPlan) (*domain.SubscriptionPlan, error) {
+    args := m.Called(ctx, plan)
+    return plan, args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.SubscriptionPlan, error) {
+    args := m.Called(ctx, id)
+    if args.Get(0) != nil {
+        return args.Get(0).(*domain.SubscriptionPlan), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) FindByProductID(ctx context.Context, productID uuid.UUID) ([]*domain.SubscriptionPlan, error) {
+    args := m.Called(ctx, productID)
+    return args.Get(0).([]*domain.SubscriptionPlan), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+    args := m.Called(ctx, id, version)
+    return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) Update(ctx context.Context, subscription *domain.SubscriptionPlan) error {
+    args := m.Called(ctx, subscription)
+    return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) ListAll(ctx context.Context) ([]*domain.SubscriptionPlan, error) {
+    args := m.Called(ctx)
+    return args.Get(0).([]*domain.SubscriptionPlan), args.Error(1)
+}
+
+// startSubscriptionServer serves the handler over an in-memory connection and
+// returns a client for it
+func startSubscriptionServer(t *testing.T, handler *grpc.SubscriptionHandler) pb.SubscriptionServiceClient {
+    listener := bufconn.Listen(1024 * 1024)
+    server := grpclib.NewServer()
+    pb.RegisterSubscriptionServiceServer(server, handler)
+    go server.Serve(listener)
+    t.Cleanup(server.Stop)
+
+    conn, err := grpclib.NewClient("passthrough:///bufnet",
+        grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+        grpclib.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatalf("Failed to dial test server: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return pb.NewSubscriptionServiceClient(conn)
+}
+
 
 
 // SubscriptionTestDatabaseSetUp sets up a PostgreSQL database connection for testing using the existing db and config setup
@@ -106,24 +171,24 @@ func TestCreateSubscriptionIntegration(t *testing.T) {
 	subscriptionPlans := []struct {
 		ProductId string
 		name        string
-		price       float64
+		price       *moneypb.Money
 		duration    int32
 	}{
 		// NOTE: the product id's here are from product table. Product need to exist before you can product create subscriptionPlan.
-		{"e96a46ad-8cb8-4484-b8f4-d72face35f86", "Basic Plan", 9.99, 20},
-		{"b64c7763-eaf7-4581-bef8-d4a13a317ad3", "Standard Plan", 19.99, 40},
+		{"e96a46ad-8cb8-4484-b8f4-d72face35f86", "Basic Plan", usd(9, 990000000), 20},
+		{"b64c7763-eaf7-4581-bef8-d4a13a317ad3", "Standard Plan", usd(19, 990000000), 40},
 	}
 
 	for _, plan := range subscriptionPlans {
 		req := &pb.CreateSubscriptionPlanRequest{
 			ProductId:  plan.ProductId, 
 			PlanName:     plan.name,
-			Price:        float32(plan.price),
+			Price:        plan.price,
 			DurationDays: plan.duration,
 		}
 
 		// Call handler
-		resp, err := handler.CreateSubscription(context.Background(), req)
+		resp, err := handler.CreateSubscriptionPlan(context.Background(), req)
 
 		// Assert no error occurred
 		if err != nil {
@@ -134,7 +199,7 @@ func TestCreateSubscriptionIntegration(t *testing.T) {
 		subscriptionPlan := resp
 
 		// Print success message
-		t.Logf("Successfully created subscription plan: %s with price: %.2f", subscriptionPlan.SubscriptionPlan.GetPlanName(), subscriptionPlan.SubscriptionPlan.GetPrice())
+		t.Logf("Successfully created subscription plan: %s with price: %s", subscriptionPlan.SubscriptionPlan.GetPlanName(), mapper.MoneyFromProto(subscriptionPlan.SubscriptionPlan.GetPrice()))
 	}
 }
 
@@ -144,7 +209,8 @@ func TestGetSubscriptionIntegration(t *testing.T) {
 	db := SubscriptionTestDatabaseSetUp(t)
 	subscriptionRepo := repository.NewSubscriptionRepository(db)
 	service := service.NewSubscriptionService(subscriptionRepo)
-	handler := grpc.NewSubscriptionHandler(service, nil) 
+	handler := grpc.NewSubscriptionHandler(service, nil)
+	handler.AdminToken = "integration"
 
 	// Assume a subscription plan already exists in the database
 	existingSubscriptionID := "32e4182d-a8d6-4c10-9449-5df902cf3b53" 
@@ -155,7 +221,7 @@ func TestGetSubscriptionIntegration(t *testing.T) {
 	}
 
 	// Call the GetSubscriptionPlan handler
-	resp, err := handler.GetSubscriptionPlan(context.Background(), req)
+	resp, err := handler.GetSubscriptionPlan(adminContext, req)
 
 	// Assert no error occurred
 	if err != nil {
@@ -171,10 +237,10 @@ func TestGetSubscriptionIntegration(t *testing.T) {
 	}
 
 	// Log the subscription plan details
-	t.Logf("Successfully fetched subscription plan: ID: %s, Name: %s, Price: %.2f, Duration: %d days",
+	t.Logf("Successfully fetched subscription plan: ID: %s, Name: %s, Price: %s, Duration: %d days",
 		subscriptionPlan.GetId(),
 		subscriptionPlan.GetPlanName(),
-		subscriptionPlan.GetPrice(),
+		mapper.MoneyFromProto(subscriptionPlan.GetPrice()),
 		subscriptionPlan.GetDurationDays(),
 	)
 }
@@ -185,13 +251,14 @@ func TestListSubscriptionsIntegration(t *testing.T) {
 	db := SubscriptionTestDatabaseSetUp(t)
 	subscriptionRepo := repository.NewSubscriptionRepository(db)
 	service := service.NewSubscriptionService(subscriptionRepo)
-	handler := grpc.NewSubscriptionHandler(service, nil) 
+	handler := grpc.NewSubscriptionHandler(service, nil)
+	handler.AdminToken = "integration"
 
 	// Create a gRPC request to list all subscription plans 
 	req := &pb.ListSubscriptionPlansRequest{}
 
 	// Call the ListSubscriptionPlans handler
-	resp, err := handler.ListSubscriptionPlans(context.Background(), req)
+	resp, err := handler.ListSubscriptionPlans(adminContext, req)
 
 	// Assert no error occurred
 	if err != nil {
@@ -205,11 +272,11 @@ func TestListSubscriptionsIntegration(t *testing.T) {
 
 	// Iterate over all subscription plans and log their details
 	for _, subscriptionPlan := range resp.GetSubscriptionPlans() {
-		t.Logf("Subscription Plan: ID: %s, Product ID: %s, Name: %s, Price: %.2f, Duration: %d days",
+		t.Logf("Subscription Plan: ID: %s, Product ID: %s, Name: %s, Price: %s, Duration: %d days",
 			subscriptionPlan.GetId(),
 			subscriptionPlan.GetProductId(),
 			subscriptionPlan.GetPlanName(),
-			subscriptionPlan.GetPrice(),
+			mapper.MoneyFromProto(subscriptionPlan.GetPrice()),
 			subscriptionPlan.GetDurationDays(),
 		)
 	}
@@ -232,14 +299,15 @@ func TestUpdateSubscriptionIntegration(t *testing.T) {
 
     // Step 2: Prepare the update request with new values
     updatedPlanName := "Updated Plan Name"
-    updatedPrice := 99.99
+    updatedPrice := domain.Money{Amount: domain.MustParseAmount("99.99"), Currency: "USD"}
     updatedDurationDays := 30
 
     req := &pb.UpdateSubscriptionPlanRequest{
         Id:          subscription.ID.String(),
         PlanName:    updatedPlanName,
-        Price:       float32(updatedPrice), // Ensuring price is passed as float32
+        Price:       mapper.MoneyToProto(updatedPrice),
         DurationDays: int32(updatedDurationDays),
+        Etag:        domain.FormatETag(subscription.Version),
     }
 
     // Step 3: Call the handler to update the subscription
@@ -256,12 +324,12 @@ func TestUpdateSubscriptionIntegration(t *testing.T) {
 
    // Assert that the updated values are reflected in the database
 	assert.Equal(t, updatedPlanName, updatedSubscription.PlanName)
-	assert.Equal(t, float32(updatedPrice), float32(updatedSubscription.Price))  // Cast to float32
+	assert.Equal(t, updatedPrice, updatedSubscription.Price)
 	assert.Equal(t, int32(updatedDurationDays), int32(updatedSubscription.Duration))
 
 	// Optionally, assert the response values as well (check that the updated values match)
 	assert.Equal(t, updatedPlanName, updatedSubscriptionResp.GetPlanName())
-	assert.Equal(t, float32(updatedPrice), updatedSubscriptionResp.GetPrice())  // Cast to float32
+	assert.Equal(t, updatedPrice, mapper.MoneyFromProto(updatedSubscriptionResp.GetPrice()))
 	assert.Equal(t, int32(updatedDurationDays), int32(updatedSubscriptionResp.GetDurationDays()))
 
 }
@@ -284,10 +352,10 @@ func TestDeleteSubscriptionIntegration(t *testing.T) {
     }
 
     // Step 2: Create a delete request for the subscription ID
-    req := &pb.DeleteSubscriptionPlanRequest{Id: subscription.ID.String()} 
+    req := &pb.DeleteSubscriptionPlanRequest{Id: subscription.ID.String(), Etag: domain.FormatETag(subscription.Version)}
 
     // Step 3: Call the handler to delete the subscription
-    _, err := handler.DeleteSubscription(context.Background(), req)
+    _, err := handler.DeleteSubscriptionPlan(context.Background(), req)
     if err != nil {
         t.Fatalf("Failed to delete subscription: %v", err)
     }
@@ -304,3 +372,58 @@ func TestDeleteSubscriptionIntegration(t *testing.T) {
 
 
 
+
+func TestSubscriptionPlanRPCs(t *testing.T) {
+    productRepo := new(MockProductRepository)
+    subscriptionRepo := new(MockSubscriptionRepository)
+    client := startSubscriptionServer(t, grpc.NewSubscriptionHandler(
+        service.NewSubscriptionService(subscriptionRepo), service.NewProductService(productRepo)))
+
+    product := &domain.Product{ID: uuid.New(), Name: "Streaming", Status: domain.ProductStatusPublished}
+    productRepo.On("FindById", product.ID.String()).Return(product, nil)
+    productRepo.On("GetByID", product.ID).Return(product, nil)
+    subscriptionRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+    created, err := client.CreateSubscriptionPlan(context.Background(), &pb.CreateSubscriptionPlanRequest{
+        ProductId: product.ID.String(), PlanName: "Basic", Price: usd(9, 990000000), DurationDays: 30,
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, product.ID.String(), created.GetSubscriptionPlan().GetProductId())
+    assert.Equal(t, `W/"1"`, created.GetSubscriptionPlan().GetEtag())
+    assert.Equal(t, int32(990000000), created.GetSubscriptionPlan().GetPrice().GetNanos())
+
+    // Prices need a known currency and must be positive
+    for _, price := range []*moneypb.Money{{Units: 5}, {CurrencyCode: "ABC", Units: 5}, usd(0, 0), usd(-1, 0)} {
+        _, err = client.CreateSubscriptionPlan(context.Background(), &pb.CreateSubscriptionPlanRequest{
+            ProductId: product.ID.String(), PlanName: "Basic", Price: price, DurationDays: 30,
+        })
+        assert.Equal(t, codes.InvalidArgument, status.Code(err), price.String())
+    }
+
+    plan := &domain.SubscriptionPlan{ID: uuid.New(), ProductID: product.ID, PlanName: "Basic", Duration: 30, Price: domain.Money{Amount: domain.MustParseAmount("9.99"), Currency: "USD"}, Version: 1}
+    subscriptionRepo.On("FindByProductID", mock.Anything, product.ID).Return([]*domain.SubscriptionPlan{plan}, nil)
+    listed, err := client.ListSubscriptionPlans(context.Background(), &pb.ListSubscriptionPlansRequest{ProductId: product.ID.String()})
+    assert.NoError(t, err)
+    assert.Len(t, listed.GetSubscriptionPlans(), 1)
+    subscriptionRepo.AssertNotCalled(t, "ListAll", mock.Anything)
+
+    // The plans of products hidden from the caller are not found or listed
+    draft := &domain.Product{ID: uuid.New(), Name: "Music", Status: domain.ProductStatusDraft}
+    draftPlan := &domain.SubscriptionPlan{ID: uuid.New(), ProductID: draft.ID, PlanName: "Early", Duration: 30, Price: plan.Price, Version: 1}
+    productRepo.On("GetByID", draft.ID).Return(draft, nil)
+    subscriptionRepo.On("FindByID", mock.Anything, draftPlan.ID).Return(draftPlan, nil)
+    subscriptionRepo.On("ListAll", mock.Anything).Return([]*domain.SubscriptionPlan{plan, draftPlan}, nil)
+    _, err = client.GetSubscriptionPlan(context.Background(), &pb.GetSubscriptionPlanRequest{Id: draftPlan.ID.String()})
+    assert.Equal(t, codes.NotFound, status.Code(err))
+    _, err = client.ListSubscriptionPlans(context.Background(), &pb.ListSubscriptionPlansRequest{ProductId: draft.ID.String()})
+    assert.Equal(t, codes.NotFound, status.Code(err))
+    listed, err = client.ListSubscriptionPlans(context.Background(), &pb.ListSubscriptionPlansRequest{})
+    assert.NoError(t, err)
+    if assert.Len(t, listed.GetSubscriptionPlans(), 1) {
+        assert.Equal(t, plan.ID.String(), listed.GetSubscriptionPlans()[0].GetId())
+    }
+
+    subscriptionRepo.On("Delete", mock.Anything, plan.ID, int64(1)).Return(nil)
+    _, err = client.DeleteSubscriptionPlan(context.Background(), &pb.DeleteSubscriptionPlanRequest{Id: plan.ID.String(), Etag: `W/"1"`})
+    assert.NoError(t, err)
+}